@@ -4,4 +4,5 @@ set -e
 
 echo "current user $(whoami)"
 
-./cthulhu-mythos-tools
+# exec replaces the shell, so the app runs as PID 1 and receives SIGTERM for the graceful shutdown.
+exec ./cthulhu-mythos-tools "$@"
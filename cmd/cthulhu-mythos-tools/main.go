@@ -2,29 +2,38 @@
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"flag"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	log "github.com/obalunenko/logger"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/obalunenko/cthulhu-mythos-tools/internal/config"
 	"github.com/obalunenko/cthulhu-mythos-tools/internal/service"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/tlscert"
 )
 
 var errSignal = errors.New("received signal")
 
 func main() {
+	configPath := flag.String("config", "", "Path to YAML or JSON config file, environment variables override its values")
+
+	flag.Parse()
+
 	signals := make(chan os.Signal, 1)
 
 	ctx := context.Background()
 
-	cfg, err := config.Load(ctx)
+	cfg, err := config.Load(ctx, *configPath)
 	if err != nil {
 		log.WithError(ctx, err).Fatal("Failed to load config")
 	}
@@ -40,11 +49,67 @@ func main() {
 
 	printVersion(ctx)
 
-	router := service.NewRouter()
+	db, err := newStorage(cfg)
+	if err != nil {
+		log.WithError(ctx, err).Fatal("Failed to open storage")
+	}
+
+	// os.Exit and Fatal skip deferred calls, so storage is closed explicitly on every exit path.
+	closeStorage := func() {
+		if err := db.Close(); err != nil {
+			log.WithError(ctx, err).Error("Failed to close storage")
+		}
+	}
+
+	log.WithFields(ctx, log.Fields{
+		"type": cfg.Storage.Type,
+		"path": cfg.Storage.Path,
+	}).Info("Storage opened")
+
+	// Session cookie of HTTPS server is never sent over plain HTTP.
+	router := service.NewRouter(db, service.AuthParams{
+		Enabled:      cfg.Auth.Enabled,
+		SessionTTL:   time.Duration(cfg.Auth.SessionTTL),
+		SecureCookie: cfg.Auth.SecureCookie || cfg.TLS.Enabled,
+	})
 
 	server := &http.Server{
-		Addr:    net.JoinHostPort(cfg.HTTP.Host, cfg.HTTP.Port),
-		Handler: router,
+		Addr:              net.JoinHostPort(cfg.HTTP.Host, string(cfg.HTTP.Port)),
+		Handler:           router,
+		ReadHeaderTimeout: time.Duration(cfg.HTTP.ReadHeaderTimeout),
+		ReadTimeout:       time.Duration(cfg.HTTP.ReadTimeout),
+		WriteTimeout:      time.Duration(cfg.HTTP.WriteTimeout),
+		IdleTimeout:       time.Duration(cfg.HTTP.IdleTimeout),
+	}
+
+	var (
+		certs    *tlscert.Reloader
+		redirect *http.Server
+	)
+
+	if cfg.TLS.Enabled {
+		certs, err = tlscert.NewReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			closeStorage()
+
+			log.WithError(ctx, err).Fatal("Failed to load TLS certificate")
+		}
+
+		server.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certs.GetCertificate,
+		}
+
+		if cfg.TLS.RedirectPort != "" {
+			redirect = &http.Server{
+				Addr:              net.JoinHostPort(cfg.HTTP.Host, string(cfg.TLS.RedirectPort)),
+				Handler:           service.NewHTTPSRedirect(string(cfg.HTTP.Port)),
+				ReadHeaderTimeout: time.Duration(cfg.HTTP.ReadHeaderTimeout),
+				ReadTimeout:       time.Duration(cfg.HTTP.ReadTimeout),
+				WriteTimeout:      time.Duration(cfg.HTTP.WriteTimeout),
+				IdleTimeout:       time.Duration(cfg.HTTP.IdleTimeout),
+			}
+		}
 	}
 
 	server.RegisterOnShutdown(func() {
@@ -59,6 +124,8 @@ func main() {
 	defer func() {
 		const msg = "Exit"
 
+		closeStorage()
+
 		var code int
 
 		err := context.Cause(ctx)
@@ -91,32 +158,114 @@ func main() {
 
 	g, ctx := errgroup.WithContext(ctx)
 
+	grace := time.Duration(cfg.HTTP.ShutdownTimeout)
+
 	g.Go(func() error {
-		<-ctx.Done()
-		return server.Shutdown(ctx)
+		return shutdown(ctx, server, grace)
 	})
 
 	g.Go(func() error {
-		log.WithFields(ctx, log.Fields{
-			"address": server.Addr,
-		}).Info("Server started")
+		return serve(ctx, server)
+	})
 
-		if err = server.ListenAndServe(); err != nil {
-			if !errors.Is(http.ErrServerClosed, err) {
-				log.WithError(ctx, err).Error("Failed to start server")
+	if redirect != nil {
+		g.Go(func() error {
+			return shutdown(ctx, redirect, grace)
+		})
 
-				return err
-			}
+		g.Go(func() error {
+			return serve(ctx, redirect)
+		})
+	}
 
-			log.Info(ctx, "Server stopped gracefully")
+	if certs != nil {
+		g.Go(func() error {
+			reloadCertificates(ctx, certs)
 
 			return nil
-		}
-
-		return nil
-	})
+		})
+	}
 
+	// Cause of the group context is the error, the deferred exit closes storage and exits with code 1.
 	if err = g.Wait(); err != nil {
-		log.WithError(ctx, err).Fatal("Service failed")
+		log.WithError(ctx, err).Error("Service failed")
+	}
+}
+
+// serve accepts connections until server is shut down, server with TLS config serves HTTPS.
+func serve(ctx context.Context, server *http.Server) error {
+	log.WithFields(ctx, log.Fields{
+		"address": server.Addr,
+		"tls":     server.TLSConfig != nil,
+	}).Info("Server started")
+
+	var err error
+
+	if server.TLSConfig != nil {
+		// Certificate is provided by TLSConfig.GetCertificate.
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.WithError(ctx, err).Error("Failed to start server")
+
+		return err
+	}
+
+	log.WithField(ctx, "address", server.Addr).Info("Server stopped gracefully")
+
+	return nil
+}
+
+// shutdown waits for ctx to be done and gracefully shuts the server down,
+// in-flight requests get the grace period to complete.
+func shutdown(ctx context.Context, server *http.Server, grace time.Duration) error {
+	<-ctx.Done()
+
+	// ctx is already cancelled here, so deadline is set on a context that is not.
+	sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), grace)
+	defer cancel()
+
+	if err := server.Shutdown(sctx); err != nil {
+		return fmt.Errorf("shutdown server %s: %w", server.Addr, err)
+	}
+
+	return nil
+}
+
+// reloadCertificates reloads TLS certificate files on SIGHUP until ctx is done.
+// Failed reload is logged and the server keeps the previous certificate.
+func reloadCertificates(ctx context.Context, certs *tlscert.Reloader) {
+	hup := make(chan os.Signal, 1)
+
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := certs.Reload(); err != nil {
+				log.WithError(ctx, err).Error("Failed to reload TLS certificate")
+
+				continue
+			}
+
+			log.Info(ctx, "TLS certificate reloaded")
+		}
+	}
+}
+
+func newStorage(cfg *config.Config) (storage.Storage, error) {
+	switch cfg.Storage.Type {
+	case config.StorageMemory:
+		return storage.NewInMemoryStorage(), nil
+	case config.StorageBolt:
+		return storage.NewBoltStorage(cfg.Storage.Path)
+	default:
+		return nil, fmt.Errorf("unknown storage type %q", cfg.Storage.Type)
 	}
 }
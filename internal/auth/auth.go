@@ -0,0 +1,142 @@
+// Package auth implements local user accounts with keeper and player roles and login sessions.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrInvalidUser is returned when user account details are not valid.
+	ErrInvalidUser = errors.New("invalid user")
+	// ErrInvalidCredentials is returned when user name or password do not match.
+	ErrInvalidCredentials = errors.New("invalid user name or password")
+)
+
+// Role of the user.
+type Role string
+
+const (
+	// RoleKeeper runs the game: sees and edits everything in their campaigns and manages accounts.
+	RoleKeeper Role = "keeper"
+	// RolePlayer sees and edits only their own investigators.
+	RolePlayer Role = "player"
+)
+
+// ParseRole returns role by its name.
+func ParseRole(s string) (Role, error) {
+	switch r := Role(strings.ToLower(strings.TrimSpace(s))); r {
+	case RoleKeeper, RolePlayer:
+		return r, nil
+	default:
+		return "", fmt.Errorf("%w: unknown role %q", ErrInvalidUser, s)
+	}
+}
+
+const (
+	// MinPasswordLength is the shortest accepted password.
+	MinPasswordLength = 8
+	// maxPasswordLength is a bcrypt input limit.
+	maxPasswordLength = 72
+)
+
+// User is a local account.
+type User struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	PasswordHash []byte    `json:"password_hash"`
+	Role         Role      `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewUser creates user with bcrypt hashed password.
+func NewUser(id, name, password string, role Role, now time.Time) (User, error) {
+	name = strings.TrimSpace(name)
+
+	switch {
+	case name == "":
+		return User{}, fmt.Errorf("%w: empty name", ErrInvalidUser)
+	case len(password) < MinPasswordLength:
+		return User{}, fmt.Errorf("%w: password should be at least %d characters", ErrInvalidUser, MinPasswordLength)
+	case len(password) > maxPasswordLength:
+		return User{}, fmt.Errorf("%w: password should be at most %d bytes", ErrInvalidUser, maxPasswordLength)
+	case role != RoleKeeper && role != RolePlayer:
+		return User{}, fmt.Errorf("%w: unknown role %q", ErrInvalidUser, role)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return User{}, fmt.Errorf("hash password: %w", err)
+	}
+
+	return User{
+		ID:           id,
+		Name:         name,
+		PasswordHash: hash,
+		Role:         role,
+		CreatedAt:    now,
+	}, nil
+}
+
+// IsKeeper reports whether user has the keeper role.
+func (u User) IsKeeper() bool {
+	return u.Role == RoleKeeper
+}
+
+// CheckPassword returns ErrInvalidCredentials when password does not match.
+func (u User) CheckPassword(password string) error {
+	if err := bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(password)); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	return nil
+}
+
+const tokenSize = 32
+
+// Session is a login session. Only a hash of the session token is stored,
+// the token itself is kept by the client in a cookie.
+type Session struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewSession creates session of the user valid for ttl. It returns the session and its token.
+func NewSession(userID string, now time.Time, ttl time.Duration) (Session, string, error) {
+	buf := make([]byte, tokenSize)
+
+	if _, err := rand.Read(buf); err != nil {
+		return Session{}, "", fmt.Errorf("generate session token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	return Session{
+		ID:        SessionID(token),
+		UserID:    userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, token, nil
+}
+
+// SessionID returns ID of the session with the token.
+func SessionID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Expired reports whether session is expired at now.
+func (s Session) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
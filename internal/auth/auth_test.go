@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Role
+		wantErr bool
+	}{
+		{in: "keeper", want: RoleKeeper},
+		{in: " Player ", want: RolePlayer},
+		{in: "admin", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRole(tt.in)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidUser)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewUser(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		userName string
+		password string
+		role     Role
+		wantErr  bool
+	}{
+		{name: "keeper", userName: " Anna ", password: "correct horse", role: RoleKeeper},
+		{name: "player", userName: "Boris", password: "12345678", role: RolePlayer},
+		{name: "empty name", userName: " ", password: "12345678", role: RolePlayer, wantErr: true},
+		{name: "short password", userName: "Boris", password: "1234567", role: RolePlayer, wantErr: true},
+		{name: "long password", userName: "Boris", password: strings.Repeat("x", 73), role: RolePlayer, wantErr: true},
+		{name: "unknown role", userName: "Boris", password: "12345678", role: "admin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := NewUser("id", tt.userName, tt.password, tt.role, now)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidUser)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, "id", u.ID)
+			assert.Equal(t, strings.TrimSpace(tt.userName), u.Name)
+			assert.Equal(t, tt.role, u.Role)
+			assert.Equal(t, now, u.CreatedAt)
+			assert.NotContains(t, string(u.PasswordHash), tt.password)
+
+			require.NoError(t, u.CheckPassword(tt.password))
+			require.ErrorIs(t, u.CheckPassword(tt.password+"!"), ErrInvalidCredentials)
+		})
+	}
+}
+
+func TestNewSession(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, token, err := NewSession("user", now, time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, "user", s.UserID)
+	assert.Equal(t, SessionID(token), s.ID)
+	assert.NotEqual(t, token, s.ID)
+	assert.False(t, s.Expired(now.Add(59*time.Minute)))
+	assert.True(t, s.Expired(now.Add(time.Hour)))
+
+	other, otherToken, err := NewSession("user", now, time.Hour)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, token, otherToken)
+	assert.NotEqual(t, s.ID, other.ID)
+}
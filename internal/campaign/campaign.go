@@ -0,0 +1,130 @@
+// Package campaign groups investigators and NPCs of the same game into campaigns.
+package campaign
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+)
+
+var (
+	// ErrInvalidCampaign is returned when campaign details are not valid.
+	ErrInvalidCampaign = errors.New("invalid campaign")
+	// ErrUnknownMember is returned when campaign has no member with given character ID.
+	ErrUnknownMember = errors.New("unknown campaign member")
+)
+
+// Member is a stored character taking part in the campaign.
+type Member struct {
+	CharacterID string `json:"character_id"`
+	// NPC marks characters played by the Keeper.
+	NPC bool `json:"npc,omitempty"`
+}
+
+// Campaign is a series of game sessions played by the same group.
+type Campaign struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name"`
+	Era     character.Era `json:"era"`
+	Keeper  string        `json:"keeper,omitempty"`
+	Notes   string        `json:"notes,omitempty"`
+	Members []Member      `json:"members,omitempty"`
+	// KeeperID is ID of the user running the campaign, empty for campaigns created without authentication.
+	KeeperID string `json:"keeper_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// New creates campaign without members.
+func New(id, name string, era character.Era, keeper, notes string, now time.Time) (Campaign, error) {
+	c := Campaign{
+		ID:        id,
+		CreatedAt: now,
+	}
+
+	if err := c.SetDetails(name, era, keeper, notes); err != nil {
+		return Campaign{}, err
+	}
+
+	return c, nil
+}
+
+// SetDetails changes campaign name, era, keeper and notes. Empty era means classic one.
+func (c *Campaign) SetDetails(name string, era character.Era, keeper, notes string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidCampaign)
+	}
+
+	switch era {
+	case "":
+		era = character.EraClassic
+	case character.EraClassic, character.EraModern:
+	default:
+		return fmt.Errorf("%w: unknown era %q", ErrInvalidCampaign, era)
+	}
+
+	c.Name = name
+	c.Era = era
+	c.Keeper = strings.TrimSpace(keeper)
+	c.Notes = strings.TrimSpace(notes)
+
+	return nil
+}
+
+// Member returns campaign member by character ID.
+func (c Campaign) Member(characterID string) (Member, bool) {
+	idx := c.memberIndex(characterID)
+	if idx < 0 {
+		return Member{}, false
+	}
+
+	return c.Members[idx], true
+}
+
+// Has reports whether character is a member of the campaign.
+func (c Campaign) Has(characterID string) bool {
+	return c.memberIndex(characterID) >= 0
+}
+
+// Join adds character to the campaign. Joining character that is already a member changes its NPC flag.
+func (c *Campaign) Join(characterID string, npc bool) error {
+	if strings.TrimSpace(characterID) == "" {
+		return fmt.Errorf("%w: empty character id", ErrInvalidCampaign)
+	}
+
+	if idx := c.memberIndex(characterID); idx >= 0 {
+		c.Members[idx].NPC = npc
+
+		return nil
+	}
+
+	c.Members = append(c.Members, Member{
+		CharacterID: characterID,
+		NPC:         npc,
+	})
+
+	return nil
+}
+
+// Leave removes character from the campaign.
+func (c *Campaign) Leave(characterID string) error {
+	idx := c.memberIndex(characterID)
+	if idx < 0 {
+		return fmt.Errorf("%w: %q", ErrUnknownMember, characterID)
+	}
+
+	c.Members = slices.Delete(c.Members, idx, idx+1)
+
+	return nil
+}
+
+func (c Campaign) memberIndex(characterID string) int {
+	return slices.IndexFunc(c.Members, func(m Member) bool {
+		return m.CharacterID == characterID
+	})
+}
@@ -0,0 +1,97 @@
+package campaign
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+)
+
+func TestNew(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		title   string
+		era     character.Era
+		want    Campaign
+		wantErr bool
+	}{
+		{
+			name:  "classic by default",
+			title: " Masks of Nyarlathotep ",
+			want: Campaign{
+				ID:        "id",
+				Name:      "Masks of Nyarlathotep",
+				Era:       character.EraClassic,
+				Keeper:    "Anna",
+				Notes:     "Peru first",
+				CreatedAt: now,
+			},
+		},
+		{
+			name:  "modern",
+			title: "Delta Green",
+			era:   character.EraModern,
+			want: Campaign{
+				ID:        "id",
+				Name:      "Delta Green",
+				Era:       character.EraModern,
+				Keeper:    "Anna",
+				Notes:     "Peru first",
+				CreatedAt: now,
+			},
+		},
+		{
+			name:    "empty name",
+			title:   " ",
+			wantErr: true,
+		},
+		{
+			name:    "unknown era",
+			title:   "Pulp",
+			era:     "pulp",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New("id", tt.title, tt.era, " Anna", "Peru first ", now)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidCampaign)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCampaign_Members(t *testing.T) {
+	c, err := New("id", "Masks", character.EraClassic, "", "", time.Now())
+	require.NoError(t, err)
+
+	require.NoError(t, c.Join("inv", false))
+	require.NoError(t, c.Join("npc", false))
+	require.NoError(t, c.Join("npc", true))
+	require.ErrorIs(t, c.Join("", false), ErrInvalidCampaign)
+
+	assert.Equal(t, []Member{{CharacterID: "inv"}, {CharacterID: "npc", NPC: true}}, c.Members)
+	assert.True(t, c.Has("inv"))
+
+	m, ok := c.Member("npc")
+	require.True(t, ok)
+	assert.True(t, m.NPC)
+
+	require.NoError(t, c.Leave("inv"))
+	require.ErrorIs(t, c.Leave("inv"), ErrUnknownMember)
+
+	assert.False(t, c.Has("inv"))
+	assert.Equal(t, []Member{{CharacterID: "npc", NPC: true}}, c.Members)
+}
@@ -0,0 +1,95 @@
+package character
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Value is a percentile value together with its half and fifth used for Hard and Extreme rolls.
+type Value struct {
+	Full  int
+	Half  int
+	Fifth int
+}
+
+// NewValue makes Value from the full percentile value.
+func NewValue(v int) Value {
+	return Value{
+		Full:  v,
+		Half:  v / 2,
+		Fifth: v / 5,
+	}
+}
+
+// Stats is a typed representation of the investigator core characteristics.
+type Stats struct {
+	STR  Value
+	CON  Value
+	SIZ  Value
+	DEX  Value
+	APP  Value
+	INT  Value
+	POW  Value
+	EDU  Value
+	Luck Value
+}
+
+// Stats parses core characteristics into typed values.
+func (c Characteristics) Stats() (Stats, error) {
+	var errs error
+
+	parse := func(name, raw string) Value {
+		v, err := ParseNumber(raw)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("%s: %w", name, err))
+		}
+
+		return NewValue(v)
+	}
+
+	s := Stats{
+		STR:  parse("STR", c.Str),
+		CON:  parse("CON", c.Con),
+		SIZ:  parse("SIZ", c.Siz),
+		DEX:  parse("DEX", c.Dex),
+		APP:  parse("APP", c.App),
+		INT:  parse("INT", c.Int),
+		POW:  parse("POW", c.Pow),
+		EDU:  parse("EDU", c.Edu),
+		Luck: parse("Luck", c.Luck),
+	}
+
+	if errs != nil {
+		return Stats{}, errs
+	}
+
+	return s, nil
+}
+
+// Values returns typed skill values. Half and Fifth are recalculated from the full value.
+func (s SkillValues) Values() (Value, error) {
+	v, err := ParseNumber(s.Value)
+	if err != nil {
+		return Value{}, err
+	}
+
+	return NewValue(v), nil
+}
+
+// ParseNumber parses numeric sheet value.
+// Empty values are treated as zero, as Dhole's House exports them for unused fields.
+func ParseNumber(raw string) (int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", raw)
+	}
+
+	return v, nil
+}
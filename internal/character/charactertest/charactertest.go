@@ -0,0 +1,30 @@
+// Package charactertest provides investigator fixtures for tests.
+package charactertest
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+)
+
+// LoadInvestigator returns test investigator from character/testdata/character.json:
+// DEX 55, CON 45, INT 60, HP 10, Sanity 40, MOV 7.
+func LoadInvestigator(tb testing.TB) character.InvestigatorClass {
+	tb.Helper()
+
+	_, file, _, ok := runtime.Caller(0)
+	require.True(tb, ok, "failed to locate fixtures")
+
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(file), "..", "testdata", "character.json"))
+	require.NoError(tb, err)
+
+	inv, err := character.UnmarshalInvestigator(data)
+	require.NoError(tb, err)
+
+	return inv.Investigator
+}
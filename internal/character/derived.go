@@ -0,0 +1,150 @@
+package character
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DamageBonusNone is a damage bonus value when no bonus applies.
+const DamageBonusNone = "None"
+
+// Derived holds attributes calculated from the core characteristics.
+type Derived struct {
+	HitPoints   int
+	MagicPoints int
+	Sanity      int
+	Move        int
+	DamageBonus string
+	Build       int
+}
+
+// Derive calculates derived attributes per Call of Cthulhu 7e rules.
+func Derive(s Stats, age int) Derived {
+	db, build := damageBonusAndBuild(s.STR.Full + s.SIZ.Full)
+
+	return Derived{
+		HitPoints:   (s.CON.Full + s.SIZ.Full) / 10,
+		MagicPoints: s.POW.Full / 5,
+		Sanity:      s.POW.Full,
+		Move:        moveRate(s, age),
+		DamageBonus: db,
+		Build:       build,
+	}
+}
+
+func moveRate(s Stats, age int) int {
+	mov := 8
+
+	switch {
+	case s.DEX.Full < s.SIZ.Full && s.STR.Full < s.SIZ.Full:
+		mov = 7
+	case s.DEX.Full > s.SIZ.Full && s.STR.Full > s.SIZ.Full:
+		mov = 9
+	}
+
+	if age >= 40 {
+		// -1 for each decade starting from 40s, up to -5 for 80s and older.
+		mov -= min((age-30)/10, 5)
+	}
+
+	return mov
+}
+
+func damageBonusAndBuild(strSiz int) (string, int) {
+	switch {
+	case strSiz <= 64:
+		return "-2", -2
+	case strSiz <= 84:
+		return "-1", -1
+	case strSiz <= 124:
+		return DamageBonusNone, 0
+	case strSiz <= 164:
+		return "+1D4", 1
+	case strSiz <= 204:
+		return "+1D6", 2
+	}
+
+	// +2D6 for 205-284 and additional +1D6 for each 80 points or part thereof.
+	dice := 2 + (strSiz-205)/80
+
+	return fmt.Sprintf("+%dD6", dice), dice + 1
+}
+
+// Mismatch describes stored derived value that disagrees with the rules.
+type Mismatch struct {
+	Field    string
+	Stored   string
+	Expected string
+}
+
+// DerivedMismatches compares stored derived attributes with calculated ones.
+func (i InvestigatorClass) DerivedMismatches() ([]Mismatch, error) {
+	stats, err := i.Characteristics.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	age, err := ParseNumber(i.PersonalDetails.Age)
+	if err != nil {
+		return nil, fmt.Errorf("age: %w", err)
+	}
+
+	d := Derive(stats, age)
+
+	var res []Mismatch
+
+	checkNum := func(field, stored string, expected int) {
+		v, err := ParseNumber(stored)
+		if err == nil && v == expected {
+			return
+		}
+
+		res = append(res, Mismatch{
+			Field:    field,
+			Stored:   stored,
+			Expected: strconv.Itoa(expected),
+		})
+	}
+
+	checkDB := func(field, stored string) {
+		if NormalizeDamageBonus(stored) == d.DamageBonus {
+			return
+		}
+
+		res = append(res, Mismatch{
+			Field:    field,
+			Stored:   stored,
+			Expected: d.DamageBonus,
+		})
+	}
+
+	c := i.Characteristics
+
+	checkNum("Characteristics.HitPtsMax", c.HitPtsMax, d.HitPoints)
+	checkNum("Characteristics.MagicPtsMax", c.MagicPtsMax, d.MagicPoints)
+	checkNum("Characteristics.SanityStart", c.SanityStart, d.Sanity)
+	checkNum("Characteristics.Move", c.Move, d.Move)
+	checkNum("Characteristics.Build", c.Build, d.Build)
+	checkDB("Characteristics.DamageBonus", c.DamageBonus)
+	checkNum("Combat.Build", i.Combat.Build, d.Build)
+	checkDB("Combat.DamageBonus", i.Combat.DamageBonus)
+
+	return res, nil
+}
+
+// NormalizeDamageBonus brings damage bonus notation to the form returned by Derive.
+func NormalizeDamageBonus(db string) string {
+	db = strings.ToUpper(strings.ReplaceAll(db, " ", ""))
+
+	switch db {
+	case "", "0", "+0", "NONE":
+		return DamageBonusNone
+	}
+
+	if !strings.HasPrefix(db, "-") && !strings.HasPrefix(db, "+") {
+		db = "+" + db
+	}
+
+	return db
+}
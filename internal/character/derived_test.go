@@ -0,0 +1,130 @@
+package character
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDerive(t *testing.T) {
+	stats := func(str, con, siz, dex, pow int) Stats {
+		return Stats{
+			STR: NewValue(str),
+			CON: NewValue(con),
+			SIZ: NewValue(siz),
+			DEX: NewValue(dex),
+			POW: NewValue(pow),
+		}
+	}
+
+	tests := []struct {
+		name  string
+		stats Stats
+		age   int
+		want  Derived
+	}{
+		{
+			name:  "testdata investigator",
+			stats: stats(35, 45, 60, 55, 40),
+			age:   34,
+			want: Derived{
+				HitPoints:   10,
+				MagicPoints: 8,
+				Sanity:      40,
+				Move:        7,
+				DamageBonus: DamageBonusNone,
+				Build:       0,
+			},
+		},
+		{
+			name:  "strong and fast",
+			stats: stats(80, 70, 60, 75, 65),
+			age:   25,
+			want: Derived{
+				HitPoints:   13,
+				MagicPoints: 13,
+				Sanity:      65,
+				Move:        9,
+				DamageBonus: "+1D4",
+				Build:       1,
+			},
+		},
+		{
+			name:  "elder",
+			stats: stats(40, 50, 50, 50, 50),
+			age:   72,
+			want: Derived{
+				HitPoints:   10,
+				MagicPoints: 10,
+				Sanity:      50,
+				Move:        4,
+				DamageBonus: DamageBonusNone,
+				Build:       0,
+			},
+		},
+		{
+			name:  "weak",
+			stats: stats(15, 40, 40, 60, 30),
+			age:   18,
+			want: Derived{
+				HitPoints:   8,
+				MagicPoints: 6,
+				Sanity:      30,
+				Move:        8,
+				DamageBonus: "-2",
+				Build:       -2,
+			},
+		},
+		{
+			name:  "monster",
+			stats: stats(200, 100, 180, 50, 50),
+			age:   0,
+			want: Derived{
+				HitPoints:   28,
+				MagicPoints: 10,
+				Sanity:      50,
+				Move:        8,
+				DamageBonus: "+4D6",
+				Build:       5,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Derive(tt.stats, tt.age))
+		})
+	}
+}
+
+func TestInvestigatorClass_DerivedMismatches(t *testing.T) {
+	inv, err := UnmarshalInvestigator(bytesFromFilePath(t, filepath.Join("testdata", "character.json")))
+	require.NoError(t, err)
+
+	got, err := inv.Investigator.DerivedMismatches()
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	inv.Investigator.Characteristics.HitPtsMax = "12"
+	inv.Investigator.Combat.DamageBonus = "+1D4"
+
+	got, err = inv.Investigator.DerivedMismatches()
+	require.NoError(t, err)
+	assert.Equal(t, []Mismatch{
+		{Field: "Characteristics.HitPtsMax", Stored: "12", Expected: "10"},
+		{Field: "Combat.DamageBonus", Stored: "+1D4", Expected: DamageBonusNone},
+	}, got)
+}
+
+func TestCharacteristics_Stats(t *testing.T) {
+	_, err := Characteristics{Str: "abc", Dex: "x"}.Stats()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "STR")
+	assert.ErrorContains(t, err, "DEX")
+
+	s, err := Characteristics{Str: "55"}.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, Value{Full: 55, Half: 27, Fifth: 11}, s.STR)
+}
@@ -0,0 +1,172 @@
+package character
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+// ErrNotImprovable is returned when skill could not be improved with experience.
+var ErrNotImprovable = errors.New("skill could not be improved with experience")
+
+const (
+	// improvementAlways is a roll above which improvement check always succeeds.
+	improvementAlways = 95
+	// masteryThreshold is a skill value that rewards investigator with Sanity when reached.
+	masteryThreshold = 90
+	maxSkillValue    = 99
+
+	improvementGain = "1D10"
+	masteryReward   = "2D6"
+)
+
+// Improvable reports whether skill could be ticked and improved in the development phase.
+// Cthulhu Mythos and Credit Rating are never improved with experience.
+func (s Skill) Improvable() bool {
+	return !strings.EqualFold(s.Name, "Cthulhu Mythos") && !strings.EqualFold(s.Name, "Credit Rating")
+}
+
+// Tick marks skill as used successfully. It returns false when the skill was already ticked.
+func (i *InvestigatorClass) Tick(name string) (bool, error) {
+	idx := i.skillIndex(name)
+	if idx < 0 {
+		return false, fmt.Errorf("%w: %q", ErrUnknownCheck, name)
+	}
+
+	s := &i.Skills.Skill[idx]
+
+	if !s.Improvable() {
+		return false, fmt.Errorf("%w: %s", ErrNotImprovable, s.FullName())
+	}
+
+	if s.Ticked {
+		return false, nil
+	}
+
+	s.Ticked = true
+
+	return true, nil
+}
+
+// Improvement is a result of a single skill improvement check.
+type Improvement struct {
+	Skill string
+	Roll  int
+	From  int
+	To    int
+	// Mastery is set when skill reached 90% and Sanity reward was rolled.
+	Mastery bool
+}
+
+// Improved reports whether skill value was increased.
+func (imp Improvement) Improved() bool {
+	return imp.To > imp.From
+}
+
+func (imp Improvement) String() string {
+	if !imp.Improved() {
+		return fmt.Sprintf("%s: rolled %d, no improvement (%d%%)", imp.Skill, imp.Roll, imp.From)
+	}
+
+	return fmt.Sprintf("%s: rolled %d, improved %d%% → %d%%", imp.Skill, imp.Roll, imp.From, imp.To)
+}
+
+// Development is a result of the investigator development phase.
+type Development struct {
+	Improvements []Improvement
+	// SanityRewards are 2D6 rolls for skills that reached 90%.
+	SanityRewards []dice.Result
+	SanityFrom    int
+	SanityTo      int
+}
+
+// Develop runs the development phase: each ticked skill is rolled with d100 and improves by 1D10
+// when the roll is above the skill value or above 95. Skills that reach 90% reward 2D6 Sanity.
+// Ticks are cleared afterwards.
+func (i *InvestigatorClass) Develop(r *dice.Roller) (Development, error) {
+	var dev Development
+
+	for idx := range i.Skills.Skill {
+		s := &i.Skills.Skill[idx]
+
+		if !s.Ticked {
+			continue
+		}
+
+		s.Ticked = false
+
+		if !s.Improvable() {
+			continue
+		}
+
+		v, err := s.Values()
+		if err != nil {
+			return Development{}, fmt.Errorf("%s: %w", s.FullName(), err)
+		}
+
+		imp := Improvement{
+			Skill: s.FullName(),
+			Roll:  r.Die(100),
+			From:  v.Full,
+			To:    v.Full,
+		}
+
+		if imp.Roll > v.Full || imp.Roll > improvementAlways {
+			gain, err := r.Roll(improvementGain)
+			if err != nil {
+				return Development{}, err
+			}
+
+			imp.To = min(v.Full+gain.Total, maxSkillValue)
+
+			s.SetValue(imp.To)
+		}
+
+		if imp.From < masteryThreshold && imp.To >= masteryThreshold {
+			reward, err := r.Roll(masteryReward)
+			if err != nil {
+				return Development{}, err
+			}
+
+			imp.Mastery = true
+
+			dev.SanityRewards = append(dev.SanityRewards, reward)
+		}
+
+		dev.Improvements = append(dev.Improvements, imp)
+	}
+
+	if len(dev.SanityRewards) == 0 {
+		return dev, nil
+	}
+
+	c := &i.Characteristics
+
+	sanity, err := ParseNumber(c.Sanity)
+	if err != nil {
+		return Development{}, fmt.Errorf("sanity: %w", err)
+	}
+
+	sanityMax, err := ParseNumber(c.SanityMax)
+	if err != nil {
+		return Development{}, fmt.Errorf("sanity max: %w", err)
+	}
+
+	if sanityMax == 0 {
+		sanityMax = maxSkillValue
+	}
+
+	dev.SanityFrom = sanity
+
+	for _, reward := range dev.SanityRewards {
+		sanity += reward.Total
+	}
+
+	dev.SanityTo = max(dev.SanityFrom, min(sanity, sanityMax))
+	c.Sanity = strconv.Itoa(dev.SanityTo)
+
+	return dev, nil
+}
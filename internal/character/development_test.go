@@ -0,0 +1,78 @@
+package character_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character/charactertest"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice/dicetest"
+)
+
+func TestInvestigatorClass_Tick(t *testing.T) {
+	ic := charactertest.LoadInvestigator(t)
+
+	ticked, err := ic.Tick("Spot Hidden")
+	require.NoError(t, err)
+	assert.True(t, ticked)
+
+	ticked, err = ic.Tick("spot hidden")
+	require.NoError(t, err)
+	assert.False(t, ticked)
+
+	_, err = ic.Tick("Cthulhu Mythos")
+	require.ErrorIs(t, err, character.ErrNotImprovable)
+
+	_, err = ic.Tick("Basket Weaving")
+	require.ErrorIs(t, err, character.ErrUnknownCheck)
+
+	s, ok := ic.FindSkill("Spot Hidden")
+	require.True(t, ok)
+	assert.True(t, s.Ticked)
+}
+
+func TestInvestigatorClass_Develop(t *testing.T) {
+	ic := charactertest.LoadInvestigator(t)
+
+	for _, name := range []string{"Spot Hidden", "Library Use", "Psychology"} {
+		_, err := ic.Tick(name)
+		require.NoError(t, err)
+	}
+
+	for i, s := range ic.Skills.Skill {
+		if s.Name == "Psychology" {
+			ic.Skills.Skill[i].SkillValues = character.SkillValues{Value: "85", Half: "42", Fifth: "17"}
+		}
+	}
+
+	// Skills are processed in sheet order: Library Use (50), Psychology (85), Spot Hidden (60).
+	// Library Use: 40 - no improvement.
+	// Psychology: 97 - always improves, +8 → 93, mastery 2D6: 3+4.
+	// Spot Hidden: 61 - improves, +10 → 70.
+	dev, err := ic.Develop(dicetest.NewRoller(40, 97, 8, 3, 4, 61, 10))
+	require.NoError(t, err)
+
+	assert.Equal(t, []character.Improvement{
+		{Skill: "Library Use", Roll: 40, From: 50, To: 50},
+		{Skill: "Psychology", Roll: 97, From: 85, To: 93, Mastery: true},
+		{Skill: "Spot Hidden", Roll: 61, From: 60, To: 70},
+	}, dev.Improvements)
+
+	require.Len(t, dev.SanityRewards, 1)
+	assert.Equal(t, 7, dev.SanityRewards[0].Total)
+	assert.Equal(t, 40, dev.SanityFrom)
+	assert.Equal(t, 47, dev.SanityTo)
+	assert.Equal(t, "47", ic.Characteristics.Sanity)
+
+	s, ok := ic.FindSkill("Spot Hidden")
+	require.True(t, ok)
+	assert.Equal(t, character.SkillValues{Value: "70", Half: "35", Fifth: "14"}, s.SkillValues)
+	assert.False(t, s.Ticked)
+
+	// Nothing is ticked anymore.
+	dev, err = ic.Develop(dicetest.NewRoller(100))
+	require.NoError(t, err)
+	assert.Empty(t, dev.Improvements)
+}
@@ -0,0 +1,18 @@
+package character
+
+// Era of the game, it defines available skills and occupations.
+type Era string
+
+const (
+	EraClassic Era = "classic"
+	EraModern  Era = "modern"
+)
+
+// EraFor returns era of the Header.GameType. Unknown game types are treated as classic.
+func EraFor(gameType string) Era {
+	if gameType == GameTypeModern {
+		return EraModern
+	}
+
+	return EraClassic
+}
@@ -0,0 +1,66 @@
+package character
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// DholesHouseVersion is a version of Dhole's House export format produced by Export.
+	DholesHouseVersion = "0.5.0"
+
+	// HeaderDateLayout is a layout of Header.CreateDate.
+	HeaderDateLayout = "02/01/2006 15:04"
+
+	defaultTitle       = "Investigator Export: Character Sheet"
+	defaultGameName    = "Call of Cthulhu TM"
+	defaultGameVersion = "7th Edition"
+	defaultDisclaimer  = "No Ghoul's were harmed producing this file, probably."
+
+	// GameTypeClassic is a Header.GameType for 1920's investigators.
+	GameTypeClassic = "Classic (1920's)"
+	// GameTypeModern is a Header.GameType for modern day investigators.
+	GameTypeModern = "Modern"
+)
+
+// Export returns investigator in Dhole's House export format with header stamped
+// by the creator and the export time. Missing game details are filled with defaults.
+func (i InvestigatorClass) Export(creator string, now time.Time) Investigator {
+	h := &i.Header
+
+	h.Creator = creator
+	h.CreateDate = now.Format(HeaderDateLayout)
+	h.Version = DholesHouseVersion
+
+	if h.Title == "" {
+		h.Title = defaultTitle
+	}
+
+	if h.GameName == "" {
+		h.GameName = defaultGameName
+	}
+
+	if h.GameVersion == "" {
+		h.GameVersion = defaultGameVersion
+	}
+
+	if h.GameType == "" {
+		h.GameType = GameTypeClassic
+	}
+
+	if h.Discalimer == "" {
+		h.Discalimer = defaultDisclaimer
+	}
+
+	// Ticks are kept by this tool only and are not a part of Dhole's House format.
+	// Skills are copied, so the ticks of the caller's sheet stay.
+	i.Skills.Skill = slices.Clone(i.Skills.Skill)
+
+	for j := range i.Skills.Skill {
+		i.Skills.Skill[j].Ticked = false
+	}
+
+	return Investigator{
+		Investigator: i,
+	}
+}
@@ -0,0 +1,62 @@
+package character
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvestigatorClass_Export(t *testing.T) {
+	original, err := UnmarshalInvestigator(bytesFromFilePath(t, filepath.Join("testdata", "character.json")))
+	require.NoError(t, err)
+
+	now := time.Date(2026, time.March, 5, 18, 30, 0, 0, time.UTC)
+
+	exported := original.Investigator.Export("cthulhu-mythos-tools v1.0.0", now)
+
+	data, err := exported.Marshal()
+	require.NoError(t, err)
+
+	got, err := UnmarshalInvestigator(data)
+	require.NoError(t, err)
+
+	want := original
+	want.Investigator.Header.Creator = "cthulhu-mythos-tools v1.0.0"
+	want.Investigator.Header.CreateDate = "05/03/2026 18:30"
+	want.Investigator.Header.Version = DholesHouseVersion
+
+	assert.Equal(t, want, got)
+	assert.Equal(t, "The Dhole's House www.dholeshouse.org", original.Investigator.Header.Creator, "original is not modified")
+}
+
+func TestInvestigatorClass_Export_Defaults(t *testing.T) {
+	got := InvestigatorClass{}.Export("creator", time.Date(2026, time.January, 2, 3, 4, 0, 0, time.UTC))
+
+	assert.Equal(t, Header{
+		Title:       defaultTitle,
+		Creator:     "creator",
+		CreateDate:  "02/01/2026 03:04",
+		GameName:    defaultGameName,
+		GameVersion: defaultGameVersion,
+		GameType:    GameTypeClassic,
+		Discalimer:  defaultDisclaimer,
+		Version:     DholesHouseVersion,
+	}, got.Investigator.Header)
+}
+
+func TestInvestigatorClass_Export_Ticks(t *testing.T) {
+	inv := InvestigatorClass{
+		Skills: Skills{Skill: []Skill{{Name: "Spot Hidden", Ticked: true}}},
+	}
+
+	exported := inv.Export("creator", time.Now())
+
+	data, err := exported.Marshal()
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(data), "ticked")
+	assert.True(t, inv.Skills.Skill[0].Ticked, "original is not modified")
+}
@@ -135,6 +135,8 @@ type Skill struct {
 	SkillValues
 	Subskill   *string `json:"subskill,omitempty"`
 	Occupation *string `json:"occupation,omitempty"`
+	// Ticked marks skill used successfully during the session, it is checked in the development phase.
+	Ticked bool `json:"ticked,omitempty"`
 }
 
 type Weapons struct {
@@ -0,0 +1,169 @@
+package character
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+var (
+	// ErrLuckNotAllowed is returned when Luck could not be spent on the roll.
+	ErrLuckNotAllowed = errors.New("luck could not be spent on this roll")
+	// ErrNotEnoughLuck is returned when investigator has less Luck than the spend costs.
+	ErrNotEnoughLuck = errors.New("not enough luck")
+)
+
+const luckRecoveryGain = "1D10"
+
+// LuckSpend is a result of spending Luck to adjust a skill check.
+type LuckSpend struct {
+	Check string
+	// Result is the adjusted check result.
+	Result dice.CheckResult
+	// RollFrom and LevelFrom are the original roll and its success level.
+	RollFrom  int
+	LevelFrom dice.SuccessLevel
+	Cost      int
+	LuckFrom  int
+	LuckTo    int
+}
+
+// String returns human-readable description of the spend.
+func (s LuckSpend) String() string {
+	return fmt.Sprintf("%s: spent %d Luck to turn %d (%s) into %d (%s), Luck %d → %d",
+		s.Check, s.Cost, s.RollFrom, s.LevelFrom, s.Result.Roll, s.Result.Level, s.LuckFrom, s.LuckTo)
+}
+
+// LuckCost returns Luck points needed to lower the roll to the level: one point for each point of the roll
+// above the level threshold. Fumbles could not be adjusted.
+func LuckCost(res dice.CheckResult, level dice.SuccessLevel) (int, error) {
+	if res.Level == dice.Fumble {
+		return 0, fmt.Errorf("%w: fumble", ErrLuckNotAllowed)
+	}
+
+	var threshold int
+
+	switch level {
+	case dice.RegularSuccess:
+		threshold = res.Target
+	case dice.HardSuccess:
+		threshold = res.Target / 2
+	case dice.ExtremeSuccess:
+		threshold = res.Target / 5
+	default:
+		return 0, fmt.Errorf("%w: could not buy %s", ErrLuckNotAllowed, level)
+	}
+
+	if res.Level >= level {
+		return 0, fmt.Errorf("%w: %s is already achieved", ErrLuckNotAllowed, level)
+	}
+
+	if threshold < 1 {
+		return 0, fmt.Errorf("%w: %s is unreachable for value %d", ErrLuckNotAllowed, level, res.Target)
+	}
+
+	return res.Roll - threshold, nil
+}
+
+// SpendLuck lowers the check roll to the requested success level paying Luck points.
+// Luck could not be spent on Luck and Sanity rolls.
+func (i *InvestigatorClass) SpendLuck(check string, res dice.CheckResult, level dice.SuccessLevel) (LuckSpend, error) {
+	switch strings.ToUpper(strings.TrimSpace(check)) {
+	case "LUCK", "SANITY":
+		return LuckSpend{}, fmt.Errorf("%w: %s roll", ErrLuckNotAllowed, check)
+	}
+
+	cost, err := LuckCost(res, level)
+	if err != nil {
+		return LuckSpend{}, err
+	}
+
+	luck, err := ParseNumber(i.Characteristics.Luck)
+	if err != nil {
+		return LuckSpend{}, fmt.Errorf("luck: %w", err)
+	}
+
+	if cost > luck {
+		return LuckSpend{}, fmt.Errorf("%w: %d needed, %d left", ErrNotEnoughLuck, cost, luck)
+	}
+
+	spend := LuckSpend{
+		Check:     check,
+		Result:    res,
+		RollFrom:  res.Roll,
+		LevelFrom: res.Level,
+		Cost:      cost,
+		LuckFrom:  luck,
+		LuckTo:    luck - cost,
+	}
+
+	spend.Result.Roll -= cost
+	spend.Result.Level = dice.Resolve(spend.Result.Roll, res.Target)
+
+	i.Characteristics.Luck = strconv.Itoa(spend.LuckTo)
+
+	return spend, nil
+}
+
+// LuckRecovery is a result of the end of session Luck recovery roll.
+type LuckRecovery struct {
+	Roll int
+	// Gain is rolled when Roll is above Luck.
+	Gain *dice.Result
+	From int
+	To   int
+}
+
+// String returns human-readable description of the recovery.
+func (r LuckRecovery) String() string {
+	if r.Gain == nil {
+		return fmt.Sprintf("Luck recovery: rolled %d, no gain, Luck %d", r.Roll, r.From)
+	}
+
+	return fmt.Sprintf("Luck recovery: rolled %d, gained %s, Luck %d → %d", r.Roll, r.Gain.String(), r.From, r.To)
+}
+
+// RecoverLuck makes the end of session Luck recovery roll: d100 above current Luck restores 1D10 Luck
+// up to 99. LuckMax is raised when the new Luck is above it.
+func (i *InvestigatorClass) RecoverLuck(r *dice.Roller) (LuckRecovery, error) {
+	c := &i.Characteristics
+
+	luck, err := ParseNumber(c.Luck)
+	if err != nil {
+		return LuckRecovery{}, fmt.Errorf("luck: %w", err)
+	}
+
+	luckMax, err := ParseNumber(c.LuckMax)
+	if err != nil {
+		return LuckRecovery{}, fmt.Errorf("luck max: %w", err)
+	}
+
+	rec := LuckRecovery{
+		Roll: r.Die(100),
+		From: luck,
+		To:   luck,
+	}
+
+	if rec.Roll <= luck {
+		return rec, nil
+	}
+
+	gain, err := r.Roll(luckRecoveryGain)
+	if err != nil {
+		return LuckRecovery{}, err
+	}
+
+	rec.Gain = &gain
+	rec.To = min(luck+gain.Total, maxSkillValue)
+
+	c.Luck = strconv.Itoa(rec.To)
+
+	if luckMax != 0 && rec.To > luckMax {
+		c.LuckMax = c.Luck
+	}
+
+	return rec, nil
+}
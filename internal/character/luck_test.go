@@ -0,0 +1,161 @@
+package character_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character/charactertest"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice/dicetest"
+)
+
+func TestLuckCost(t *testing.T) {
+	tests := []struct {
+		name    string
+		res     dice.CheckResult
+		level   dice.SuccessLevel
+		want    int
+		wantErr bool
+	}{
+		{
+			name:  "failure to regular",
+			res:   dice.CheckResult{Target: 50, Roll: 62, Level: dice.Failure},
+			level: dice.RegularSuccess,
+			want:  12,
+		},
+		{
+			name:  "regular to hard",
+			res:   dice.CheckResult{Target: 50, Roll: 30, Level: dice.RegularSuccess},
+			level: dice.HardSuccess,
+			want:  5,
+		},
+		{
+			name:  "failure to extreme",
+			res:   dice.CheckResult{Target: 50, Roll: 55, Level: dice.Failure},
+			level: dice.ExtremeSuccess,
+			want:  45,
+		},
+		{
+			name:    "fumble",
+			res:     dice.CheckResult{Target: 50, Roll: 100, Level: dice.Fumble},
+			level:   dice.RegularSuccess,
+			wantErr: true,
+		},
+		{
+			name:    "already achieved",
+			res:     dice.CheckResult{Target: 50, Roll: 20, Level: dice.HardSuccess},
+			level:   dice.RegularSuccess,
+			wantErr: true,
+		},
+		{
+			name:    "critical could not be bought",
+			res:     dice.CheckResult{Target: 50, Roll: 20, Level: dice.HardSuccess},
+			level:   dice.CriticalSuccess,
+			wantErr: true,
+		},
+		{
+			name:    "unreachable extreme",
+			res:     dice.CheckResult{Target: 4, Roll: 30, Level: dice.Failure},
+			level:   dice.ExtremeSuccess,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := character.LuckCost(tt.res, tt.level)
+			if tt.wantErr {
+				require.ErrorIs(t, err, character.ErrLuckNotAllowed)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInvestigatorClass_SpendLuck(t *testing.T) {
+	ic := charactertest.LoadInvestigator(t)
+
+	// Spot Hidden 60, Luck 40.
+	spend, err := ic.SpendLuck("Spot Hidden", dice.CheckResult{Target: 60, Roll: 75, Level: dice.Failure}, dice.RegularSuccess)
+	require.NoError(t, err)
+
+	assert.Equal(t, 15, spend.Cost)
+	assert.Equal(t, 60, spend.Result.Roll)
+	assert.Equal(t, dice.RegularSuccess, spend.Result.Level)
+	assert.Equal(t, 40, spend.LuckFrom)
+	assert.Equal(t, 25, spend.LuckTo)
+	assert.Equal(t, "25", ic.Characteristics.Luck)
+	assert.Equal(t, "Spot Hidden: spent 15 Luck to turn 75 (Failure) into 60 (Regular), Luck 40 → 25", spend.String())
+
+	_, err = ic.SpendLuck("Spot Hidden", dice.CheckResult{Target: 60, Roll: 90, Level: dice.Failure}, dice.RegularSuccess)
+	require.ErrorIs(t, err, character.ErrNotEnoughLuck)
+	assert.Equal(t, "25", ic.Characteristics.Luck)
+
+	_, err = ic.SpendLuck("Luck", dice.CheckResult{Target: 25, Roll: 30, Level: dice.Failure}, dice.RegularSuccess)
+	require.ErrorIs(t, err, character.ErrLuckNotAllowed)
+
+	_, err = ic.SpendLuck("sanity", dice.CheckResult{Target: 40, Roll: 45, Level: dice.Failure}, dice.RegularSuccess)
+	require.ErrorIs(t, err, character.ErrLuckNotAllowed)
+}
+
+func TestInvestigatorClass_RecoverLuck(t *testing.T) {
+	tests := []struct {
+		name        string
+		luck        string
+		faces       []int
+		want        character.LuckRecovery
+		wantLuckMax string
+	}{
+		{
+			name:        "roll under luck",
+			luck:        "40",
+			faces:       []int{40},
+			want:        character.LuckRecovery{Roll: 40, From: 40, To: 40},
+			wantLuckMax: "40",
+		},
+		{
+			name:        "roll over luck",
+			luck:        "30",
+			faces:       []int{41, 7},
+			want:        character.LuckRecovery{Roll: 41, From: 30, To: 37},
+			wantLuckMax: "40",
+		},
+		{
+			name:        "luck max is raised",
+			luck:        "38",
+			faces:       []int{90, 5},
+			want:        character.LuckRecovery{Roll: 90, From: 38, To: 43},
+			wantLuckMax: "43",
+		},
+		{
+			name:        "capped at 99",
+			luck:        "95",
+			faces:       []int{100, 10},
+			want:        character.LuckRecovery{Roll: 100, From: 95, To: 99},
+			wantLuckMax: "99",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ic := charactertest.LoadInvestigator(t)
+			ic.Characteristics.Luck = tt.luck
+
+			got, err := ic.RecoverLuck(dicetest.NewRoller(tt.faces...))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want.Roll, got.Roll)
+			assert.Equal(t, tt.want.From, got.From)
+			assert.Equal(t, tt.want.To, got.To)
+			assert.Equal(t, tt.want.To != tt.want.From, got.Gain != nil)
+			assert.Equal(t, tt.wantLuckMax, ic.Characteristics.LuckMax)
+		})
+	}
+}
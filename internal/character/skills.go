@@ -0,0 +1,135 @@
+package character
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownCheck is returned when investigator has no skill or characteristic with given name.
+var ErrUnknownCheck = errors.New("unknown skill or characteristic")
+
+// subskillNone is a Dhole's House placeholder for not chosen specialisation.
+const subskillNone = "None"
+
+// Specialisation returns skill specialisation or empty string when it is not set.
+func (s Skill) Specialisation() string {
+	if s.Subskill == nil || *s.Subskill == subskillNone {
+		return ""
+	}
+
+	return *s.Subskill
+}
+
+// FullName returns skill name with its specialisation, e.g. "Firearms (Handgun)".
+func (s Skill) FullName() string {
+	if sp := s.Specialisation(); sp != "" {
+		return fmt.Sprintf("%s (%s)", s.Name, sp)
+	}
+
+	return s.Name
+}
+
+// IsOccupation reports whether skill is marked as occupation skill.
+func (s Skill) IsOccupation() bool {
+	return s.Occupation != nil && strings.EqualFold(*s.Occupation, "true")
+}
+
+// SetValue sets skill value together with its half and fifth.
+func (s *Skill) SetValue(v int) {
+	nv := NewValue(v)
+
+	s.Value, s.Half, s.Fifth = strconv.Itoa(nv.Full), strconv.Itoa(nv.Half), strconv.Itoa(nv.Fifth)
+}
+
+// SetSkillValue sets value of the skill found as in FindSkill.
+// Skill is added when investigator has no skill with given name.
+func (i *InvestigatorClass) SetSkillValue(name string, v int) {
+	idx := i.skillIndex(name)
+	if idx < 0 {
+		i.Skills.Skill = append(i.Skills.Skill, Skill{Name: strings.TrimSpace(name)})
+		idx = len(i.Skills.Skill) - 1
+	}
+
+	i.Skills.Skill[idx].SetValue(v)
+}
+
+// FindSkill looks up skill by its full name, case-insensitively.
+// Specialised skills also match by specialisation alone, e.g. "Handgun".
+func (i InvestigatorClass) FindSkill(name string) (Skill, bool) {
+	idx := i.skillIndex(name)
+	if idx < 0 {
+		return Skill{}, false
+	}
+
+	return i.Skills.Skill[idx], true
+}
+
+// skillIndex returns index of the skill found as in FindSkill or -1.
+func (i InvestigatorClass) skillIndex(name string) int {
+	name = strings.TrimSpace(name)
+
+	for idx, s := range i.Skills.Skill {
+		if strings.EqualFold(s.FullName(), name) {
+			return idx
+		}
+	}
+
+	for idx, s := range i.Skills.Skill {
+		if sp := s.Specialisation(); sp != "" && strings.EqualFold(sp, name) {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// CheckTarget returns value to roll against for the skill or characteristic with given name.
+func (i InvestigatorClass) CheckTarget(name string) (int, error) {
+	if s, ok := i.FindSkill(name); ok {
+		return ParseNumber(s.Value)
+	}
+
+	c := i.Characteristics
+
+	chars := map[string]string{
+		"STR":    c.Str,
+		"CON":    c.Con,
+		"SIZ":    c.Siz,
+		"DEX":    c.Dex,
+		"APP":    c.App,
+		"INT":    c.Int,
+		"POW":    c.Pow,
+		"EDU":    c.Edu,
+		"LUCK":   c.Luck,
+		"SANITY": c.Sanity,
+	}
+
+	if v, ok := chars[strings.ToUpper(strings.TrimSpace(name))]; ok {
+		return ParseNumber(v)
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrUnknownCheck, name)
+}
+
+// CheckNames returns names of all skills and characteristics that could be rolled.
+func (i InvestigatorClass) CheckNames() []string {
+	names := []string{"STR", "CON", "SIZ", "DEX", "APP", "INT", "POW", "EDU", "Luck", "Sanity"}
+
+	seen := make(map[string]struct{}, len(i.Skills.Skill))
+
+	for _, s := range i.Skills.Skill {
+		n := s.FullName()
+
+		if _, ok := seen[n]; ok {
+			continue
+		}
+
+		seen[n] = struct{}{}
+
+		names = append(names, n)
+	}
+
+	return names
+}
@@ -0,0 +1,44 @@
+package character
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvestigatorClass_CheckTarget(t *testing.T) {
+	inv, err := UnmarshalInvestigator(bytesFromFilePath(t, filepath.Join("testdata", "character.json")))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		check   string
+		want    int
+		wantErr error
+	}{
+		{name: "skill", check: "Spot Hidden", want: 60},
+		{name: "case insensitive", check: "library use", want: 50},
+		{name: "specialised", check: "Firearms (Handgun)", want: 40},
+		{name: "specialisation only", check: "Photography", want: 20},
+		{name: "unset specialisation", check: "Art/Craft", want: 5},
+		{name: "characteristic", check: "edu", want: 70},
+		{name: "luck", check: "Luck", want: 40},
+		{name: "unknown", check: "Necromancy", wantErr: ErrUnknownCheck},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := inv.Investigator.CheckTarget(tt.check)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
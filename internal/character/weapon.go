@@ -0,0 +1,20 @@
+package character
+
+import (
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+// RollDamage rolls weapon damage applying passed damage bonus to DB terms.
+func (w Weapon) RollDamage(r *dice.Roller, damageBonus string) (dice.Result, error) {
+	return r.Roll(w.Damage, dice.WithDamageBonus(damageBonus))
+}
+
+// DamageBonus returns investigator damage bonus from combat section,
+// falling back to characteristics when combat one is not filled.
+func (i InvestigatorClass) DamageBonus() string {
+	if i.Combat.DamageBonus != "" {
+		return i.Combat.DamageBonus
+	}
+
+	return i.Characteristics.DamageBonus
+}
@@ -0,0 +1,31 @@
+package character
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+func TestWeapon_RollDamage(t *testing.T) {
+	inv, err := UnmarshalInvestigator(bytesFromFilePath(t, filepath.Join("testdata", "character.json")))
+	require.NoError(t, err)
+
+	ic := inv.Investigator
+
+	for _, w := range ic.Weapons.Weapon {
+		t.Run(w.Name, func(t *testing.T) {
+			maxDamage, err := dice.Max(w.Damage, dice.WithDamageBonus(ic.DamageBonus()))
+			require.NoError(t, err)
+
+			got, err := w.RollDamage(dice.NewSeededRoller(1), ic.DamageBonus())
+			require.NoError(t, err)
+
+			assert.GreaterOrEqual(t, got.Total, 1)
+			assert.LessOrEqual(t, got.Total, maxDamage.Total)
+		})
+	}
+}
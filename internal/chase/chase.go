@@ -0,0 +1,397 @@
+package chase
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+var (
+	// ErrFinished is returned when finished chase is modified.
+	ErrFinished = errors.New("chase is finished")
+	// ErrNotStarted is returned when participants move before the speed rolls.
+	ErrNotStarted = errors.New("chase is not started")
+	// ErrStarted is returned when the chase setup is changed after the speed rolls.
+	ErrStarted = errors.New("chase is already started")
+	// ErrCannotMove is returned when participant has no movement actions or nowhere to move.
+	ErrCannotMove = errors.New("participant cannot move")
+)
+
+// Chase is a chase tracked by the Keeper.
+type Chase struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Locations []Location `json:"locations"`
+	// Participants keep their positions on the Locations track.
+	Participants []Participant `json:"participants"`
+	// Round is the current round, zero means speed rolls are not made yet.
+	Round int `json:"round"`
+	// Log describes rolls and their results, oldest first.
+	Log       []string  `json:"log,omitempty"`
+	Finished  bool      `json:"finished,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// KeeperID is ID of the user running the chase, empty for chases created without authentication.
+	KeeperID string `json:"keeper_id,omitempty"`
+}
+
+// NewChase creates chase on the track of locations.
+func NewChase(id, name string, locations []Location, now time.Time) (Chase, error) {
+	if len(locations) < minLocations {
+		return Chase{}, fmt.Errorf("%w: at least %d locations are required", ErrInvalidTrack, minLocations)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Chase"
+	}
+
+	return Chase{
+		ID:        id,
+		Name:      name,
+		Locations: locations,
+		CreatedAt: now,
+	}, nil
+}
+
+// Add puts participant on the track before the chase is started.
+func (c *Chase) Add(p Participant) error {
+	switch {
+	case c.Finished:
+		return ErrFinished
+	case c.Round > 0:
+		return ErrStarted
+	}
+
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	if p.Position < 0 || p.Position >= len(c.Locations) {
+		return fmt.Errorf("%w: %s: position %d is out of the track", ErrInvalidParticipant, p.Name, p.Position+1)
+	}
+
+	if _, err := c.participant(p.ID); err == nil {
+		return fmt.Errorf("%w: %s is already in the chase", ErrInvalidParticipant, p.Name)
+	}
+
+	c.Participants = append(c.Participants, p)
+
+	c.logf("%s joins the chase as %s at %s", p.Name, p.Role, c.Locations[p.Position].Name)
+
+	return nil
+}
+
+// Participant returns participant by ID.
+func (c Chase) Participant(id string) (Participant, error) {
+	p, err := c.participant(id)
+	if err != nil {
+		return Participant{}, err
+	}
+
+	return *p, nil
+}
+
+func (c *Chase) participant(id string) (*Participant, error) {
+	for i := range c.Participants {
+		if c.Participants[i].ID == id {
+			return &c.Participants[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownParticipant, id)
+}
+
+// Order returns participants in order of action: highest DEX first.
+func (c Chase) Order() []Participant {
+	order := slices.Clone(c.Participants)
+
+	slices.SortStableFunc(order, func(a, b Participant) int {
+		return cmp.Compare(b.DEX, a.DEX)
+	})
+
+	return order
+}
+
+// At returns active participants at the location.
+func (c Chase) At(position int) []Participant {
+	var res []Participant
+
+	for _, p := range c.Order() {
+		if p.Active() && p.Position == position {
+			res = append(res, p)
+		}
+	}
+
+	return res
+}
+
+// Start makes speed rolls and starts the first round. Extreme success on CON (Drive Auto in a vehicle)
+// adds 1 to MOV and failure subtracts 1. Quarry faster than every pursuer escapes at once
+// and pursuer slower than every remaining quarry is left behind.
+func (c *Chase) Start(r *dice.Roller) error {
+	switch {
+	case c.Finished:
+		return ErrFinished
+	case c.Round > 0:
+		return ErrStarted
+	}
+
+	if !c.has(RoleQuarry) || !c.has(RolePursuer) {
+		return fmt.Errorf("%w: chase needs a quarry and a pursuer", ErrInvalidParticipant)
+	}
+
+	for i := range c.Participants {
+		p := &c.Participants[i]
+
+		check, err := r.Check(p.SkillValue(p.SpeedSkill()), 0, 0)
+		if err != nil {
+			return err
+		}
+
+		p.SpeedRoll = &check
+		p.AdjustedMOV = p.MOV
+
+		switch {
+		case check.Level >= dice.ExtremeSuccess:
+			p.AdjustedMOV++
+		case !check.Level.IsSuccess():
+			p.AdjustedMOV = max(p.AdjustedMOV-1, 1)
+		}
+
+		c.logf("%s speed roll %s %d: %s, MOV %d", p.Name, p.SpeedSkill(), check.Roll, check.Level, p.AdjustedMOV)
+	}
+
+	fastestPursuer := 0
+
+	for _, p := range c.Participants {
+		if p.Role == RolePursuer {
+			fastestPursuer = max(fastestPursuer, p.AdjustedMOV)
+		}
+	}
+
+	slowestQuarry := 0
+
+	for i := range c.Participants {
+		p := &c.Participants[i]
+
+		if p.Role != RoleQuarry {
+			continue
+		}
+
+		if p.AdjustedMOV > fastestPursuer {
+			p.Escaped = true
+
+			c.logf("%s is faster than every pursuer and escapes", p.Name)
+
+			continue
+		}
+
+		if slowestQuarry == 0 || p.AdjustedMOV < slowestQuarry {
+			slowestQuarry = p.AdjustedMOV
+		}
+	}
+
+	for i := range c.Participants {
+		p := &c.Participants[i]
+
+		if p.Role == RolePursuer && p.AdjustedMOV < slowestQuarry {
+			p.LeftBehind = true
+
+			c.logf("%s is slower than every quarry and is left behind", p.Name)
+		}
+	}
+
+	if c.finishIfOver() {
+		return nil
+	}
+
+	c.newRound()
+
+	return nil
+}
+
+// NextRound ends the current round and gives participants their movement actions.
+func (c *Chase) NextRound() error {
+	switch {
+	case c.Finished:
+		return ErrFinished
+	case c.Round == 0:
+		return ErrNotStarted
+	}
+
+	c.newRound()
+
+	return nil
+}
+
+// newRound gives each active participant one movement action plus one for each point of MOV
+// above the slowest participant. Actions lost on hazards in the previous round are subtracted.
+func (c *Chase) newRound() {
+	c.Round++
+
+	c.logf("Round %d", c.Round)
+
+	slowest := 0
+
+	for _, p := range c.Participants {
+		if p.Active() && (slowest == 0 || p.AdjustedMOV < slowest) {
+			slowest = p.AdjustedMOV
+		}
+	}
+
+	for i := range c.Participants {
+		p := &c.Participants[i]
+
+		if !p.Active() {
+			p.Actions = 0
+
+			continue
+		}
+
+		p.Actions = min(p.Actions, 0) + 1 + p.AdjustedMOV - slowest
+	}
+}
+
+// Finish ends the chase.
+func (c *Chase) Finish() {
+	c.Finished = true
+
+	c.logf("Chase is over")
+}
+
+// Movement is a result of the movement action.
+type Movement struct {
+	Participant string `json:"participant"`
+	From        int    `json:"from"`
+	To          int    `json:"to"`
+	// Check is the roll to pass the obstacle, nil for clear locations.
+	Check *dice.CheckResult `json:"check,omitempty"`
+	// Lost are movement actions lost on a failed hazard roll.
+	Lost    int  `json:"lost,omitempty"`
+	Escaped bool `json:"escaped,omitempty"`
+	// Caught are names of the quarries caught by the pursuer.
+	Caught []string `json:"caught,omitempty"`
+}
+
+// Move spends movement action of participant to move to the next location of the track.
+// Failed roll on a hazard still passes it but costs 1D3 more actions, failed roll on a barrier
+// leaves participant in place. Pursuer reaching location of a quarry catches it and quarry
+// reaching the end of the track escapes.
+func (c *Chase) Move(r *dice.Roller, id string) (Movement, error) {
+	switch {
+	case c.Finished:
+		return Movement{}, ErrFinished
+	case c.Round == 0:
+		return Movement{}, ErrNotStarted
+	}
+
+	p, err := c.participant(id)
+	if err != nil {
+		return Movement{}, err
+	}
+
+	switch {
+	case !p.Active():
+		return Movement{}, fmt.Errorf("%w: %s is %s", ErrCannotMove, p.Name, p.Status())
+	case p.Actions <= 0:
+		return Movement{}, fmt.Errorf("%w: %s has no movement actions left", ErrCannotMove, p.Name)
+	case p.Position+1 >= len(c.Locations):
+		return Movement{}, fmt.Errorf("%w: %s is at the end of the track", ErrCannotMove, p.Name)
+	}
+
+	m := Movement{
+		Participant: p.ID,
+		From:        p.Position,
+		To:          p.Position + 1,
+	}
+
+	p.Actions--
+
+	loc := c.Locations[m.To]
+
+	if loc.Obstacle != ObstacleNone {
+		check, err := r.Check(p.SkillValue(loc.Skill), 0, 0)
+		if err != nil {
+			return Movement{}, err
+		}
+
+		m.Check = &check
+
+		switch {
+		case check.Level.IsSuccess():
+			c.logf("%s passes %s %s with %s %d: %s", p.Name, loc.Obstacle, loc.Name, loc.Skill, check.Roll, check.Level)
+		case loc.Obstacle == ObstacleHazard:
+			m.Lost = r.Die(3)
+			p.Actions -= m.Lost
+
+			c.logf("%s fails %s %d on hazard %s and loses %d movement actions", p.Name, loc.Skill, check.Roll, loc.Name, m.Lost)
+		default:
+			m.To = m.From
+
+			c.logf("%s fails %s %d and is stopped by barrier %s", p.Name, loc.Skill, check.Roll, loc.Name)
+
+			return m, nil
+		}
+	}
+
+	p.Position = m.To
+
+	c.logf("%s moves to %s", p.Name, loc.Name)
+
+	switch p.Role {
+	case RoleQuarry:
+		if p.Position == len(c.Locations)-1 {
+			p.Escaped = true
+			m.Escaped = true
+
+			c.logf("%s escapes", p.Name)
+		}
+	case RolePursuer:
+		for i := range c.Participants {
+			q := &c.Participants[i]
+
+			if q.Role == RoleQuarry && q.Active() && q.Position == p.Position {
+				q.Caught = true
+				m.Caught = append(m.Caught, q.Name)
+
+				c.logf("%s catches %s", p.Name, q.Name)
+			}
+		}
+	}
+
+	c.finishIfOver()
+
+	return m, nil
+}
+
+// finishIfOver finishes the chase when there are no quarries or pursuers left in it.
+func (c *Chase) finishIfOver() bool {
+	if c.active(RoleQuarry) && c.active(RolePursuer) {
+		return false
+	}
+
+	c.Finish()
+
+	return true
+}
+
+func (c Chase) has(role Role) bool {
+	return slices.ContainsFunc(c.Participants, func(p Participant) bool {
+		return p.Role == role
+	})
+}
+
+func (c Chase) active(role Role) bool {
+	return slices.ContainsFunc(c.Participants, func(p Participant) bool {
+		return p.Role == role && p.Active()
+	})
+}
+
+func (c *Chase) logf(format string, args ...any) {
+	c.Log = append(c.Log, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,347 @@
+package chase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character/charactertest"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice/dicetest"
+)
+
+// investigator returns quarry of the test investigator: DEX 55, CON 45, MOV 7, Climb 20, Jump 20.
+func investigator(t *testing.T, position int) Participant {
+	t.Helper()
+
+	p, err := FromInvestigator("inv", "character-id", RoleQuarry, charactertest.LoadInvestigator(t))
+	require.NoError(t, err)
+
+	p.Position = position
+
+	return p
+}
+
+func ghoul(mov int) Participant {
+	return Participant{
+		ID:     "npc",
+		Name:   "Ghoul",
+		Role:   RolePursuer,
+		DEX:    65,
+		MOV:    mov,
+		Skills: map[string]int{"con": 50, "climb": 85},
+	}
+}
+
+func track() []Location {
+	return []Location{
+		{Name: "Street"},
+		{Name: "Market"},
+		{Name: "Alley", Obstacle: ObstacleHazard, Skill: "Jump"},
+		{Name: "Fence", Obstacle: ObstacleBarrier, Skill: "Climb"},
+		{Name: "Docks"},
+	}
+}
+
+func newChase(t *testing.T, ps ...Participant) Chase {
+	t.Helper()
+
+	c, err := NewChase("chase", "", track(), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	for _, p := range ps {
+		require.NoError(t, c.Add(p))
+	}
+
+	return c
+}
+
+func TestParseLocations(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []Location
+		wantErr bool
+	}{
+		{
+			name: "clear and obstacles",
+			text: "Street\n\n Alley | Hazard | Jump \nFence|barrier|Climb\r\n",
+			want: []Location{
+				{Name: "Street"},
+				{Name: "Alley", Obstacle: ObstacleHazard, Skill: "Jump"},
+				{Name: "Fence", Obstacle: ObstacleBarrier, Skill: "Climb"},
+			},
+		},
+		{
+			name:    "unknown obstacle",
+			text:    "Street\nRiver | flood | Swim",
+			wantErr: true,
+		},
+		{
+			name:    "missing skill",
+			text:    "Street\nFence | barrier",
+			wantErr: true,
+		},
+		{
+			name:    "empty name",
+			text:    "Street\n | hazard | Jump",
+			wantErr: true,
+		},
+		{
+			name:    "single location",
+			text:    "Street",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLocations(tt.text)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidTrack)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromInvestigator(t *testing.T) {
+	p := investigator(t, 0)
+
+	assert.Equal(t, "Ричард Смит", p.Name)
+	assert.Equal(t, RoleQuarry, p.Role)
+	assert.Equal(t, "character-id", p.CharacterID)
+	assert.Equal(t, 55, p.DEX)
+	assert.Equal(t, 7, p.MOV)
+	assert.Equal(t, "CON", p.SpeedSkill())
+	assert.Equal(t, 45, p.SkillValue(p.SpeedSkill()))
+	assert.Equal(t, 20, p.SkillValue("climb"))
+	assert.Equal(t, 0, p.SkillValue("Piloting"))
+
+	p.UseVehicle(12)
+
+	assert.Equal(t, 12, p.MOV)
+	assert.Equal(t, "Drive Auto", p.SpeedSkill())
+	assert.Equal(t, 20, p.SkillValue(p.SpeedSkill()))
+}
+
+func TestChase_Start(t *testing.T) {
+	tests := []struct {
+		name        string
+		ghoulMOV    int
+		faces       []int
+		wantMOV     [2]int
+		wantActions [2]int
+		wantEscaped bool
+	}{
+		{
+			name:        "regular speed rolls",
+			ghoulMOV:    9,
+			faces:       []int{1, 4, 1, 4},
+			wantMOV:     [2]int{7, 9},
+			wantActions: [2]int{1, 3},
+		},
+		{
+			name:        "extreme quarry escapes",
+			ghoulMOV:    7,
+			faces:       []int{6, 1, 1, 4},
+			wantMOV:     [2]int{8, 7},
+			wantEscaped: true,
+		},
+		{
+			name:        "failed pursuer lets quarry escape",
+			ghoulMOV:    7,
+			faces:       []int{1, 4, 1, 10},
+			wantMOV:     [2]int{7, 6},
+			wantEscaped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newChase(t, investigator(t, 1), ghoul(tt.ghoulMOV))
+
+			require.NoError(t, c.Start(dicetest.NewRoller(tt.faces...)))
+
+			quarry, pursuer := c.Participants[0], c.Participants[1]
+
+			assert.Equal(t, tt.wantMOV, [2]int{quarry.AdjustedMOV, pursuer.AdjustedMOV})
+			assert.Equal(t, tt.wantEscaped, quarry.Escaped)
+			assert.Equal(t, tt.wantEscaped, c.Finished)
+
+			if !tt.wantEscaped {
+				assert.Equal(t, 1, c.Round)
+				assert.Equal(t, tt.wantActions, [2]int{quarry.Actions, pursuer.Actions})
+			}
+		})
+	}
+}
+
+func TestChase_Move(t *testing.T) {
+	tests := []struct {
+		name        string
+		mover       string
+		position    int
+		faces       []int
+		wantTo      int
+		wantLost    int
+		wantActions int
+		wantErr     error
+		wantEscaped bool
+		wantCaught  bool
+	}{
+		{
+			name:        "clear location",
+			mover:       "inv",
+			position:    0,
+			wantTo:      1,
+			wantActions: 0,
+		},
+		{
+			name:        "pursuer catches quarry",
+			mover:       "npc",
+			position:    1,
+			wantTo:      1,
+			wantActions: 2,
+			wantCaught:  true,
+		},
+		{
+			name:        "hazard passed",
+			mover:       "inv",
+			position:    1,
+			faces:       []int{1, 2},
+			wantTo:      2,
+			wantActions: 0,
+		},
+		{
+			name:        "hazard failed",
+			mover:       "inv",
+			position:    1,
+			faces:       []int{1, 10, 2},
+			wantTo:      2,
+			wantLost:    2,
+			wantActions: -2,
+		},
+		{
+			name:        "barrier failed",
+			mover:       "inv",
+			position:    2,
+			faces:       []int{1, 10},
+			wantTo:      2,
+			wantActions: 0,
+		},
+		{
+			name:        "quarry escapes",
+			mover:       "inv",
+			position:    3,
+			wantTo:      4,
+			wantActions: 0,
+			wantEscaped: true,
+		},
+		{
+			name:     "end of the track",
+			mover:    "inv",
+			position: 4,
+			wantErr:  ErrCannotMove,
+		},
+		{
+			name:     "unknown participant",
+			mover:    "nobody",
+			position: 1,
+			wantErr:  ErrUnknownParticipant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newChase(t, investigator(t, tt.position), ghoul(9))
+
+			require.NoError(t, c.Start(dicetest.NewRoller(1, 4, 1, 4)))
+
+			m, err := c.Move(dicetest.NewRoller(append(tt.faces, 1)...), tt.mover)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			p, err := c.Participant(tt.mover)
+			require.NoError(t, err)
+
+			quarry, err := c.Participant("inv")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantTo, m.To)
+			assert.Equal(t, tt.wantTo, p.Position)
+			assert.Equal(t, tt.wantLost, m.Lost)
+			assert.Equal(t, tt.wantActions, p.Actions)
+			assert.Equal(t, tt.wantEscaped, quarry.Escaped)
+			assert.Equal(t, tt.wantCaught, quarry.Caught)
+			assert.Equal(t, tt.wantEscaped || tt.wantCaught, c.Finished)
+		})
+	}
+}
+
+func TestChase_Rounds(t *testing.T) {
+	c := newChase(t, investigator(t, 1), ghoul(9))
+
+	_, err := c.Move(dicetest.NewRoller(1), "inv")
+	require.ErrorIs(t, err, ErrNotStarted)
+
+	require.ErrorIs(t, c.NextRound(), ErrNotStarted)
+
+	require.NoError(t, c.Start(dicetest.NewRoller(1, 4, 1, 4)))
+	require.ErrorIs(t, c.Add(ghoul(8)), ErrStarted)
+
+	// Failed Jump on the hazard costs 3 more actions, the delay is carried to the next rounds.
+	_, err = c.Move(dicetest.NewRoller(1, 10, 3), "inv")
+	require.NoError(t, err)
+
+	_, err = c.Move(dicetest.NewRoller(1), "inv")
+	require.ErrorIs(t, err, ErrCannotMove)
+
+	require.NoError(t, c.NextRound())
+
+	quarry, err := c.Participant("inv")
+	require.NoError(t, err)
+
+	pursuer, err := c.Participant("npc")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, c.Round)
+	assert.Equal(t, -2, quarry.Actions)
+	assert.Equal(t, 3, pursuer.Actions)
+
+	order := c.Order()
+	assert.Equal(t, "npc", order[0].ID)
+	assert.Equal(t, []Participant{pursuer}, c.At(0))
+}
+
+func TestChase_StartLeftBehind(t *testing.T) {
+	slow := ghoul(6)
+	slow.ID, slow.Name = "slow", "Slow ghoul"
+
+	c := newChase(t, investigator(t, 1), ghoul(9), slow)
+
+	require.NoError(t, c.Start(dicetest.NewRoller(1, 4)))
+
+	pursuer, err := c.Participant("slow")
+	require.NoError(t, err)
+
+	assert.True(t, pursuer.LeftBehind)
+	assert.False(t, c.Finished)
+	assert.Equal(t, 0, pursuer.Actions)
+}
+
+func TestChase_StartWithoutPursuer(t *testing.T) {
+	c := newChase(t, investigator(t, 1))
+
+	require.ErrorIs(t, c.Start(dicetest.NewRoller(1, 4)), ErrInvalidParticipant)
+}
@@ -0,0 +1,181 @@
+package chase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/skills"
+)
+
+var (
+	// ErrInvalidParticipant is returned when participant could not join the chase.
+	ErrInvalidParticipant = errors.New("invalid chase participant")
+	// ErrUnknownParticipant is returned when chase has no participant with given ID.
+	ErrUnknownParticipant = errors.New("unknown chase participant")
+)
+
+// Role of the participant in the chase.
+type Role string
+
+const (
+	// RoleQuarry is fleeing from the pursuers.
+	RoleQuarry Role = "quarry"
+	// RolePursuer is trying to catch the quarry.
+	RolePursuer Role = "pursuer"
+)
+
+// ParseRole returns role by its name.
+func ParseRole(s string) (Role, error) {
+	switch r := Role(strings.ToLower(strings.TrimSpace(s))); r {
+	case RoleQuarry, RolePursuer:
+		return r, nil
+	default:
+		return "", fmt.Errorf("%w: unknown role %q", ErrInvalidParticipant, s)
+	}
+}
+
+const (
+	// speedSkillOnFoot is rolled for the speed roll in a chase on foot.
+	speedSkillOnFoot = "CON"
+	// speedSkillVehicle is rolled for the speed roll in a vehicle chase.
+	speedSkillVehicle = "Drive Auto"
+)
+
+// Participant is a quarry or pursuer in the chase.
+type Participant struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Role Role   `json:"role"`
+	// CharacterID links investigator participant to the stored character.
+	CharacterID string `json:"character_id,omitempty"`
+
+	DEX int `json:"dex"`
+	MOV int `json:"mov"`
+	// Vehicle participants use Drive Auto for the speed roll instead of CON.
+	Vehicle bool `json:"vehicle,omitempty"`
+	// Skills are values rolled to pass obstacles and for the speed roll, keys are lower cased names.
+	Skills map[string]int `json:"skills"`
+	// DefaultSkill is rolled for obstacles with skills missing in Skills.
+	DefaultSkill int `json:"default_skill"`
+
+	// SpeedRoll is set when the chase is started.
+	SpeedRoll   *dice.CheckResult `json:"speed_roll,omitempty"`
+	AdjustedMOV int               `json:"adjusted_mov"`
+	// Position is index of the location on the track.
+	Position int `json:"position"`
+	// Actions are movement actions left in the current round, negative value is a delay carried to the next round.
+	Actions int `json:"actions"`
+
+	Escaped bool `json:"escaped,omitempty"`
+	Caught  bool `json:"caught,omitempty"`
+	// LeftBehind is set for pursuer slower than every quarry at the start of the chase.
+	LeftBehind bool `json:"left_behind,omitempty"`
+}
+
+// Active reports whether participant still takes part in the chase.
+func (p Participant) Active() bool {
+	return !p.Escaped && !p.Caught && !p.LeftBehind
+}
+
+// Status returns participant state description.
+func (p Participant) Status() string {
+	switch {
+	case p.Escaped:
+		return "escaped"
+	case p.Caught:
+		return "caught"
+	case p.LeftBehind:
+		return "left behind"
+	default:
+		return "in chase"
+	}
+}
+
+// SkillValue returns value rolled for the skill or characteristic.
+func (p Participant) SkillValue(name string) int {
+	if v, ok := p.Skills[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return v
+	}
+
+	return p.DefaultSkill
+}
+
+// SpeedSkill returns name of the skill used for the speed roll.
+func (p Participant) SpeedSkill() string {
+	if p.Vehicle {
+		return speedSkillVehicle
+	}
+
+	return speedSkillOnFoot
+}
+
+// UseVehicle puts participant into a vehicle with given MOV.
+func (p *Participant) UseVehicle(mov int) {
+	p.Vehicle = true
+	p.MOV = mov
+}
+
+// Validate checks that participant could join the chase.
+func (p Participant) Validate() error {
+	switch {
+	case strings.TrimSpace(p.ID) == "":
+		return fmt.Errorf("%w: empty id", ErrInvalidParticipant)
+	case strings.TrimSpace(p.Name) == "":
+		return fmt.Errorf("%w: empty name", ErrInvalidParticipant)
+	case p.Role != RoleQuarry && p.Role != RolePursuer:
+		return fmt.Errorf("%w: %s: unknown role %q", ErrInvalidParticipant, p.Name, p.Role)
+	case p.MOV <= 0:
+		return fmt.Errorf("%w: %s: MOV should be positive", ErrInvalidParticipant, p.Name)
+	}
+
+	return nil
+}
+
+// FromInvestigator creates participant from investigator sheet. MOV is taken from Characteristics.Move
+// or derived when it is not filled. Skills not trained by investigator are rolled with their base values.
+func FromInvestigator(id, characterID string, role Role, inv character.InvestigatorClass) (Participant, error) {
+	stats, err := inv.Characteristics.Stats()
+	if err != nil {
+		return Participant{}, fmt.Errorf("%w: %w", ErrInvalidParticipant, err)
+	}
+
+	mov, err := character.ParseNumber(inv.Characteristics.Move)
+	if err != nil {
+		return Participant{}, fmt.Errorf("%w: move: %w", ErrInvalidParticipant, err)
+	}
+
+	if mov == 0 {
+		age, _ := character.ParseNumber(inv.PersonalDetails.Age)
+
+		mov = character.Derive(stats, age).Move
+	}
+
+	p := Participant{
+		ID:          id,
+		Name:        inv.PersonalDetails.Name,
+		Role:        role,
+		CharacterID: characterID,
+		DEX:         stats.DEX.Full,
+		MOV:         mov,
+		Skills:      make(map[string]int),
+	}
+
+	for _, s := range skills.Starting(character.EraFor(inv.Header.GameType), stats) {
+		p.Skills[strings.ToLower(s.FullName())] = s.Base
+	}
+
+	for _, name := range inv.CheckNames() {
+		if v, err := inv.CheckTarget(name); err == nil {
+			p.Skills[strings.ToLower(name)] = v
+		}
+	}
+
+	if p.Name == "" {
+		p.Name = characterID
+	}
+
+	return p, p.Validate()
+}
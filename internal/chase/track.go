@@ -0,0 +1,86 @@
+// Package chase implements Call of Cthulhu 7e chases: speed rolls, a track of locations
+// with hazards and barriers and movement actions of the participants.
+package chase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidTrack is returned when chase locations could not be parsed.
+var ErrInvalidTrack = errors.New("invalid chase track")
+
+// Obstacle on the chase location.
+type Obstacle string
+
+const (
+	// ObstacleNone is a clear location.
+	ObstacleNone Obstacle = ""
+	// ObstacleHazard is passed anyway, failed skill roll costs 1D3 movement actions.
+	ObstacleHazard Obstacle = "hazard"
+	// ObstacleBarrier stops participant until skill roll succeeds.
+	ObstacleBarrier Obstacle = "barrier"
+)
+
+// Location is a place on the chase track.
+type Location struct {
+	Name     string   `json:"name"`
+	Obstacle Obstacle `json:"obstacle,omitempty"`
+	// Skill is rolled to pass the obstacle, e.g. "Climb", "Jump" or "DEX".
+	Skill string `json:"skill,omitempty"`
+}
+
+// String returns location in the form parsed by ParseLocations.
+func (l Location) String() string {
+	if l.Obstacle == ObstacleNone {
+		return l.Name
+	}
+
+	return fmt.Sprintf("%s | %s | %s", l.Name, l.Obstacle, l.Skill)
+}
+
+const minLocations = 2
+
+// ParseLocations parses chase track, one location per line: "Name" for a clear location
+// or "Name | hazard | Skill" and "Name | barrier | Skill" for obstacles. Empty lines are skipped.
+func ParseLocations(text string) ([]Location, error) {
+	var res []Location
+
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		parts := strings.Split(line, "|")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+
+		loc := Location{Name: parts[0]}
+
+		switch {
+		case loc.Name == "":
+			return nil, fmt.Errorf("%w: line %d: empty location name", ErrInvalidTrack, n+1)
+		case len(parts) == 1:
+		case len(parts) == 3 && parts[2] != "":
+			loc.Obstacle = Obstacle(strings.ToLower(parts[1]))
+			loc.Skill = parts[2]
+
+			if loc.Obstacle != ObstacleHazard && loc.Obstacle != ObstacleBarrier {
+				return nil, fmt.Errorf("%w: line %d: unknown obstacle %q", ErrInvalidTrack, n+1, parts[1])
+			}
+		default:
+			return nil, fmt.Errorf("%w: line %d: expected \"Name\" or \"Name | hazard | Skill\"", ErrInvalidTrack, n+1)
+		}
+
+		res = append(res, loc)
+	}
+
+	if len(res) < minLocations {
+		return nil, fmt.Errorf("%w: at least %d locations are required", ErrInvalidTrack, minLocations)
+	}
+
+	return res, nil
+}
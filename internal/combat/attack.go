@@ -0,0 +1,376 @@
+package combat
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+// ErrUnknownResponse is returned for unsupported response to the attack.
+var ErrUnknownResponse = errors.New("unknown response")
+
+// Response of the target to the melee attack.
+type Response string
+
+const (
+	// ResponseNone is used when target does not or could not respond, e.g. to firearm attacks.
+	ResponseNone      Response = "none"
+	ResponseDodge     Response = "dodge"
+	ResponseFightBack Response = "fightback"
+)
+
+// ParseResponse returns response by its name, empty name means no response.
+func ParseResponse(s string) (Response, error) {
+	switch r := Response(strings.ToLower(strings.TrimSpace(s))); r {
+	case "", ResponseNone:
+		return ResponseNone, nil
+	case ResponseDodge, ResponseFightBack:
+		return r, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownResponse, s)
+	}
+}
+
+// Wound is a damage applied to combatant.
+type Wound struct {
+	Damage int
+	HPFrom int
+	HPTo   int
+	// MajorWound is set when a single hit dealt half of maximum hit points or more.
+	MajorWound bool
+	// CONCheck is rolled on major wound to stay conscious.
+	CONCheck    *dice.CheckResult
+	Unconscious bool
+	Dying       bool
+	Dead        bool
+}
+
+// String returns human-readable wound, e.g. "HP 10 → 4, major wound, unconscious".
+func (w Wound) String() string {
+	s := fmt.Sprintf("HP %d → %d", w.HPFrom, w.HPTo)
+
+	if w.MajorWound {
+		s += ", major wound"
+	}
+
+	if w.CONCheck != nil {
+		s += fmt.Sprintf(", CON rolled %d (%s)", w.CONCheck.Roll, w.CONCheck.Level)
+	}
+
+	switch {
+	case w.Dead:
+		s += ", dead"
+	case w.Dying:
+		s += ", dying"
+	case w.Unconscious:
+		s += ", unconscious"
+	}
+
+	return s
+}
+
+// TakeDamage applies damage per 7e rules: damage of more than maximum hit points in a single hit kills,
+// half of maximum or more is a major wound that calls for a CON roll to stay conscious.
+// At zero hit points combatant with major wound is dying, otherwise unconscious.
+func (c *Combatant) TakeDamage(r *dice.Roller, damage int) (Wound, error) {
+	damage = max(damage, 0)
+
+	w := Wound{
+		Damage: damage,
+		HPFrom: c.HP,
+	}
+
+	c.HP = max(c.HP-damage, 0)
+	w.HPTo = c.HP
+
+	if damage > c.HPMax {
+		c.Dead, w.Dead = true, true
+
+		return w, nil
+	}
+
+	if damage > 0 && damage*2 >= c.HPMax {
+		c.MajorWound, w.MajorWound = true, true
+	}
+
+	switch {
+	case c.HP == 0 && c.MajorWound:
+		c.Dying, w.Dying = true, true
+	case c.HP == 0:
+		c.Unconscious, w.Unconscious = true, true
+	case w.MajorWound:
+		check, err := r.Check(c.CON, 0, 0)
+		if err != nil {
+			return Wound{}, err
+		}
+
+		w.CONCheck = &check
+
+		if !check.Level.IsSuccess() {
+			c.Unconscious, w.Unconscious = true, true
+		}
+	}
+
+	return w, nil
+}
+
+// Attack is a result of the attack.
+type Attack struct {
+	Attacker string
+	Target   string
+	Weapon   string
+	Response Response
+	// Outnumbered target already responded this round and attacker got a bonus die.
+	Outnumbered bool
+	AttackRoll  dice.CheckResult
+	DefenceRoll *dice.CheckResult
+	Hit         bool
+	// Countered is set when target fought back and won, then Damage and Wound are dealt to the attacker.
+	Countered bool
+	// Extreme success deals maximum damage, Impale adds another damage roll.
+	Extreme bool
+	Impale  bool
+	Damage  *dice.Result
+	Wound   *Wound
+}
+
+// String returns human-readable attack result.
+func (a Attack) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%s attacks %s with %s: rolled %d (%s)", a.Attacker, a.Target, a.Weapon, a.AttackRoll.Roll, a.AttackRoll.Level)
+
+	if a.Outnumbered {
+		sb.WriteString(" with bonus die")
+	}
+
+	if a.DefenceRoll != nil {
+		fmt.Fprintf(&sb, "; %s %s: rolled %d (%s)", a.Target, a.Response, a.DefenceRoll.Roll, a.DefenceRoll.Level)
+	}
+
+	switch {
+	case a.Countered:
+		fmt.Fprintf(&sb, "; %s wins", a.Target)
+	case a.Hit:
+		sb.WriteString("; hit")
+	default:
+		sb.WriteString("; miss")
+	}
+
+	switch {
+	case a.Impale:
+		sb.WriteString(", impale")
+	case a.Extreme:
+		sb.WriteString(", maximum damage")
+	}
+
+	if a.Damage != nil {
+		fmt.Fprintf(&sb, "; damage %d", a.Damage.Total)
+	}
+
+	if a.Wound != nil {
+		fmt.Fprintf(&sb, "; %s", a.Wound)
+	}
+
+	return sb.String()
+}
+
+// Attack resolves attack of the combatant with its weapon against the target.
+// Melee attacks are opposed by the target Dodge (target wins ties) or fight back with its best melee weapon
+// (attacker wins ties). Firearm attacks are not opposed. Targets that already responded this round are
+// outnumbered and melee attackers get a bonus die.
+func (e *Encounter) Attack(r *dice.Roller, attackerID string, weapon int, targetID string, resp Response) (Attack, error) {
+	if e.Finished {
+		return Attack{}, ErrFinished
+	}
+
+	if e.Round == 0 {
+		return Attack{}, ErrNotStarted
+	}
+
+	attacker, err := e.combatant(attackerID)
+	if err != nil {
+		return Attack{}, err
+	}
+
+	target, err := e.combatant(targetID)
+	if err != nil {
+		return Attack{}, err
+	}
+
+	switch {
+	case !attacker.CanAct():
+		return Attack{}, fmt.Errorf("%w: %s is %s", ErrCannotAct, attacker.Name, attacker.Status())
+	case attacker.ID == target.ID:
+		return Attack{}, fmt.Errorf("%w: %s could not attack themselves", ErrInvalidCombatant, attacker.Name)
+	case target.Dead:
+		return Attack{}, fmt.Errorf("%w: %s is dead", ErrInvalidCombatant, target.Name)
+	case weapon < 0 || weapon >= len(attacker.Weapons):
+		return Attack{}, fmt.Errorf("%w: %s has no weapon %d", ErrInvalidCombatant, attacker.Name, weapon)
+	}
+
+	w := attacker.Weapons[weapon]
+
+	if w.Firearm || !target.CanAct() {
+		resp = ResponseNone
+	}
+
+	a := Attack{
+		Attacker:    attacker.Name,
+		Target:      target.Name,
+		Weapon:      w.Name,
+		Response:    resp,
+		Outnumbered: !w.Firearm && target.Responses > 0,
+	}
+
+	bonus := 0
+	if a.Outnumbered {
+		bonus = 1
+	}
+
+	if a.AttackRoll, err = r.Check(w.Skill, bonus, 0); err != nil {
+		return Attack{}, err
+	}
+
+	if err = e.oppose(r, &a, target); err != nil {
+		return Attack{}, err
+	}
+
+	switch {
+	case a.Hit:
+		err = e.hit(r, &a, attacker, w, target)
+	case a.Countered:
+		err = e.counter(r, &a, target, attacker)
+	}
+
+	if err != nil {
+		return Attack{}, err
+	}
+
+	e.logf("%s", a)
+
+	return a, nil
+}
+
+func (e *Encounter) oppose(r *dice.Roller, a *Attack, target *Combatant) error {
+	atk := a.AttackRoll.Level
+
+	var defence int
+
+	switch a.Response {
+	case ResponseDodge:
+		defence = target.Dodge
+	case ResponseFightBack:
+		idx := bestMeleeWeapon(*target)
+		if idx < 0 {
+			return fmt.Errorf("%w: %s has no melee weapon to fight back", ErrInvalidCombatant, target.Name)
+		}
+
+		defence = target.Weapons[idx].Skill
+	default:
+		a.Hit = atk.IsSuccess()
+
+		return nil
+	}
+
+	check, err := r.Check(defence, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	target.Responses++
+
+	a.DefenceRoll = &check
+
+	def := check.Level
+
+	if a.Response == ResponseDodge {
+		a.Hit = atk.IsSuccess() && atk > def
+
+		return nil
+	}
+
+	a.Hit = atk.IsSuccess() && atk >= def
+	a.Countered = !a.Hit && def.IsSuccess()
+
+	return nil
+}
+
+// hit rolls damage of the successful attack. Extreme success deals maximum damage and impaling weapons roll again.
+func (e *Encounter) hit(r *dice.Roller, a *Attack, attacker *Combatant, w Weapon, target *Combatant) error {
+	db := dice.WithDamageBonus(attacker.DamageBonus)
+
+	var (
+		dmg dice.Result
+		err error
+	)
+
+	a.Extreme = a.AttackRoll.Level >= dice.ExtremeSuccess
+
+	if a.Extreme {
+		dmg, err = dice.Max(w.Damage, db)
+	} else {
+		dmg, err = r.Roll(w.Damage, db)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if a.Extreme && w.Impale {
+		extra, err := r.Roll(w.Damage, db)
+		if err != nil {
+			return err
+		}
+
+		a.Impale = true
+
+		dmg.Total += extra.Total
+		dmg.Rolls = append(dmg.Rolls, extra.Rolls...)
+	}
+
+	return applyDamage(r, a, target, dmg)
+}
+
+// counter deals damage of the target best melee weapon to the attacker that lost to fight back.
+func (e *Encounter) counter(r *dice.Roller, a *Attack, target, attacker *Combatant) error {
+	w := target.Weapons[bestMeleeWeapon(*target)]
+
+	dmg, err := r.Roll(w.Damage, dice.WithDamageBonus(target.DamageBonus))
+	if err != nil {
+		return err
+	}
+
+	return applyDamage(r, a, attacker, dmg)
+}
+
+func applyDamage(r *dice.Roller, a *Attack, c *Combatant, dmg dice.Result) error {
+	wound, err := c.TakeDamage(r, dmg.Total)
+	if err != nil {
+		return err
+	}
+
+	a.Damage = &dmg
+	a.Wound = &wound
+
+	return nil
+}
+
+// bestMeleeWeapon returns index of the melee weapon with highest skill or -1.
+func bestMeleeWeapon(c Combatant) int {
+	best := -1
+
+	for i, w := range c.Weapons {
+		if w.Firearm {
+			continue
+		}
+
+		if best < 0 || w.Skill > c.Weapons[best].Skill {
+			best = i
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,386 @@
+package combat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character/charactertest"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice/dicetest"
+)
+
+// investigator returns combatant of the test investigator: DEX 55, CON 45, HP 10, Dodge 27,
+// weapons are Unarmed (35, 1d4+db), .45 semi-automatic (40, 1D10+2) and small knife (35, 1D4+DB).
+func investigator(t *testing.T) Combatant {
+	t.Helper()
+
+	c, err := FromInvestigator("inv", "character-id", charactertest.LoadInvestigator(t))
+	require.NoError(t, err)
+
+	return c
+}
+
+func ghoul() Combatant {
+	return Combatant{
+		ID:          "npc",
+		Name:        "Ghoul",
+		Kind:        KindNPC,
+		DEX:         65,
+		CON:         50,
+		HP:          13,
+		HPMax:       13,
+		Dodge:       32,
+		DamageBonus: "+1D4",
+		Weapons:     []Weapon{{Name: "Claws", Skill: 40, Damage: "1D6+DB"}},
+	}
+}
+
+func newEncounter(t *testing.T) Encounter {
+	t.Helper()
+
+	e := NewEncounter("enc", "", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, e.Add(investigator(t)))
+	require.NoError(t, e.Add(ghoul()))
+
+	return e
+}
+
+func TestFromInvestigator(t *testing.T) {
+	c := investigator(t)
+
+	assert.Equal(t, "Ричард Смит", c.Name)
+	assert.Equal(t, KindInvestigator, c.Kind)
+	assert.Equal(t, "character-id", c.CharacterID)
+	assert.Equal(t, 55, c.DEX)
+	assert.Equal(t, 45, c.CON)
+	assert.Equal(t, 10, c.HP)
+	assert.Equal(t, 10, c.HPMax)
+	assert.Equal(t, 27, c.Dodge)
+	assert.Equal(t, []Weapon{
+		{Name: "Unarmed", Skill: 35, Damage: "1d4+db"},
+		{Name: ".45 semi-automatic", Skill: 40, Damage: "1D10+2", Firearm: true, Impale: true},
+		{Name: "Knife, small (switchblade etc.)", Skill: 35, Damage: "1D4+DB", Impale: true},
+	}, c.Weapons)
+}
+
+func TestEncounter_Add(t *testing.T) {
+	e := newEncounter(t)
+
+	assert.Equal(t, "Encounter", e.Name)
+	require.ErrorIs(t, e.Add(ghoul()), ErrInvalidCombatant)
+
+	bad := ghoul()
+	bad.ID = "bad"
+	bad.Weapons = []Weapon{{Name: "Claws", Skill: 40, Damage: "1D6+foo"}}
+
+	require.ErrorIs(t, e.Add(bad), ErrInvalidCombatant)
+
+	bad.Weapons = nil
+	require.ErrorIs(t, e.Add(bad), ErrInvalidCombatant)
+}
+
+func TestEncounter_Order(t *testing.T) {
+	e := newEncounter(t)
+
+	ids := func() []string {
+		var res []string
+
+		for _, c := range e.Order() {
+			res = append(res, c.ID)
+		}
+
+		return res
+	}
+
+	assert.Equal(t, []string{"npc", "inv"}, ids())
+
+	require.NoError(t, e.ReadyFirearm("inv", true))
+	assert.Equal(t, []string{"inv", "npc"}, ids())
+
+	require.ErrorIs(t, e.ReadyFirearm("unknown", true), ErrUnknownCombatant)
+}
+
+func TestEncounter_Next(t *testing.T) {
+	e := newEncounter(t)
+
+	_, err := e.Attack(dicetest.NewRoller(1), "inv", 0, "npc", ResponseNone)
+	require.ErrorIs(t, err, ErrNotStarted)
+
+	r := dicetest.NewRoller(1, 9)
+
+	require.NoError(t, e.Next(r))
+	assert.Equal(t, 1, e.Round)
+	assert.Equal(t, "npc", e.Acting)
+
+	require.NoError(t, e.Next(r))
+	assert.Equal(t, 1, e.Round)
+	assert.Equal(t, "inv", e.Acting)
+
+	e.Combatants[1].Dying = true
+	e.Combatants[1].Responses = 2
+
+	// Dying ghoul rolls 80 against CON 50 and dies, the turn goes to the investigator.
+	require.NoError(t, e.Next(r))
+	assert.Equal(t, 2, e.Round)
+	assert.Equal(t, "inv", e.Acting)
+	assert.True(t, e.Combatants[1].Dead)
+	assert.Zero(t, e.Combatants[1].Responses)
+
+	e.Finish()
+	require.ErrorIs(t, e.Next(r), ErrFinished)
+}
+
+func TestEncounter_Attack(t *testing.T) {
+	tests := []struct {
+		name     string
+		attacker string
+		weapon   int
+		target   string
+		response Response
+		faces    []int
+		check    func(t *testing.T, a Attack, e Encounter)
+	}{
+		{
+			name:     "firearm ignores dodge and causes major wound",
+			attacker: "inv",
+			weapon:   1,
+			target:   "npc",
+			response: ResponseDodge,
+			// Attack 30, damage 7+2, CON 80.
+			faces: []int{1, 4, 7, 1, 9},
+			check: func(t *testing.T, a Attack, e Encounter) {
+				assert.Equal(t, ResponseNone, a.Response)
+				assert.True(t, a.Hit)
+				assert.Nil(t, a.DefenceRoll)
+				require.NotNil(t, a.Damage)
+				assert.Equal(t, 9, a.Damage.Total)
+
+				npc, err := e.Combatant("npc")
+				require.NoError(t, err)
+				assert.Equal(t, 4, npc.HP)
+				assert.True(t, npc.MajorWound)
+				assert.True(t, npc.Unconscious)
+				assert.Equal(t, "unconscious, major wound", npc.Status())
+			},
+		},
+		{
+			name:     "dodge wins ties",
+			attacker: "inv",
+			weapon:   0,
+			target:   "npc",
+			response: ResponseDodge,
+			// Attack 30 vs 35, dodge 30 vs 32.
+			faces: []int{1, 4, 1, 4},
+			check: func(t *testing.T, a Attack, e Encounter) {
+				assert.False(t, a.Hit)
+				assert.Nil(t, a.Damage)
+				require.NotNil(t, a.DefenceRoll)
+
+				npc, err := e.Combatant("npc")
+				require.NoError(t, err)
+				assert.Equal(t, 1, npc.Responses)
+				assert.Equal(t, 13, npc.HP)
+			},
+		},
+		{
+			name:     "fight back wins and damages attacker",
+			attacker: "npc",
+			weapon:   0,
+			target:   "inv",
+			response: ResponseFightBack,
+			// Attack 80 vs 40, fight back 10 vs 35, unarmed damage 3.
+			faces: []int{1, 9, 1, 2, 3},
+			check: func(t *testing.T, a Attack, e Encounter) {
+				assert.False(t, a.Hit)
+				assert.True(t, a.Countered)
+				require.NotNil(t, a.Wound)
+				assert.Equal(t, 3, a.Wound.Damage)
+
+				npc, err := e.Combatant("npc")
+				require.NoError(t, err)
+				assert.Equal(t, 10, npc.HP)
+			},
+		},
+		{
+			name:     "attacker wins fight back ties",
+			attacker: "npc",
+			weapon:   0,
+			target:   "inv",
+			response: ResponseFightBack,
+			// Attack 30 vs 40, fight back 30 vs 35, claws 2 and damage bonus 1.
+			faces: []int{1, 4, 1, 4, 2, 1},
+			check: func(t *testing.T, a Attack, e Encounter) {
+				assert.True(t, a.Hit)
+				assert.False(t, a.Countered)
+				require.NotNil(t, a.Damage)
+				assert.Equal(t, 3, a.Damage.Total)
+
+				inv, err := e.Combatant("inv")
+				require.NoError(t, err)
+				assert.Equal(t, 7, inv.HP)
+			},
+		},
+		{
+			name:     "extreme success impales",
+			attacker: "inv",
+			weapon:   2,
+			target:   "npc",
+			response: ResponseNone,
+			// Attack 05 vs 35, maximum 4 and another roll 2.
+			faces: []int{6, 1, 2},
+			check: func(t *testing.T, a Attack, e Encounter) {
+				assert.True(t, a.Extreme)
+				assert.True(t, a.Impale)
+				require.NotNil(t, a.Damage)
+				assert.Equal(t, 6, a.Damage.Total)
+			},
+		},
+		{
+			name:     "extreme success without impale deals maximum damage",
+			attacker: "inv",
+			weapon:   0,
+			target:   "npc",
+			response: ResponseNone,
+			faces:    []int{6, 1},
+			check: func(t *testing.T, a Attack, e Encounter) {
+				assert.True(t, a.Extreme)
+				assert.False(t, a.Impale)
+				require.NotNil(t, a.Damage)
+				assert.Equal(t, 4, a.Damage.Total)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEncounter(t)
+			require.NoError(t, e.Next(dicetest.NewRoller(1)))
+
+			a, err := e.Attack(dicetest.NewRoller(tt.faces...), tt.attacker, tt.weapon, tt.target, tt.response)
+			require.NoError(t, err)
+
+			tt.check(t, a, e)
+			assert.Equal(t, a.String(), e.Log[len(e.Log)-1])
+		})
+	}
+}
+
+func TestEncounter_Attack_outnumbered(t *testing.T) {
+	e := newEncounter(t)
+	require.NoError(t, e.Next(dicetest.NewRoller(1)))
+
+	a, err := e.Attack(dicetest.NewRoller(1, 4, 1, 4), "inv", 0, "npc", ResponseDodge)
+	require.NoError(t, err)
+	assert.False(t, a.Outnumbered)
+
+	a, err = e.Attack(dicetest.NewRoller(1, 4, 9, 1, 4), "inv", 0, "npc", ResponseDodge)
+	require.NoError(t, err)
+	assert.True(t, a.Outnumbered)
+	assert.Equal(t, 1, a.AttackRoll.Bonus)
+
+	_, err = e.Attack(dicetest.NewRoller(1), "inv", 5, "npc", ResponseNone)
+	require.ErrorIs(t, err, ErrInvalidCombatant)
+
+	_, err = e.Attack(dicetest.NewRoller(1), "inv", 0, "inv", ResponseNone)
+	require.ErrorIs(t, err, ErrInvalidCombatant)
+
+	e.Combatants[0].Unconscious = true
+
+	_, err = e.Attack(dicetest.NewRoller(1), "inv", 0, "npc", ResponseNone)
+	require.ErrorIs(t, err, ErrCannotAct)
+}
+
+func TestCombatant_TakeDamage(t *testing.T) {
+	tests := []struct {
+		name   string
+		hp     int
+		damage int
+		faces  []int
+		want   Wound
+	}{
+		{name: "regular", hp: 10, damage: 3, want: Wound{Damage: 3, HPFrom: 10, HPTo: 7}},
+		{
+			name: "major wound stays conscious", hp: 10, damage: 5, faces: []int{1, 2},
+			want: Wound{Damage: 5, HPFrom: 10, HPTo: 5, MajorWound: true},
+		},
+		{
+			name: "major wound falls unconscious", hp: 10, damage: 5, faces: []int{1, 9},
+			want: Wound{Damage: 5, HPFrom: 10, HPTo: 5, MajorWound: true, Unconscious: true},
+		},
+		{
+			name: "major wound to zero is dying", hp: 10, damage: 10,
+			want: Wound{Damage: 10, HPFrom: 10, HPTo: 0, MajorWound: true, Dying: true},
+		},
+		{
+			name: "zero without major wound is unconscious", hp: 3, damage: 4,
+			want: Wound{Damage: 4, HPFrom: 3, HPTo: 0, Unconscious: true},
+		},
+		{
+			name: "more than maximum kills", hp: 10, damage: 11,
+			want: Wound{Damage: 11, HPFrom: 10, HPTo: 0, Dead: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Combatant{HP: tt.hp, HPMax: 10, CON: 50}
+
+			got, err := c.TakeDamage(dicetest.NewRoller(append(tt.faces, 1)...), tt.damage)
+			require.NoError(t, err)
+
+			got.CONCheck = nil
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want.HPTo, c.HP)
+			assert.Equal(t, tt.want.Dead, c.Dead)
+			assert.Equal(t, tt.want.Dying, c.Dying)
+			assert.Equal(t, tt.want.Unconscious, c.Unconscious)
+		})
+	}
+}
+
+func TestEncounter_Heal(t *testing.T) {
+	e := newEncounter(t)
+
+	_, err := e.Heal(dicetest.NewRoller(1), "inv", -10)
+	require.NoError(t, err)
+
+	inv, err := e.Combatant("inv")
+	require.NoError(t, err)
+	assert.True(t, inv.Dying)
+
+	w, err := e.Heal(dicetest.NewRoller(1), "inv", 4)
+	require.NoError(t, err)
+	assert.Equal(t, 4, w.HPTo)
+
+	inv, err = e.Combatant("inv")
+	require.NoError(t, err)
+	assert.False(t, inv.Dying)
+}
+
+func TestEncounter_HealUnconscious(t *testing.T) {
+	e := newEncounter(t)
+
+	// Hits below the half of maximum hit points are not major wounds, zero HP knocks out.
+	for _, damage := range []int{4, 4, 2} {
+		_, err := e.Heal(dicetest.NewRoller(1), "inv", -damage)
+		require.NoError(t, err)
+	}
+
+	inv, err := e.Combatant("inv")
+	require.NoError(t, err)
+	require.True(t, inv.Unconscious)
+	require.False(t, inv.Dying)
+
+	w, err := e.Heal(dicetest.NewRoller(1), "inv", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, w.HPTo)
+
+	inv, err = e.Combatant("inv")
+	require.NoError(t, err)
+	assert.False(t, inv.Unconscious)
+	assert.Contains(t, e.Log, inv.Name+" regains consciousness")
+}
@@ -0,0 +1,242 @@
+// Package combat implements Call of Cthulhu 7e combat: DEX ordered rounds, opposed attacks and wounds.
+package combat
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/skills"
+)
+
+var (
+	// ErrInvalidCombatant is returned when combatant could not be added to the encounter.
+	ErrInvalidCombatant = errors.New("invalid combatant")
+	// ErrUnknownCombatant is returned when encounter has no combatant with given ID.
+	ErrUnknownCombatant = errors.New("unknown combatant")
+	// ErrCannotAct is returned when incapacitated combatant tries to act.
+	ErrCannotAct = errors.New("combatant cannot act")
+)
+
+// Kind of the combatant.
+type Kind string
+
+const (
+	KindInvestigator Kind = "investigator"
+	KindNPC          Kind = "npc"
+)
+
+const (
+	unarmedName   = "Unarmed"
+	unarmedSkill  = skills.Fighting + " (Brawl)"
+	unarmedDamage = "1D3+DB"
+)
+
+// impalingWords mark weapons that impale on Extreme success besides firearms.
+var impalingWords = []string{"knife", "dagger", "sword", "sabre", "rapier", "spear", "bayonet", "arrow", "bow", "machete"}
+
+// Weapon is a weapon used by combatant with the skill value to attack with it.
+type Weapon struct {
+	Name   string `json:"name"`
+	Skill  int    `json:"skill"`
+	Damage string `json:"damage"`
+	// Firearm attacks could not be dodged or fought back in melee.
+	Firearm bool `json:"firearm,omitempty"`
+	// Impale weapons roll damage twice on Extreme success.
+	Impale bool `json:"impale,omitempty"`
+}
+
+// Combatant is an investigator or NPC taking part in the encounter.
+type Combatant struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+	// CharacterID links investigator combatant to the stored character.
+	CharacterID string `json:"character_id,omitempty"`
+
+	DEX         int      `json:"dex"`
+	CON         int      `json:"con"`
+	HP          int      `json:"hp"`
+	HPMax       int      `json:"hp_max"`
+	Dodge       int      `json:"dodge"`
+	DamageBonus string   `json:"damage_bonus"`
+	Weapons     []Weapon `json:"weapons"`
+
+	// FirearmReadied adds 50 to DEX for the initiative order.
+	FirearmReadied bool `json:"firearm_readied,omitempty"`
+	// Responses counts dodges and fight backs in the current round, attackers of outnumbered target get a bonus die.
+	Responses int `json:"responses,omitempty"`
+
+	MajorWound  bool `json:"major_wound,omitempty"`
+	Unconscious bool `json:"unconscious,omitempty"`
+	Dying       bool `json:"dying,omitempty"`
+	Dead        bool `json:"dead,omitempty"`
+}
+
+// readiedFirearmBonus is added to DEX of combatant with readied firearm.
+const readiedFirearmBonus = 50
+
+// Initiative returns combatant DEX used for the order of action.
+func (c Combatant) Initiative() int {
+	if c.FirearmReadied {
+		return c.DEX + readiedFirearmBonus
+	}
+
+	return c.DEX
+}
+
+// CanAct reports whether combatant is able to take actions.
+func (c Combatant) CanAct() bool {
+	return !c.Unconscious && !c.Dying && !c.Dead
+}
+
+// Status returns human-readable combatant state.
+func (c Combatant) Status() string {
+	var st []string
+
+	switch {
+	case c.Dead:
+		return "dead"
+	case c.Dying:
+		st = append(st, "dying")
+	case c.Unconscious:
+		st = append(st, "unconscious")
+	}
+
+	if c.MajorWound {
+		st = append(st, "major wound")
+	}
+
+	if len(st) == 0 {
+		return "ok"
+	}
+
+	return strings.Join(st, ", ")
+}
+
+// Validate checks that combatant could take part in the combat.
+func (c Combatant) Validate() error {
+	switch {
+	case strings.TrimSpace(c.ID) == "":
+		return fmt.Errorf("%w: empty id", ErrInvalidCombatant)
+	case strings.TrimSpace(c.Name) == "":
+		return fmt.Errorf("%w: empty name", ErrInvalidCombatant)
+	case c.HPMax <= 0:
+		return fmt.Errorf("%w: %s: hit points should be positive", ErrInvalidCombatant, c.Name)
+	case len(c.Weapons) == 0:
+		return fmt.Errorf("%w: %s: no weapons", ErrInvalidCombatant, c.Name)
+	}
+
+	for _, w := range c.Weapons {
+		if _, err := dice.Max(w.Damage, dice.WithDamageBonus(c.DamageBonus)); err != nil {
+			return fmt.Errorf("%w: %s: %s: %w", ErrInvalidCombatant, c.Name, w.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// FromInvestigator creates combatant from investigator sheet.
+// Weapons skill values are taken from the weapon or its skill, Unarmed is added when sheet has no weapons.
+func FromInvestigator(id, characterID string, inv character.InvestigatorClass) (Combatant, error) {
+	stats, err := inv.Characteristics.Stats()
+	if err != nil {
+		return Combatant{}, fmt.Errorf("%w: %w", ErrInvalidCombatant, err)
+	}
+
+	c := inv.Characteristics
+
+	hp, err := character.ParseNumber(c.HitPts)
+	if err != nil {
+		return Combatant{}, fmt.Errorf("%w: hit points: %w", ErrInvalidCombatant, err)
+	}
+
+	hpMax, err := character.ParseNumber(c.HitPtsMax)
+	if err != nil {
+		return Combatant{}, fmt.Errorf("%w: max hit points: %w", ErrInvalidCombatant, err)
+	}
+
+	if hpMax == 0 {
+		hpMax = character.Derive(stats, 0).HitPoints
+	}
+
+	if hp == 0 && c.HitPts == "" {
+		hp = hpMax
+	}
+
+	cmb := Combatant{
+		ID:          id,
+		Name:        inv.PersonalDetails.Name,
+		Kind:        KindInvestigator,
+		CharacterID: characterID,
+		DEX:         stats.DEX.Full,
+		CON:         stats.CON.Full,
+		HP:          hp,
+		HPMax:       hpMax,
+		Dodge:       investigatorDodge(inv, stats),
+		DamageBonus: inv.DamageBonus(),
+	}
+
+	for _, w := range inv.Weapons.Weapon {
+		cmb.Weapons = append(cmb.Weapons, investigatorWeapon(inv, w))
+	}
+
+	if len(cmb.Weapons) == 0 {
+		skill, _ := inv.CheckTarget(unarmedSkill)
+
+		cmb.Weapons = append(cmb.Weapons, Weapon{Name: unarmedName, Skill: skill, Damage: unarmedDamage})
+	}
+
+	if cmb.Name == "" {
+		cmb.Name = characterID
+	}
+
+	return cmb, cmb.Validate()
+}
+
+func investigatorDodge(inv character.InvestigatorClass, stats character.Stats) int {
+	if v, err := character.ParseNumber(inv.Combat.Dodge.Value); err == nil && v > 0 {
+		return v
+	}
+
+	if v, err := inv.CheckTarget(skills.Dodge); err == nil && v > 0 {
+		return v
+	}
+
+	return stats.DEX.Full / 2 //nolint:mnd // Dodge base is half DEX.
+}
+
+func investigatorWeapon(inv character.InvestigatorClass, w character.Weapon) Weapon {
+	cw := Weapon{
+		Name:   w.Name,
+		Damage: w.Damage,
+	}
+
+	def, _, known := skills.Lookup(w.Skillname)
+	skill, found := inv.FindSkill(w.Skillname)
+
+	cw.Firearm = (known && def.Name == skills.Firearms) || (found && strings.EqualFold(skill.Name, skills.Firearms))
+	cw.Impale = cw.Firearm || isImpaling(w.Name)
+
+	if v, err := character.ParseNumber(w.Regular); err == nil && v > 0 {
+		cw.Skill = v
+	} else if found {
+		cw.Skill, _ = character.ParseNumber(skill.Value)
+	}
+
+	return cw
+}
+
+func isImpaling(name string) bool {
+	name = strings.ToLower(name)
+
+	for _, w := range impalingWords {
+		if strings.Contains(name, w) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,248 @@
+package combat
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+var (
+	// ErrFinished is returned when finished encounter is modified.
+	ErrFinished = errors.New("encounter is finished")
+	// ErrNotStarted is returned when turn actions are taken before the first round.
+	ErrNotStarted = errors.New("encounter is not started")
+)
+
+// Encounter is a combat tracked by the Keeper.
+type Encounter struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Round is the current round, zero means combat has not started yet.
+	Round int `json:"round"`
+	// Acting is ID of combatant whose turn it is.
+	Acting     string      `json:"acting,omitempty"`
+	Combatants []Combatant `json:"combatants"`
+	// Log describes rolls and their results, oldest first.
+	Log       []string  `json:"log,omitempty"`
+	Finished  bool      `json:"finished,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// KeeperID is ID of the user running the encounter, empty for encounters created without authentication.
+	KeeperID string `json:"keeper_id,omitempty"`
+}
+
+// NewEncounter creates empty encounter.
+func NewEncounter(id, name string, now time.Time) Encounter {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Encounter"
+	}
+
+	return Encounter{
+		ID:        id,
+		Name:      name,
+		CreatedAt: now,
+	}
+}
+
+// Add adds combatant to the encounter.
+func (e *Encounter) Add(c Combatant) error {
+	if e.Finished {
+		return ErrFinished
+	}
+
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := e.combatant(c.ID); err == nil {
+		return fmt.Errorf("%w: %s is already in the encounter", ErrInvalidCombatant, c.Name)
+	}
+
+	e.Combatants = append(e.Combatants, c)
+
+	e.logf("%s joins the combat", c.Name)
+
+	return nil
+}
+
+// Combatant returns combatant by ID.
+func (e Encounter) Combatant(id string) (Combatant, error) {
+	c, err := e.combatant(id)
+	if err != nil {
+		return Combatant{}, err
+	}
+
+	return *c, nil
+}
+
+func (e *Encounter) combatant(id string) (*Combatant, error) {
+	for i := range e.Combatants {
+		if e.Combatants[i].ID == id {
+			return &e.Combatants[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownCombatant, id)
+}
+
+// Order returns combatants in order of action: highest DEX first, readied firearms add 50.
+func (e Encounter) Order() []Combatant {
+	order := slices.Clone(e.Combatants)
+
+	slices.SortStableFunc(order, func(a, b Combatant) int {
+		return cmp.Compare(b.Initiative(), a.Initiative())
+	})
+
+	return order
+}
+
+// ReadyFirearm sets whether combatant has a firearm readied, it changes the order of action.
+func (e *Encounter) ReadyFirearm(id string, readied bool) error {
+	if e.Finished {
+		return ErrFinished
+	}
+
+	c, err := e.combatant(id)
+	if err != nil {
+		return err
+	}
+
+	c.FirearmReadied = readied
+
+	return nil
+}
+
+// Next passes the turn to the next combatant able to act, the first call starts the combat.
+// Passing the turn from the last combatant starts a new round:
+// outnumbering is reset and each dying combatant makes a CON roll or dies.
+func (e *Encounter) Next(r *dice.Roller) error {
+	if e.Finished {
+		return ErrFinished
+	}
+
+	order := e.Order()
+
+	pos := slices.IndexFunc(order, func(c Combatant) bool {
+		return c.ID == e.Acting
+	})
+
+	if e.Round == 0 {
+		pos = len(order)
+	}
+
+	for i := pos + 1; i < len(order); i++ {
+		if order[i].CanAct() {
+			e.Acting = order[i].ID
+
+			return nil
+		}
+	}
+
+	return e.newRound(r, order)
+}
+
+func (e *Encounter) newRound(r *dice.Roller, order []Combatant) error {
+	e.Round++
+	e.Acting = ""
+
+	e.logf("Round %d", e.Round)
+
+	for i := range e.Combatants {
+		c := &e.Combatants[i]
+
+		c.Responses = 0
+
+		if !c.Dying || c.Dead {
+			continue
+		}
+
+		check, err := r.Check(c.CON, 0, 0)
+		if err != nil {
+			return err
+		}
+
+		if !check.Level.IsSuccess() {
+			c.Dead = true
+
+			e.logf("%s fails CON roll (%d) and dies", c.Name, check.Roll)
+
+			continue
+		}
+
+		e.logf("%s passes CON roll (%d) and clings to life", c.Name, check.Roll)
+	}
+
+	for _, c := range order {
+		if cur, _ := e.Combatant(c.ID); cur.CanAct() {
+			e.Acting = c.ID
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// Finish ends the combat.
+func (e *Encounter) Finish() {
+	e.Finished = true
+	e.Acting = ""
+
+	e.logf("Combat is over")
+}
+
+// Heal restores combatant hit points up to maximum, negative amount is a damage without a roll.
+// Healed above zero combatant is no longer dying and regains consciousness.
+func (e *Encounter) Heal(r *dice.Roller, id string, amount int) (Wound, error) {
+	if e.Finished {
+		return Wound{}, ErrFinished
+	}
+
+	c, err := e.combatant(id)
+	if err != nil {
+		return Wound{}, err
+	}
+
+	if amount < 0 {
+		w, err := c.TakeDamage(r, -amount)
+		if err != nil {
+			return Wound{}, err
+		}
+
+		e.logf("%s takes %d damage: %s", c.Name, -amount, w)
+
+		return w, nil
+	}
+
+	if c.Dead {
+		return Wound{}, fmt.Errorf("%w: %s is dead", ErrCannotAct, c.Name)
+	}
+
+	w := Wound{HPFrom: c.HP}
+
+	c.HP = min(c.HP+amount, c.HPMax)
+	w.HPTo = c.HP
+
+	e.logf("%s heals %d HP: %d → %d", c.Name, amount, w.HPFrom, w.HPTo)
+
+	if c.HP > 0 {
+		c.Dying = false
+
+		if c.Unconscious {
+			c.Unconscious = false
+
+			e.logf("%s regains consciousness", c.Name)
+		}
+	}
+
+	return w, nil
+}
+
+func (e *Encounter) logf(format string, args ...any) {
+	e.Log = append(e.Log, fmt.Sprintf(format, args...))
+}
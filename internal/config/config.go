@@ -1,12 +1,22 @@
 package config
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/obalunenko/getenv"
 	"github.com/obalunenko/getenv/option"
 	log "github.com/obalunenko/logger"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -14,11 +24,101 @@
 	hostEnv   = "HOST"
 	levelEnv  = "LOG_LEVEL"
 	formatEnv = "LOG_FORMAT"
+
+	readHeaderTimeoutEnv = "HTTP_READ_HEADER_TIMEOUT"
+	readTimeoutEnv       = "HTTP_READ_TIMEOUT"
+	writeTimeoutEnv      = "HTTP_WRITE_TIMEOUT"
+	idleTimeoutEnv       = "HTTP_IDLE_TIMEOUT"
+	shutdownTimeoutEnv   = "HTTP_SHUTDOWN_TIMEOUT"
+
+	storageTypeEnv = "STORAGE_TYPE"
+	storagePathEnv = "STORAGE_PATH"
+
+	authEnabledEnv      = "AUTH_ENABLED"
+	authSessionTTLEnv   = "AUTH_SESSION_TTL"
+	authSecureCookieEnv = "AUTH_SECURE_COOKIE"
+
+	tlsEnabledEnv      = "TLS_ENABLED"
+	tlsCertFileEnv     = "TLS_CERT_FILE"
+	tlsKeyFileEnv      = "TLS_KEY_FILE"
+	tlsRedirectPortEnv = "TLS_REDIRECT_PORT"
 )
 
+const (
+	// StorageMemory keeps characters in memory, they are lost on restart.
+	StorageMemory = "memory"
+	// StorageBolt keeps characters in an embedded bbolt database file.
+	StorageBolt = "bolt"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// ErrInvalid is returned when configuration values are not valid.
+var ErrInvalid = errors.New("invalid config")
+
+// Duration is a time.Duration written as "90s" or "12h" in config files.
+type Duration time.Duration
+
+// UnmarshalText parses duration string.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
+
+// MarshalText formats duration as a string.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Port is a TCP port. Config files could set it both as a number and as a string.
+type Port string
+
+// UnmarshalJSON accepts port as a JSON number or string.
+func (p *Port) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		var s string
+
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		*p = Port(s)
+
+		return nil
+	}
+
+	var n json.Number
+
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("port should be a number or a string: %w", err)
+	}
+
+	*p = Port(n)
+
+	return nil
+}
+
 type httpConfig struct {
-	Port string `yaml:"port" json:"port"`
+	Port Port   `yaml:"port" json:"port"`
 	Host string `yaml:"host" json:"host"`
+
+	ReadHeaderTimeout Duration `yaml:"read_header_timeout" json:"read_header_timeout"`
+	// ReadTimeout limits reading the whole request including uploaded sheets.
+	ReadTimeout Duration `yaml:"read_timeout" json:"read_timeout"`
+	// WriteTimeout limits writing the response including rendered PDF sheets.
+	WriteTimeout Duration `yaml:"write_timeout" json:"write_timeout"`
+	IdleTimeout  Duration `yaml:"idle_timeout" json:"idle_timeout"`
+	// ShutdownTimeout is a grace period for in-flight requests on shutdown.
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" json:"shutdown_timeout"`
 }
 
 type logConfig struct {
@@ -26,9 +126,34 @@ type logConfig struct {
 	Format string `yaml:"format" json:"format"`
 }
 
+type storageConfig struct {
+	Type string `yaml:"type" json:"type"`
+	Path string `yaml:"path" json:"path"`
+}
+
+type authConfig struct {
+	// Enabled requires users to log in, without it everyone has full access.
+	Enabled    bool     `yaml:"enabled" json:"enabled"`
+	SessionTTL Duration `yaml:"session_ttl" json:"session_ttl"`
+	// SecureCookie marks session cookie to be sent over HTTPS only.
+	SecureCookie bool `yaml:"secure_cookie" json:"secure_cookie"`
+}
+
+type tlsConfig struct {
+	// Enabled serves HTTPS with the certificate and key files.
+	Enabled  bool   `yaml:"enabled" json:"enabled"`
+	CertFile string `yaml:"cert_file" json:"cert_file"`
+	KeyFile  string `yaml:"key_file" json:"key_file"`
+	// RedirectPort starts plain HTTP listener on the port redirecting all requests to HTTPS, empty disables it.
+	RedirectPort Port `yaml:"redirect_port" json:"redirect_port"`
+}
+
 type Config struct {
-	HTTP httpConfig `yaml:"http" json:"http"`
-	Log  logConfig  `yaml:"log" json:"log"`
+	HTTP    httpConfig    `yaml:"http" json:"http"`
+	Log     logConfig     `yaml:"log" json:"log"`
+	Storage storageConfig `yaml:"storage" json:"storage"`
+	Auth    authConfig    `yaml:"auth" json:"auth"`
+	TLS     tlsConfig     `yaml:"tls" json:"tls"`
 }
 
 func DefaultConfig() *Config {
@@ -36,19 +161,167 @@ func DefaultConfig() *Config {
 		HTTP: httpConfig{
 			Port: "8080",
 			Host: "0.0.0.0",
+
+			ReadHeaderTimeout: Duration(5 * time.Second),
+			ReadTimeout:       Duration(30 * time.Second),
+			WriteTimeout:      Duration(60 * time.Second),
+			IdleTimeout:       Duration(120 * time.Second),
+			ShutdownTimeout:   Duration(15 * time.Second),
 		},
 		Log: logConfig{
 			Level:  "INFO",
-			Format: "text",
+			Format: formatText,
+		},
+		Storage: storageConfig{
+			Type: StorageMemory,
+			Path: "data/cthulhu-mythos-tools.db",
+		},
+		Auth: authConfig{
+			Enabled:      true,
+			SessionTTL:   Duration(7 * 24 * time.Hour),
+			SecureCookie: false,
+		},
+		TLS: tlsConfig{
+			Enabled: false,
 		},
 	}
 }
 
-func Load(ctx context.Context) (*Config, error) {
-	return loadFromEnv(ctx)
+// Load returns validated config. Values are taken from defaults, overridden by the config file at path
+// (YAML or JSON by extension, skipped when path is empty), overridden by environment variables.
+func Load(ctx context.Context, path string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	if path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := loadFromEnv(ctx, cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
 }
 
-func loadEnv[T string | []uint](ctx context.Context, key string, defaultVal T, opts ...option.Option) (T, error) {
+// loadFile decodes config file over cfg values, keys missing in the file keep their values.
+func loadFile(path string, cfg *Config) error {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(content))
+		dec.KnownFields(true)
+
+		err = dec.Decode(cfg)
+		if errors.Is(err, io.EOF) {
+			// Empty file.
+			err = nil
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(content))
+		dec.DisallowUnknownFields()
+
+		err = dec.Decode(cfg)
+	default:
+		return fmt.Errorf("config file %q: unsupported extension %q, use .yaml, .yml or .json", path, ext)
+	}
+
+	if err != nil {
+		return fmt.Errorf("decode config file %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// Validate checks config values and returns all found problems joined.
+func (c *Config) Validate() error {
+	var errs []error
+
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalid}, args...)...))
+	}
+
+	if !validPort(c.HTTP.Port) {
+		invalid("http port %q should be a number from 1 to 65535", c.HTTP.Port)
+	}
+
+	for _, t := range []struct {
+		name string
+		d    Duration
+	}{
+		{name: "read header timeout", d: c.HTTP.ReadHeaderTimeout},
+		{name: "read timeout", d: c.HTTP.ReadTimeout},
+		{name: "write timeout", d: c.HTTP.WriteTimeout},
+		{name: "idle timeout", d: c.HTTP.IdleTimeout},
+		{name: "shutdown timeout", d: c.HTTP.ShutdownTimeout},
+	} {
+		if t.d <= 0 {
+			invalid("http %s %s should be positive", t.name, time.Duration(t.d))
+		}
+	}
+
+	if _, err := log.ParseLevel(c.Log.Level); err != nil {
+		invalid("log level %q should be one of DEBUG, INFO, WARN, ERROR, FATAL", c.Log.Level)
+	}
+
+	if c.Log.Format != formatText && c.Log.Format != formatJSON {
+		invalid("log format %q should be %q or %q", c.Log.Format, formatText, formatJSON)
+	}
+
+	switch c.Storage.Type {
+	case StorageMemory:
+	case StorageBolt:
+		if c.Storage.Path == "" {
+			invalid("storage path is required for %q storage", StorageBolt)
+		}
+	default:
+		invalid("storage type %q should be %q or %q", c.Storage.Type, StorageMemory, StorageBolt)
+	}
+
+	if c.Auth.SessionTTL <= 0 {
+		invalid("auth session ttl %s should be positive", time.Duration(c.Auth.SessionTTL))
+	}
+
+	if c.TLS.Enabled {
+		if c.TLS.CertFile == "" {
+			invalid("tls cert file is required when tls is enabled")
+		}
+
+		if c.TLS.KeyFile == "" {
+			invalid("tls key file is required when tls is enabled")
+		}
+	}
+
+	if c.TLS.RedirectPort != "" {
+		switch {
+		case !validPort(c.TLS.RedirectPort):
+			invalid("tls redirect port %q should be a number from 1 to 65535", c.TLS.RedirectPort)
+		case c.TLS.RedirectPort == c.HTTP.Port:
+			invalid("tls redirect port %q should differ from http port", c.TLS.RedirectPort)
+		case !c.TLS.Enabled:
+			invalid("tls redirect port is set while tls is disabled")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validPort(p Port) bool {
+	port, err := strconv.Atoi(string(p))
+
+	return err == nil && port >= 1 && port <= 65535
+}
+
+func loadEnv[T string | []uint | bool | time.Duration](ctx context.Context, key string, defaultVal T, opts ...option.Option) (T, error) {
 	val, err := getenv.Env[T](key, opts...)
 	if err != nil {
 		if !errors.Is(err, getenv.ErrNotSet) {
@@ -56,9 +329,9 @@ func loadEnv[T string | []uint](ctx context.Context, key string, defaultVal T, o
 		}
 
 		log.WithFields(ctx, log.Fields{
-			"env":     key,
-			"default": defaultVal,
-		}).Warn("Env not set - using default")
+			"env":   key,
+			"value": defaultVal,
+		}).Debug("Env not set - using configured value")
 
 		val = defaultVal
 	}
@@ -66,43 +339,61 @@ func loadEnv[T string | []uint](ctx context.Context, key string, defaultVal T, o
 	return val, nil
 }
 
-func loadFromEnv(ctx context.Context) (*Config, error) {
+// loadFromEnv overrides cfg values by the set environment variables.
+func loadFromEnv(ctx context.Context, cfg *Config) error {
 	var errs error
 
-	dflt := DefaultConfig()
+	setEnv := func(key string, dst *string) {
+		v, err := loadEnv[string](ctx, key, *dst)
+		if err != nil {
+			errs = errors.Join(errs, err)
 
-	port, err := loadEnv[string](ctx, portEnv, dflt.HTTP.Port)
-	if err != nil {
-		errs = errors.Join(errs, err)
-	}
+			return
+		}
 
-	host, err := loadEnv[string](ctx, hostEnv, dflt.HTTP.Host)
-	if err != nil {
-		errs = errors.Join(errs, err)
+		*dst = v
 	}
 
-	level, err := loadEnv[string](ctx, levelEnv, dflt.Log.Level)
-	if err != nil {
-		errs = errors.Join(errs, err)
-	}
+	setBoolEnv := func(key string, dst *bool) {
+		v, err := loadEnv[bool](ctx, key, *dst)
+		if err != nil {
+			errs = errors.Join(errs, err)
 
-	format, err := loadEnv[string](ctx, formatEnv, dflt.Log.Format)
-	if err != nil {
-		errs = errors.Join(errs, err)
+			return
+		}
+
+		*dst = v
 	}
 
-	if errs != nil {
-		return nil, errs
+	setDurationEnv := func(key string, dst *Duration) {
+		v, err := loadEnv[time.Duration](ctx, key, time.Duration(*dst))
+		if err != nil {
+			errs = errors.Join(errs, err)
+
+			return
+		}
+
+		*dst = Duration(v)
 	}
 
-	return &Config{
-		HTTP: httpConfig{
-			Port: port,
-			Host: host,
-		},
-		Log: logConfig{
-			Level:  level,
-			Format: format,
-		},
-	}, nil
+	setEnv(portEnv, (*string)(&cfg.HTTP.Port))
+	setEnv(hostEnv, &cfg.HTTP.Host)
+	setDurationEnv(readHeaderTimeoutEnv, &cfg.HTTP.ReadHeaderTimeout)
+	setDurationEnv(readTimeoutEnv, &cfg.HTTP.ReadTimeout)
+	setDurationEnv(writeTimeoutEnv, &cfg.HTTP.WriteTimeout)
+	setDurationEnv(idleTimeoutEnv, &cfg.HTTP.IdleTimeout)
+	setDurationEnv(shutdownTimeoutEnv, &cfg.HTTP.ShutdownTimeout)
+	setEnv(levelEnv, &cfg.Log.Level)
+	setEnv(formatEnv, &cfg.Log.Format)
+	setEnv(storageTypeEnv, &cfg.Storage.Type)
+	setEnv(storagePathEnv, &cfg.Storage.Path)
+	setBoolEnv(authEnabledEnv, &cfg.Auth.Enabled)
+	setDurationEnv(authSessionTTLEnv, &cfg.Auth.SessionTTL)
+	setBoolEnv(authSecureCookieEnv, &cfg.Auth.SecureCookie)
+	setBoolEnv(tlsEnabledEnv, &cfg.TLS.Enabled)
+	setEnv(tlsCertFileEnv, &cfg.TLS.CertFile)
+	setEnv(tlsKeyFileEnv, &cfg.TLS.KeyFile)
+	setEnv(tlsRedirectPortEnv, (*string)(&cfg.TLS.RedirectPort))
+
+	return errs
 }
@@ -2,7 +2,9 @@
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,8 +17,22 @@ func unsetEnv(tb testing.TB) {
 
 	tb.Setenv(portEnv, "")
 	tb.Setenv(hostEnv, "")
+	tb.Setenv(readHeaderTimeoutEnv, "")
+	tb.Setenv(readTimeoutEnv, "")
+	tb.Setenv(writeTimeoutEnv, "")
+	tb.Setenv(idleTimeoutEnv, "")
+	tb.Setenv(shutdownTimeoutEnv, "")
 	tb.Setenv(levelEnv, "")
 	tb.Setenv(formatEnv, "")
+	tb.Setenv(storageTypeEnv, "")
+	tb.Setenv(storagePathEnv, "")
+	tb.Setenv(authEnabledEnv, "")
+	tb.Setenv(authSessionTTLEnv, "")
+	tb.Setenv(authSecureCookieEnv, "")
+	tb.Setenv(tlsEnabledEnv, "")
+	tb.Setenv(tlsCertFileEnv, "")
+	tb.Setenv(tlsKeyFileEnv, "")
+	tb.Setenv(tlsRedirectPortEnv, "")
 }
 
 func TestLoadDefault(t *testing.T) {
@@ -25,7 +41,7 @@ func TestLoadDefault(t *testing.T) {
 	unsetEnv(t)
 
 	t.Run("default", func(t *testing.T) {
-		cfg, err := Load(ctx)
+		cfg, err := Load(ctx, "")
 		require.NoError(t, err)
 
 		require.Equal(t, DefaultConfig(), cfg)
@@ -35,7 +51,7 @@ func TestLoadDefault(t *testing.T) {
 		t.Run("port", func(t *testing.T) {
 			t.Setenv(portEnv, "8081")
 
-			cfg, err := Load(ctx)
+			cfg, err := Load(ctx, "")
 			require.NoError(t, err)
 
 			expected := DefaultConfig()
@@ -46,7 +62,7 @@ func TestLoadDefault(t *testing.T) {
 		t.Run("host", func(t *testing.T) {
 			t.Setenv(hostEnv, "127.0.0.1")
 
-			cfg, err := Load(ctx)
+			cfg, err := Load(ctx, "")
 			require.NoError(t, err)
 
 			expected := DefaultConfig()
@@ -54,10 +70,23 @@ func TestLoadDefault(t *testing.T) {
 
 			assert.Equal(t, expected, cfg)
 		})
+		t.Run("timeouts", func(t *testing.T) {
+			t.Setenv(writeTimeoutEnv, "2m")
+			t.Setenv(shutdownTimeoutEnv, "30s")
+
+			cfg, err := Load(ctx, "")
+			require.NoError(t, err)
+
+			expected := DefaultConfig()
+			expected.HTTP.WriteTimeout = Duration(2 * time.Minute)
+			expected.HTTP.ShutdownTimeout = Duration(30 * time.Second)
+
+			assert.Equal(t, expected, cfg)
+		})
 		t.Run("level", func(t *testing.T) {
 			t.Setenv(levelEnv, "DEBUG")
 
-			cfg, err := Load(ctx)
+			cfg, err := Load(ctx, "")
 			require.NoError(t, err)
 
 			expected := DefaultConfig()
@@ -68,7 +97,7 @@ func TestLoadDefault(t *testing.T) {
 		t.Run("format", func(t *testing.T) {
 			t.Setenv(formatEnv, "json")
 
-			cfg, err := Load(ctx)
+			cfg, err := Load(ctx, "")
 			require.NoError(t, err)
 
 			expected := DefaultConfig()
@@ -76,5 +105,205 @@ func TestLoadDefault(t *testing.T) {
 
 			assert.Equal(t, expected, cfg)
 		})
+		t.Run("storage", func(t *testing.T) {
+			t.Setenv(storageTypeEnv, StorageBolt)
+			t.Setenv(storagePathEnv, "/var/lib/cthulhu/db")
+
+			cfg, err := Load(ctx, "")
+			require.NoError(t, err)
+
+			expected := DefaultConfig()
+			expected.Storage.Type = StorageBolt
+			expected.Storage.Path = "/var/lib/cthulhu/db"
+
+			assert.Equal(t, expected, cfg)
+		})
+		t.Run("auth", func(t *testing.T) {
+			t.Setenv(authEnabledEnv, "false")
+			t.Setenv(authSessionTTLEnv, "12h")
+			t.Setenv(authSecureCookieEnv, "true")
+
+			cfg, err := Load(ctx, "")
+			require.NoError(t, err)
+
+			expected := DefaultConfig()
+			expected.Auth.Enabled = false
+			expected.Auth.SessionTTL = Duration(12 * time.Hour)
+			expected.Auth.SecureCookie = true
+
+			assert.Equal(t, expected, cfg)
+		})
+		t.Run("wrong auth ttl", func(t *testing.T) {
+			t.Setenv(authSessionTTLEnv, "week")
+
+			_, err := Load(ctx, "")
+			require.Error(t, err)
+		})
 	})
 }
+
+func TestLoadFile(t *testing.T) {
+	ctx := testlogger.New(context.Background())
+
+	unsetEnv(t)
+
+	expected := DefaultConfig()
+	expected.HTTP.Port = "9090"
+	expected.HTTP.WriteTimeout = Duration(2 * time.Minute)
+	expected.Log.Level = "DEBUG"
+	expected.Log.Format = "json"
+	expected.Storage.Type = StorageBolt
+	expected.Storage.Path = "/var/lib/cthulhu/db"
+	expected.Auth.SessionTTL = Duration(12 * time.Hour)
+	expected.TLS.Enabled = true
+	expected.TLS.CertFile = "/etc/cthulhu/cert.pem"
+	expected.TLS.KeyFile = "/etc/cthulhu/key.pem"
+	expected.TLS.RedirectPort = "8080"
+
+	for _, path := range []string{"testdata/config.yaml", "testdata/config.json"} {
+		t.Run(path, func(t *testing.T) {
+			cfg, err := Load(ctx, path)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, cfg)
+		})
+	}
+
+	t.Run("env overrides file", func(t *testing.T) {
+		t.Setenv(portEnv, "8081")
+		t.Setenv(authSessionTTLEnv, "1h")
+
+		cfg, err := Load(ctx, "testdata/config.yaml")
+		require.NoError(t, err)
+
+		assert.Equal(t, Port("8081"), cfg.HTTP.Port)
+		assert.Equal(t, Duration(time.Hour), cfg.Auth.SessionTTL)
+		assert.Equal(t, "DEBUG", cfg.Log.Level)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := Load(ctx, "testdata/unknown.yaml")
+		require.ErrorContains(t, err, "prot")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := Load(ctx, "testdata/missing.yaml")
+		require.Error(t, err)
+	})
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		_, err := Load(ctx, "testdata/config.toml")
+		require.Error(t, err)
+	})
+}
+
+func TestPort_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Port
+		wantErr bool
+	}{
+		{in: `8443`, want: "8443"},
+		{in: `"8443"`, want: "8443"},
+		{in: `""`, want: ""},
+		{in: `true`, wantErr: true},
+		{in: `[8443]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var got Port
+
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		errs   []string
+	}{
+		{
+			name:   "default",
+			modify: func(*Config) {},
+		},
+		{
+			name: "port",
+			modify: func(cfg *Config) {
+				cfg.HTTP.Port = "65536"
+			},
+			errs: []string{"http port"},
+		},
+		{
+			name: "log",
+			modify: func(cfg *Config) {
+				cfg.Log.Level = "verbose"
+				cfg.Log.Format = "xml"
+			},
+			errs: []string{"log level", "log format"},
+		},
+		{
+			name: "storage",
+			modify: func(cfg *Config) {
+				cfg.Storage.Type = StorageBolt
+				cfg.Storage.Path = ""
+			},
+			errs: []string{"storage path"},
+		},
+		{
+			name: "tls redirect",
+			modify: func(cfg *Config) {
+				cfg.TLS.RedirectPort = cfg.HTTP.Port
+			},
+			errs: []string{"should differ from http port"},
+		},
+		{
+			name: "tls redirect without tls",
+			modify: func(cfg *Config) {
+				cfg.TLS.RedirectPort = "80"
+			},
+			errs: []string{"tls is disabled"},
+		},
+		{
+			name: "all",
+			modify: func(cfg *Config) {
+				cfg.HTTP.Port = "http"
+				cfg.HTTP.ShutdownTimeout = 0
+				cfg.Storage.Type = "postgres"
+				cfg.Auth.SessionTTL = 0
+				cfg.TLS.Enabled = true
+			},
+			errs: []string{"http port", "http shutdown timeout", "storage type", "session ttl", "tls cert file", "tls key file"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if len(tt.errs) == 0 {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, ErrInvalid)
+
+			for _, msg := range tt.errs {
+				assert.ErrorContains(t, err, msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package creation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+const (
+	// MinAge is the youngest investigator age supported by the rules.
+	MinAge = 15
+	// MaxAge is the oldest investigator age supported by the rules.
+	MaxAge = 89
+
+	minAfterDeduction = 1
+)
+
+// AgeRule describes characteristics adjustments for an age bracket.
+type AgeRule struct {
+	// EDUChecks is a number of EDU improvement checks.
+	EDUChecks int
+	// EDULoss is deducted from EDU.
+	EDULoss int
+	// Deduct points are split by the player among DeductFrom characteristics.
+	Deduct     int
+	DeductFrom []string
+	// APPLoss is deducted from APP.
+	APPLoss int
+	// LuckTwice rolls Luck twice and keeps the higher result.
+	LuckTwice bool
+}
+
+// AgeBracket is an age range with its adjustments.
+type AgeBracket struct {
+	From int
+	To   int
+	Rule AgeRule
+}
+
+// AgeBrackets returns age adjustments table from the rulebook.
+func AgeBrackets() []AgeBracket {
+	physical := []string{"STR", "CON", "DEX"}
+
+	//nolint:mnd // age brackets are defined by the rulebook.
+	return []AgeBracket{
+		{From: MinAge, To: 19, Rule: AgeRule{EDULoss: 5, Deduct: 5, DeductFrom: []string{"STR", "SIZ"}, LuckTwice: true}},
+		{From: 20, To: 39, Rule: AgeRule{EDUChecks: 1}},
+		{From: 40, To: 49, Rule: AgeRule{EDUChecks: 2, Deduct: 5, DeductFrom: physical, APPLoss: 5}},
+		{From: 50, To: 59, Rule: AgeRule{EDUChecks: 3, Deduct: 10, DeductFrom: physical, APPLoss: 10}},
+		{From: 60, To: 69, Rule: AgeRule{EDUChecks: 4, Deduct: 20, DeductFrom: physical, APPLoss: 15}},
+		{From: 70, To: 79, Rule: AgeRule{EDUChecks: 4, Deduct: 40, DeductFrom: physical, APPLoss: 20}},
+		{From: 80, To: MaxAge, Rule: AgeRule{EDUChecks: 4, Deduct: 80, DeductFrom: physical, APPLoss: 25}},
+	}
+}
+
+// AgeRuleFor returns adjustments for the age.
+func AgeRuleFor(age int) (AgeRule, error) {
+	b, err := ageBracket(age)
+	if err != nil {
+		return AgeRule{}, err
+	}
+
+	return b.Rule, nil
+}
+
+func ageBracket(age int) (AgeBracket, error) {
+	for _, b := range AgeBrackets() {
+		if age >= b.From && age <= b.To {
+			return b, nil
+		}
+	}
+
+	return AgeBracket{}, fmt.Errorf("%w: age must be in range %d-%d", ErrInvalidValues, MinAge, MaxAge)
+}
+
+// Deductions are points the player takes from characteristics because of age.
+type Deductions map[string]int
+
+// AgeRoll is a result of EDU improvement checks and Luck reroll made for the age.
+type AgeRoll struct {
+	// Age the rolls were made for.
+	Age  int      `json:"age"`
+	EDU  int      `json:"edu"`
+	Luck int      `json:"luck"`
+	Log  []string `json:"log,omitempty"`
+}
+
+// ApplyAge applies age adjustments to characteristics.
+func ApplyAge(r *dice.Roller, c Core, age int, deductions Deductions) (Core, []string, error) {
+	rule, err := AgeRuleFor(age)
+	if err != nil {
+		return Core{}, nil, err
+	}
+
+	c, log, err := deductAge(c, rule, deductions)
+	if err != nil {
+		return Core{}, nil, err
+	}
+
+	roll, err := rollAge(r, c, age, rule)
+	if err != nil {
+		return Core{}, nil, err
+	}
+
+	c.EDU = roll.EDU
+	c.Luck = roll.Luck
+
+	return c, append(log, roll.Log...), nil
+}
+
+// deductAge applies age penalties chosen by the player or fixed by the rule.
+func deductAge(c Core, rule AgeRule, deductions Deductions) (Core, []string, error) {
+	if err := validateDeductions(rule, deductions); err != nil {
+		return Core{}, nil, err
+	}
+
+	fields := map[string]*int{"STR": &c.STR, "CON": &c.CON, "DEX": &c.DEX, "SIZ": &c.SIZ}
+
+	var log []string
+
+	for _, name := range rule.DeductFrom {
+		v := deductions[name]
+		if v == 0 {
+			continue
+		}
+
+		if *fields[name]-v < minAfterDeduction {
+			return Core{}, nil, fmt.Errorf("%w: %s would drop below %d", ErrInvalidValues, name, minAfterDeduction)
+		}
+
+		*fields[name] -= v
+
+		log = append(log, fmt.Sprintf("Age: %s -%d", name, v))
+	}
+
+	if rule.APPLoss != 0 {
+		c.APP = max(c.APP-rule.APPLoss, minAfterDeduction)
+
+		log = append(log, fmt.Sprintf("Age: APP -%d", rule.APPLoss))
+	}
+
+	if rule.EDULoss != 0 {
+		c.EDU = max(c.EDU-rule.EDULoss, minAfterDeduction)
+
+		log = append(log, fmt.Sprintf("Age: EDU -%d", rule.EDULoss))
+	}
+
+	return c, log, nil
+}
+
+// rollAge makes random age adjustments to characteristics with penalties already deducted.
+// Deductions never touch EDU and Luck, so the result is the same for any deductions of the age bracket.
+func rollAge(r *dice.Roller, c Core, age int, rule AgeRule) (AgeRoll, error) {
+	roll := AgeRoll{
+		Age:  age,
+		EDU:  c.EDU,
+		Luck: c.Luck,
+	}
+
+	for i := range rule.EDUChecks {
+		v := r.Die(100)
+
+		if v <= roll.EDU {
+			roll.Log = append(roll.Log, fmt.Sprintf("EDU improvement check %d: rolled %d, no improvement", i+1, v))
+
+			continue
+		}
+
+		gain := r.Die(10)
+		roll.EDU = min(roll.EDU+gain, maxCharacteristic)
+
+		roll.Log = append(roll.Log, fmt.Sprintf("EDU improvement check %d: rolled %d, EDU +%d", i+1, v, gain))
+	}
+
+	if rule.LuckTwice {
+		res, err := r.Roll(rollLuck)
+		if err != nil {
+			return AgeRoll{}, err
+		}
+
+		if res.Total > roll.Luck {
+			roll.Luck = res.Total
+		}
+
+		roll.Log = append(roll.Log, fmt.Sprintf("Young investigator Luck reroll: %s, Luck %d", res.String(), roll.Luck))
+	}
+
+	return roll, nil
+}
+
+func validateDeductions(rule AgeRule, deductions Deductions) error {
+	var sum int
+
+	for name, v := range deductions {
+		if v < 0 {
+			return fmt.Errorf("%w: negative deduction from %s", ErrInvalidValues, name)
+		}
+
+		if v != 0 && !slices.Contains(rule.DeductFrom, name) {
+			return fmt.Errorf("%w: %s could not be reduced at this age", ErrInvalidValues, name)
+		}
+
+		sum += v
+	}
+
+	if sum != rule.Deduct {
+		return fmt.Errorf("%w: deduct exactly %d points from %s, got %d",
+			ErrInvalidValues, rule.Deduct, strings.Join(rule.DeductFrom, "/"), sum)
+	}
+
+	return nil
+}
+
+// SetAge applies age adjustments to the confirmed characteristics and moves draft to the occupation step.
+// Age is rolled once: submitting the age step again keeps the first rolls,
+// so only age of the same bracket and other deductions could be chosen.
+func (d *Draft) SetAge(r *dice.Roller, age int, deductions Deductions) error {
+	if err := d.requireStep(StepAge); err != nil {
+		return err
+	}
+
+	b, err := ageBracket(age)
+	if err != nil {
+		return err
+	}
+
+	if d.AgeRoll != nil && (d.AgeRoll.Age < b.From || d.AgeRoll.Age > b.To) {
+		return fmt.Errorf("%w: age is already rolled for %d, choose age in the same range", ErrInvalidValues, d.AgeRoll.Age)
+	}
+
+	c, log, err := deductAge(d.BaseCore, b.Rule, deductions)
+	if err != nil {
+		return err
+	}
+
+	if d.AgeRoll == nil {
+		roll, err := rollAge(r, c, age, b.Rule)
+		if err != nil {
+			return err
+		}
+
+		d.AgeRoll = &roll
+	}
+
+	c.EDU = d.AgeRoll.EDU
+	c.Luck = d.AgeRoll.Luck
+
+	d.Core = c
+	d.Age = age
+	d.PersonalDetails.Age = fmt.Sprint(age)
+	d.AgeLog = append(log, d.AgeRoll.Log...)
+	d.Step = StepOccupation
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package creation
+
+import (
+	"strconv"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/skills"
+)
+
+const (
+	optionTrue = "true"
+	// sanityMax is a maximal Sanity of investigator without Cthulhu Mythos knowledge.
+	sanityMax = 99
+)
+
+// Finish sets backstory and builds complete investigator.
+func (d *Draft) Finish(b character.Backstory) (character.InvestigatorClass, error) {
+	if err := d.requireStep(StepBackstory); err != nil {
+		return character.InvestigatorClass{}, err
+	}
+
+	d.Backstory = b
+
+	return d.Build(), nil
+}
+
+// Build makes investigator from the draft state.
+func (d Draft) Build() character.InvestigatorClass {
+	c := d.Core
+
+	derived := character.Derive(c.Stats(), d.Age)
+	budget := d.Budget()
+
+	list := d.buildSkills()
+
+	var dodge character.SkillValues
+
+	for _, s := range list {
+		if s.Name == skills.Dodge {
+			dodge = s.SkillValues
+		}
+	}
+
+	itoa := strconv.Itoa
+
+	return character.InvestigatorClass{
+		Header: character.Header{
+			GameType: d.GameType,
+		},
+		PersonalDetails: d.PersonalDetails,
+		Characteristics: character.Characteristics{
+			Str:                         itoa(c.STR),
+			Dex:                         itoa(c.DEX),
+			Int:                         itoa(c.INT),
+			Con:                         itoa(c.CON),
+			App:                         itoa(c.APP),
+			Pow:                         itoa(c.POW),
+			Siz:                         itoa(c.SIZ),
+			Edu:                         itoa(c.EDU),
+			Move:                        itoa(derived.Move),
+			Luck:                        itoa(c.Luck),
+			LuckMax:                     itoa(c.Luck),
+			Sanity:                      itoa(derived.Sanity),
+			SanityStart:                 itoa(derived.Sanity),
+			SanityMax:                   itoa(sanityMax),
+			MagicPts:                    itoa(derived.MagicPoints),
+			MagicPtsMax:                 itoa(derived.MagicPoints),
+			HitPts:                      itoa(derived.HitPoints),
+			HitPtsMax:                   itoa(derived.HitPoints),
+			DamageBonus:                 derived.DamageBonus,
+			Build:                       itoa(derived.Build),
+			OccupationSkillPoints:       itoa(budget.Occupation),
+			PersonalInterestSkillPoints: itoa(budget.Personal),
+		},
+		Skills: character.Skills{
+			Skill: list,
+		},
+		Combat: character.Combat{
+			DamageBonus: derived.DamageBonus,
+			Build:       itoa(derived.Build),
+			Dodge:       character.Dodge{SkillValues: dodge},
+		},
+		Backstory: d.Backstory,
+	}
+}
+
+func (d Draft) buildSkills() []character.Skill {
+	bases := d.Skills()
+	list := make([]character.Skill, 0, len(bases))
+
+	for _, b := range bases {
+		a := d.Allocations[b.FullName()]
+		v := character.NewValue(b.Base + a.Occupation + a.Personal)
+
+		s := character.Skill{
+			Name: b.Name,
+			SkillValues: character.SkillValues{
+				Value: strconv.Itoa(v.Full),
+				Half:  strconv.Itoa(v.Half),
+				Fifth: strconv.Itoa(v.Fifth),
+			},
+		}
+
+		switch {
+		case b.Specialisation != "":
+			sp := b.Specialisation
+			s.Subskill = &sp
+		case b.Family:
+			none := skills.SubskillNone
+			s.Subskill = &none
+		}
+
+		if d.IsOccupationSkill(b.FullName()) {
+			occ := optionTrue
+			s.Occupation = &occ
+		}
+
+		list = append(list, s)
+	}
+
+	return list
+}
@@ -0,0 +1,197 @@
+package creation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+// ErrInvalidValues is returned when user provided values violate the rules.
+var ErrInvalidValues = errors.New("invalid values")
+
+// Core holds numeric core characteristics.
+type Core struct {
+	STR  int `json:"str"`
+	CON  int `json:"con"`
+	SIZ  int `json:"siz"`
+	DEX  int `json:"dex"`
+	APP  int `json:"app"`
+	INT  int `json:"int"`
+	POW  int `json:"pow"`
+	EDU  int `json:"edu"`
+	Luck int `json:"luck"`
+}
+
+// Stats returns typed characteristics.
+func (c Core) Stats() character.Stats {
+	return character.Stats{
+		STR:  character.NewValue(c.STR),
+		CON:  character.NewValue(c.CON),
+		SIZ:  character.NewValue(c.SIZ),
+		DEX:  character.NewValue(c.DEX),
+		APP:  character.NewValue(c.APP),
+		INT:  character.NewValue(c.INT),
+		POW:  character.NewValue(c.POW),
+		EDU:  character.NewValue(c.EDU),
+		Luck: character.NewValue(c.Luck),
+	}
+}
+
+const (
+	rollStandard = "3D6x5"
+	rollHigher   = "(2D6+6)x5"
+	rollLuck     = rollStandard
+
+	// PointBuyTotal is a number of points distributed among 8 characteristics (Luck is rolled).
+	PointBuyTotal = 460
+	// PointBuyMin is a minimal value of a characteristic in point-buy.
+	PointBuyMin = 15
+	// PointBuyMax is a maximal value of a characteristic in point-buy.
+	PointBuyMax = 90
+
+	maxCharacteristic = 99
+)
+
+// RollCharacteristics rolls characteristics: 3D6×5 for STR, CON, DEX, APP, POW and Luck, (2D6+6)×5 for SIZ, INT and EDU.
+func RollCharacteristics(r *dice.Roller) (Core, []string, error) {
+	var (
+		c   Core
+		log []string
+	)
+
+	targets := []struct {
+		name string
+		expr string
+		dst  *int
+	}{
+		{name: "STR", expr: rollStandard, dst: &c.STR},
+		{name: "CON", expr: rollStandard, dst: &c.CON},
+		{name: "SIZ", expr: rollHigher, dst: &c.SIZ},
+		{name: "DEX", expr: rollStandard, dst: &c.DEX},
+		{name: "APP", expr: rollStandard, dst: &c.APP},
+		{name: "INT", expr: rollHigher, dst: &c.INT},
+		{name: "POW", expr: rollStandard, dst: &c.POW},
+		{name: "EDU", expr: rollHigher, dst: &c.EDU},
+		{name: "Luck", expr: rollLuck, dst: &c.Luck},
+	}
+
+	for _, t := range targets {
+		res, err := r.Roll(t.expr)
+		if err != nil {
+			return Core{}, nil, err
+		}
+
+		*t.dst = res.Total
+
+		log = append(log, fmt.Sprintf("%s: %s", t.name, res.String()))
+	}
+
+	return c, log, nil
+}
+
+// ValidatePointBuy checks that 8 characteristics (without Luck) spend exactly PointBuyTotal points
+// and each is in [PointBuyMin, PointBuyMax] range.
+func ValidatePointBuy(c Core) error {
+	var errs error
+
+	values := map[string]int{
+		"STR": c.STR, "CON": c.CON, "SIZ": c.SIZ, "DEX": c.DEX,
+		"APP": c.APP, "INT": c.INT, "POW": c.POW, "EDU": c.EDU,
+	}
+
+	var sum int
+
+	for _, name := range []string{"STR", "CON", "SIZ", "DEX", "APP", "INT", "POW", "EDU"} {
+		v := values[name]
+
+		sum += v
+
+		if v < PointBuyMin || v > PointBuyMax {
+			errs = errors.Join(errs, fmt.Errorf("%w: %s must be in range %d-%d", ErrInvalidValues, name, PointBuyMin, PointBuyMax))
+		}
+	}
+
+	if sum != PointBuyTotal {
+		errs = errors.Join(errs, fmt.Errorf("%w: total must be %d, got %d", ErrInvalidValues, PointBuyTotal, sum))
+	}
+
+	return errs
+}
+
+// RollCharacteristics generates characteristics by rolling dice. Characteristics are generated only once.
+func (d *Draft) RollCharacteristics(r *dice.Roller) error {
+	if err := d.requireStep(StepCharacteristics); err != nil {
+		return err
+	}
+
+	if d.Rolled {
+		return fmt.Errorf("%w: characteristics are already generated", ErrInvalidValues)
+	}
+
+	c, log, err := RollCharacteristics(r)
+	if err != nil {
+		return err
+	}
+
+	d.Method = MethodRoll
+	d.Core = c
+	d.Rolled = true
+	d.Log = log
+
+	return nil
+}
+
+// PointBuy sets characteristics distributed by the player, Luck is rolled.
+// Points could be distributed again, but rolled characteristics and Luck are kept.
+func (d *Draft) PointBuy(r *dice.Roller, c Core) error {
+	if err := d.requireStep(StepCharacteristics); err != nil {
+		return err
+	}
+
+	if d.Rolled && d.Method != MethodPointBuy {
+		return fmt.Errorf("%w: characteristics are already rolled", ErrInvalidValues)
+	}
+
+	if err := ValidatePointBuy(c); err != nil {
+		return err
+	}
+
+	if d.Rolled {
+		c.Luck = d.Core.Luck
+		d.Core = c
+
+		return nil
+	}
+
+	luck, err := r.Roll(rollLuck)
+	if err != nil {
+		return err
+	}
+
+	c.Luck = luck.Total
+
+	d.Method = MethodPointBuy
+	d.Core = c
+	d.Rolled = true
+	d.Log = []string{"Point-buy characteristics", fmt.Sprintf("Luck: %s", luck.String())}
+
+	return nil
+}
+
+// ConfirmCharacteristics moves draft to the age step.
+func (d *Draft) ConfirmCharacteristics() error {
+	if err := d.requireStep(StepCharacteristics); err != nil {
+		return err
+	}
+
+	if !d.Rolled {
+		return fmt.Errorf("%w: characteristics are not generated", ErrInvalidValues)
+	}
+
+	d.BaseCore = d.Core
+	d.Step = StepAge
+
+	return nil
+}
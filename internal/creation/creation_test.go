@@ -0,0 +1,294 @@
+package creation
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice/dicetest"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/occupation"
+)
+
+func TestRollCharacteristics(t *testing.T) {
+	c, log, err := RollCharacteristics(dicetest.NewFixedRoller(6))
+	require.NoError(t, err)
+
+	assert.Equal(t, Core{STR: 90, CON: 90, SIZ: 90, DEX: 90, APP: 90, INT: 90, POW: 90, EDU: 90, Luck: 90}, c)
+	assert.Len(t, log, 9)
+
+	c, _, err = RollCharacteristics(dicetest.NewFixedRoller(1))
+	require.NoError(t, err)
+
+	assert.Equal(t, Core{STR: 15, CON: 15, SIZ: 40, DEX: 15, APP: 15, INT: 40, POW: 15, EDU: 40, Luck: 15}, c)
+}
+
+func TestValidatePointBuy(t *testing.T) {
+	valid := Core{STR: 50, CON: 50, SIZ: 60, DEX: 60, APP: 50, INT: 70, POW: 60, EDU: 60}
+
+	require.NoError(t, ValidatePointBuy(valid))
+
+	tooMuch := valid
+	tooMuch.STR = 55
+	require.ErrorIs(t, ValidatePointBuy(tooMuch), ErrInvalidValues)
+
+	outOfRange := valid
+	outOfRange.STR = 10
+	outOfRange.CON = 90
+	require.ErrorIs(t, ValidatePointBuy(outOfRange), ErrInvalidValues)
+}
+
+func TestApplyAge(t *testing.T) {
+	base := Core{STR: 50, CON: 50, SIZ: 60, DEX: 60, APP: 50, INT: 70, POW: 60, EDU: 60, Luck: 40}
+
+	tests := []struct {
+		name       string
+		age        int
+		face       int
+		deductions Deductions
+		want       Core
+		wantErr    bool
+	}{
+		{
+			name: "twenties, EDU improved",
+			age:  25,
+			face: 100,
+			want: Core{STR: 50, CON: 50, SIZ: 60, DEX: 60, APP: 50, INT: 70, POW: 60, EDU: 70, Luck: 40},
+		},
+		{
+			name: "twenties, EDU not improved",
+			age:  25,
+			face: 1,
+			want: base,
+		},
+		{
+			name:       "teenager",
+			age:        17,
+			face:       6,
+			deductions: Deductions{"STR": 2, "SIZ": 3},
+			want:       Core{STR: 48, CON: 50, SIZ: 57, DEX: 60, APP: 50, INT: 70, POW: 60, EDU: 55, Luck: 90},
+		},
+		{
+			name:       "fifties",
+			age:        55,
+			face:       1,
+			deductions: Deductions{"STR": 5, "DEX": 5},
+			want:       Core{STR: 45, CON: 50, SIZ: 60, DEX: 55, APP: 40, INT: 70, POW: 60, EDU: 60, Luck: 40},
+		},
+		{
+			name:       "wrong deduction total",
+			age:        45,
+			face:       1,
+			deductions: Deductions{"STR": 1},
+			wantErr:    true,
+		},
+		{
+			name:       "wrong deduction characteristic",
+			age:        45,
+			face:       1,
+			deductions: Deductions{"SIZ": 5},
+			wantErr:    true,
+		},
+		{
+			name:    "too old",
+			age:     95,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := ApplyAge(dicetest.NewFixedRoller(max(tt.face, 1)), base, tt.age, tt.deductions)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidValues)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSkillPoints(t *testing.T) {
+	c := Core{STR: 50, DEX: 60, APP: 40, EDU: 70}
+
+	tests := []struct {
+		formula string
+		want    int
+		wantErr bool
+	}{
+		{formula: "EDU×4", want: 280},
+		{formula: "EDU x 2 + DEX x 2", want: 260},
+		{formula: "EDU*2+(STR|DEX|APP)*2", want: 260},
+		{formula: "EDU", wantErr: true},
+		{formula: "LUCK×4", wantErr: true},
+		{formula: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.formula, func(t *testing.T) {
+			got, err := SkillPoints(tt.formula, c)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidValues)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDraft(t *testing.T) {
+	r := dicetest.NewFixedRoller(1)
+
+	d := NewDraft("id", "Harvey Walters", "", time.Now())
+
+	require.ErrorIs(t, d.ConfirmCharacteristics(), ErrInvalidValues)
+	require.ErrorIs(t, d.SetAge(r, 30, nil), ErrWrongStep)
+
+	require.NoError(t, d.PointBuy(r, Core{STR: 50, CON: 50, SIZ: 60, DEX: 60, APP: 50, INT: 70, POW: 60, EDU: 60}))
+	require.NoError(t, d.ConfirmCharacteristics())
+	require.NoError(t, d.SetAge(r, 30, nil))
+
+	require.ErrorIs(t, d.SetOccupation("Antiquarian", "EDU×4", []string{CthulhuMythos}), ErrInvalidValues)
+	require.NoError(t, d.SetOccupation("Antiquarian", "EDU×4", []string{"Appraise", "History", "Library Use"}))
+
+	budget := d.Budget()
+	assert.Equal(t, 240, budget.Occupation)
+	assert.Equal(t, 140, budget.Personal)
+
+	require.ErrorIs(t, d.SetAllocations(map[string]Allocation{"Dodge": {Occupation: 10}}), ErrInvalidValues)
+	require.ErrorIs(t, d.SetAllocations(map[string]Allocation{"History": {Occupation: 241}}), ErrInvalidValues)
+	require.ErrorIs(t, d.SetAllocations(map[string]Allocation{CthulhuMythos: {Personal: 5}}), ErrInvalidValues)
+	require.ErrorIs(t, d.SetAllocations(map[string]Allocation{"Library Use": {Occupation: 80}}), ErrInvalidValues)
+
+	require.NoError(t, d.SetAllocations(map[string]Allocation{
+		"History":          {Occupation: 60},
+		"Library Use":      {Occupation: 50},
+		CreditRating:       {Occupation: 30},
+		"Appraise":         {Occupation: 40, Personal: 10},
+		"Spot Hidden":      {Personal: 30},
+		"Fighting (Brawl)": {Personal: 10},
+	}))
+
+	inv, err := d.Finish(character.Backstory{Description: "Quiet scholar"})
+	require.NoError(t, err)
+
+	assert.Equal(t, character.GameTypeClassic, inv.Header.GameType)
+	assert.Equal(t, "Harvey Walters", inv.PersonalDetails.Name)
+	assert.Equal(t, "Antiquarian", inv.PersonalDetails.Occupation)
+	assert.Equal(t, "30", inv.PersonalDetails.Age)
+	assert.Equal(t, "11", inv.Characteristics.HitPtsMax)
+	assert.Equal(t, "240", inv.Characteristics.OccupationSkillPoints)
+	assert.Equal(t, "Quiet scholar", inv.Backstory.Description)
+
+	mismatches, err := inv.DerivedMismatches()
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	target, err := inv.CheckTarget("Appraise")
+	require.NoError(t, err)
+	assert.Equal(t, 55, target)
+
+	target, err = inv.CheckTarget("Brawl")
+	require.NoError(t, err)
+	assert.Equal(t, 35, target)
+
+	target, err = inv.CheckTarget("Dodge")
+	require.NoError(t, err)
+	assert.Equal(t, 30, target)
+	assert.Equal(t, strconv.Itoa(target), inv.Combat.Dodge.Value)
+
+	history, ok := inv.FindSkill("History")
+	require.True(t, ok)
+	assert.True(t, history.IsOccupation())
+}
+
+func TestDraft_BackToAge(t *testing.T) {
+	d := NewDraft("id", "Harvey Walters", "", time.Now())
+
+	require.NoError(t, d.PointBuy(dicetest.NewFixedRoller(6), Core{STR: 55, CON: 50, SIZ: 60, DEX: 60, APP: 55, INT: 60, POW: 60, EDU: 60}))
+	require.NoError(t, d.ConfirmCharacteristics())
+
+	d.Back()
+	assert.Equal(t, StepAge, d.Step)
+	require.ErrorIs(t, d.RollCharacteristics(dicetest.NewFixedRoller(1)), ErrWrongStep)
+
+	// Both EDU improvement checks succeed only on the first roll.
+	r := dicetest.NewRoller(100, 7, 90, 3, 1)
+
+	want := Core{STR: 50, CON: 50, SIZ: 60, DEX: 60, APP: 50, INT: 60, POW: 60, EDU: 70, Luck: 90}
+
+	for range 2 {
+		require.NoError(t, d.SetAge(r, 45, Deductions{"STR": 5}))
+		assert.Equal(t, want, d.Core)
+		assert.Equal(t, "45", d.PersonalDetails.Age)
+		assert.Len(t, d.AgeLog, 4)
+
+		d.Back()
+		assert.Equal(t, StepAge, d.Step)
+	}
+
+	require.NoError(t, d.SetAge(r, 49, Deductions{"DEX": 5}))
+	assert.Equal(t, Core{STR: 55, CON: 50, SIZ: 60, DEX: 55, APP: 50, INT: 60, POW: 60, EDU: 70, Luck: 90}, d.Core)
+
+	d.Back()
+	require.ErrorIs(t, d.SetAge(r, 30, nil), ErrInvalidValues)
+}
+
+func TestDraft_CharacteristicsOnce(t *testing.T) {
+	pointBuy := Core{STR: 55, CON: 50, SIZ: 60, DEX: 60, APP: 55, INT: 60, POW: 60, EDU: 60}
+
+	rolled := NewDraft("id", "Harvey Walters", "", time.Now())
+
+	require.NoError(t, rolled.RollCharacteristics(dicetest.NewFixedRoller(6)))
+	require.ErrorIs(t, rolled.RollCharacteristics(dicetest.NewFixedRoller(1)), ErrInvalidValues)
+	require.ErrorIs(t, rolled.PointBuy(dicetest.NewFixedRoller(1), pointBuy), ErrInvalidValues)
+	assert.Equal(t, 90, rolled.Core.STR)
+
+	bought := NewDraft("id", "Harvey Walters", "", time.Now())
+
+	require.NoError(t, bought.PointBuy(dicetest.NewFixedRoller(6), pointBuy))
+	require.ErrorIs(t, bought.RollCharacteristics(dicetest.NewFixedRoller(1)), ErrInvalidValues)
+
+	pointBuy.STR, pointBuy.SIZ = pointBuy.SIZ, pointBuy.STR
+	require.NoError(t, bought.PointBuy(dicetest.NewFixedRoller(1), pointBuy))
+
+	pointBuy.Luck = 90
+	assert.Equal(t, pointBuy, bought.Core)
+}
+
+func TestDraft_ChooseOccupation(t *testing.T) {
+	r := dicetest.NewFixedRoller(1)
+
+	d := NewDraft("id", "Harvey Walters", character.GameTypeClassic, time.Now())
+
+	require.NoError(t, d.PointBuy(r, Core{STR: 50, CON: 50, SIZ: 60, DEX: 60, APP: 50, INT: 70, POW: 60, EDU: 60}))
+	require.NoError(t, d.ConfirmCharacteristics())
+	require.NoError(t, d.SetAge(r, 30, nil))
+
+	hacker, err := occupation.Find("Hacker")
+	require.NoError(t, err)
+	require.ErrorIs(t, d.ChooseOccupation(hacker, nil), ErrInvalidValues)
+
+	antiquarian, err := occupation.Find("Antiquarian")
+	require.NoError(t, err)
+	require.ErrorIs(t, d.ChooseOccupation(antiquarian, []string{"Dodge", "Swim"}), ErrInvalidValues)
+	require.NoError(t, d.ChooseOccupation(antiquarian, []string{"Appraise", "History", "Charm", "Swim"}))
+
+	assert.Equal(t, "Antiquarian", d.PersonalDetails.Occupation)
+	assert.Equal(t, 240, d.Budget().Occupation)
+
+	require.ErrorIs(t, d.SetAllocations(map[string]Allocation{"History": {Occupation: 60}}), ErrInvalidValues)
+	require.NoError(t, d.SetAllocations(map[string]Allocation{
+		"History":    {Occupation: 60},
+		CreditRating: {Occupation: 30, Personal: 10},
+	}))
+}
@@ -0,0 +1,125 @@
+// Package creation implements step by step investigator creation following Call of Cthulhu 7e rulebook.
+package creation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/occupation"
+)
+
+// Step of the creation wizard.
+type Step int
+
+const (
+	StepCharacteristics Step = iota
+	StepAge
+	StepOccupation
+	StepSkills
+	StepBackstory
+)
+
+func (s Step) String() string {
+	switch s {
+	case StepCharacteristics:
+		return "characteristics"
+	case StepAge:
+		return "age"
+	case StepOccupation:
+		return "occupation"
+	case StepSkills:
+		return "skills"
+	case StepBackstory:
+		return "backstory"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrWrongStep is returned when draft operation is called out of order.
+var ErrWrongStep = errors.New("wrong creation step")
+
+// Method of characteristics generation.
+type Method string
+
+const (
+	MethodRoll     Method = "roll"
+	MethodPointBuy Method = "pointbuy"
+)
+
+// Allocation is skill points spent on a skill.
+type Allocation struct {
+	Occupation int `json:"occupation"`
+	Personal   int `json:"personal"`
+}
+
+// Draft is an investigator under construction.
+type Draft struct {
+	ID       string `json:"id"`
+	Step     Step   `json:"step"`
+	GameType string `json:"game_type"`
+
+	PersonalDetails character.PersonalDetails `json:"personal_details"`
+
+	Method Method `json:"method,omitempty"`
+	// Rolled is set after characteristics are generated.
+	Rolled bool `json:"rolled"`
+	Core   Core `json:"core"`
+	// BaseCore keeps confirmed characteristics before age adjustments,
+	// so the age step could be submitted again after going back.
+	BaseCore Core `json:"base_core"`
+
+	Age int `json:"age"`
+	// Log describes random rolls made for characteristics.
+	Log []string `json:"log,omitempty"`
+	// AgeLog describes age adjustments and rolls.
+	AgeLog []string `json:"age_log,omitempty"`
+	// AgeRoll keeps the first age rolls, so going back to the age step could not roll them again.
+	AgeRoll *AgeRoll `json:"age_roll,omitempty"`
+
+	Occupation         string   `json:"occupation"`
+	SkillPointsFormula string   `json:"skill_points_formula"`
+	OccupationSkills   []string `json:"occupation_skills"`
+	// CreditRating limits Credit Rating when occupation is taken from the catalogue.
+	CreditRating *occupation.Range `json:"credit_rating,omitempty"`
+
+	Allocations map[string]Allocation `json:"allocations,omitempty"`
+
+	Backstory character.Backstory `json:"backstory"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewDraft starts a new investigator.
+func NewDraft(id, name, gameType string, now time.Time) Draft {
+	if gameType == "" {
+		gameType = character.GameTypeClassic
+	}
+
+	return Draft{
+		ID:       id,
+		Step:     StepCharacteristics,
+		GameType: gameType,
+		PersonalDetails: character.PersonalDetails{
+			Name: name,
+		},
+		CreatedAt: now,
+	}
+}
+
+func (d *Draft) requireStep(s Step) error {
+	if d.Step != s {
+		return ErrWrongStep
+	}
+
+	return nil
+}
+
+// Back returns draft to the previous step. Results of the current step are kept.
+// Confirmed characteristics could not be generated again, so the age step is the earliest one to return to.
+func (d *Draft) Back() {
+	if d.Step > StepAge {
+		d.Step--
+	}
+}
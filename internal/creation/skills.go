@@ -0,0 +1,231 @@
+package creation
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/occupation"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/skills"
+)
+
+const (
+	// CreditRating skill is always an occupation skill.
+	CreditRating = skills.CreditRating
+	// CthulhuMythos skill could not be raised during creation.
+	CthulhuMythos = skills.CthulhuMythos
+
+	// MaxOccupationSkills is a number of occupation skills besides Credit Rating.
+	MaxOccupationSkills = 8
+
+	maxSkill = 99
+)
+
+// Skills returns skills of the investigator with base values for the draft era and characteristics.
+func (d Draft) Skills() []skills.Slot {
+	return skills.Starting(character.EraFor(d.GameType), d.Core.Stats())
+}
+
+// SkillPoints calculates skill points by the occupation formula, e.g. "EDU×2+(DEX|STR)×2".
+func SkillPoints(formula string, c Core) (int, error) {
+	points, err := occupation.EvalSkillPoints(formula, map[string]int{
+		"STR": c.STR, "CON": c.CON, "SIZ": c.SIZ, "DEX": c.DEX,
+		"APP": c.APP, "INT": c.INT, "POW": c.POW, "EDU": c.EDU,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidValues, err)
+	}
+
+	return points, nil
+}
+
+// PersonalInterestPoints returns personal interest skill points: INT×2.
+func PersonalInterestPoints(c Core) int {
+	return c.INT * 2 //nolint:mnd // rulebook formula.
+}
+
+// SetOccupation sets occupation and its skills and moves draft to the skills step.
+// Credit Rating is always added to occupation skills.
+func (d *Draft) SetOccupation(name, formula string, chosenSkills []string) error {
+	if err := d.requireStep(StepOccupation); err != nil {
+		return err
+	}
+
+	var errs error
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		errs = errors.Join(errs, fmt.Errorf("%w: occupation name is required", ErrInvalidValues))
+	}
+
+	if _, err := SkillPoints(formula, d.Core); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	known := make(map[string]bool)
+	for _, s := range d.Skills() {
+		known[s.FullName()] = true
+	}
+
+	chosen := []string{CreditRating}
+
+	for _, s := range chosenSkills {
+		switch {
+		case !known[s]:
+			errs = errors.Join(errs, fmt.Errorf("%w: unknown skill %q", ErrInvalidValues, s))
+		case s == CthulhuMythos:
+			errs = errors.Join(errs, fmt.Errorf("%w: %s could not be an occupation skill", ErrInvalidValues, s))
+		case !slices.Contains(chosen, s):
+			chosen = append(chosen, s)
+		}
+	}
+
+	if len(chosen)-1 > MaxOccupationSkills {
+		errs = errors.Join(errs, fmt.Errorf("%w: choose at most %d occupation skills", ErrInvalidValues, MaxOccupationSkills))
+	}
+
+	if errs != nil {
+		return errs
+	}
+
+	d.PersonalDetails.Occupation = name
+	d.Occupation = name
+	d.SkillPointsFormula = formula
+	d.OccupationSkills = chosen
+	d.CreditRating = nil
+	d.Step = StepSkills
+
+	return nil
+}
+
+// ChooseOccupation sets occupation from the catalogue and moves draft to the skills step.
+// Skills must be allowed by the occupation and Credit Rating is limited by its range.
+func (d *Draft) ChooseOccupation(o occupation.Occupation, chosenSkills []string) error {
+	if err := d.requireStep(StepOccupation); err != nil {
+		return err
+	}
+
+	if !o.AvailableIn(character.EraFor(d.GameType)) {
+		return fmt.Errorf("%w: %s is not available in %s", ErrInvalidValues, o.Name, d.GameType)
+	}
+
+	if err := o.ValidateSkills(chosenSkills); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidValues, err)
+	}
+
+	if err := d.SetOccupation(o.Name, o.SkillPoints, chosenSkills); err != nil {
+		return err
+	}
+
+	cr := o.CreditRating
+	d.CreditRating = &cr
+
+	return nil
+}
+
+// Budget is skill points available and spent.
+type Budget struct {
+	Occupation      int
+	OccupationSpent int
+	Personal        int
+	PersonalSpent   int
+}
+
+// Budget returns skill points budget of the draft.
+func (d Draft) Budget() Budget {
+	occ, err := SkillPoints(d.SkillPointsFormula, d.Core)
+	if err != nil {
+		occ = 0
+	}
+
+	b := Budget{
+		Occupation: occ,
+		Personal:   PersonalInterestPoints(d.Core),
+	}
+
+	for _, a := range d.Allocations {
+		b.OccupationSpent += a.Occupation
+		b.PersonalSpent += a.Personal
+	}
+
+	return b
+}
+
+// IsOccupationSkill reports whether skill is chosen as occupation skill.
+func (d Draft) IsOccupationSkill(name string) bool {
+	return slices.Contains(d.OccupationSkills, name)
+}
+
+// ValidateAllocations checks that skill points are spent according to the rules.
+func (d Draft) ValidateAllocations(allocations map[string]Allocation) error {
+	var errs error
+
+	bases := make(map[string]int)
+	for _, s := range d.Skills() {
+		bases[s.FullName()] = s.Base
+	}
+
+	probe := d
+	probe.Allocations = allocations
+
+	for name, a := range allocations {
+		base, ok := bases[name]
+
+		switch {
+		case !ok:
+			errs = errors.Join(errs, fmt.Errorf("%w: unknown skill %q", ErrInvalidValues, name))
+
+			continue
+		case a.Occupation < 0 || a.Personal < 0:
+			errs = errors.Join(errs, fmt.Errorf("%w: %s: negative points", ErrInvalidValues, name))
+		case name == CthulhuMythos && a.Occupation+a.Personal != 0:
+			errs = errors.Join(errs, fmt.Errorf("%w: %s could not be raised during creation", ErrInvalidValues, name))
+		case a.Occupation != 0 && !d.IsOccupationSkill(name):
+			errs = errors.Join(errs, fmt.Errorf("%w: %s is not an occupation skill", ErrInvalidValues, name))
+		}
+
+		if base+a.Occupation+a.Personal > maxSkill {
+			errs = errors.Join(errs, fmt.Errorf("%w: %s exceeds %d%%", ErrInvalidValues, name, maxSkill))
+		}
+	}
+
+	if d.CreditRating != nil {
+		a := allocations[CreditRating]
+		if v := a.Occupation + a.Personal; !d.CreditRating.Contains(v) {
+			errs = errors.Join(errs, fmt.Errorf("%w: %s must be in range %s for %s, got %d",
+				ErrInvalidValues, CreditRating, d.CreditRating, d.Occupation, v))
+		}
+	}
+
+	b := probe.Budget()
+
+	if b.OccupationSpent > b.Occupation {
+		errs = errors.Join(errs, fmt.Errorf("%w: %d occupation points spent, %d available",
+			ErrInvalidValues, b.OccupationSpent, b.Occupation))
+	}
+
+	if b.PersonalSpent > b.Personal {
+		errs = errors.Join(errs, fmt.Errorf("%w: %d personal interest points spent, %d available",
+			ErrInvalidValues, b.PersonalSpent, b.Personal))
+	}
+
+	return errs
+}
+
+// SetAllocations stores skill points allocation and moves draft to the backstory step.
+func (d *Draft) SetAllocations(allocations map[string]Allocation) error {
+	if err := d.requireStep(StepSkills); err != nil {
+		return err
+	}
+
+	if err := d.ValidateAllocations(allocations); err != nil {
+		return err
+	}
+
+	d.Allocations = allocations
+	d.Step = StepBackstory
+
+	return nil
+}
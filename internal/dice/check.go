@@ -0,0 +1,138 @@
+package dice
+
+import (
+	"errors"
+)
+
+// ErrTooManyDice is returned when check is requested with negative or unreasonably many bonus or penalty dice.
+var ErrTooManyDice = errors.New("too many bonus or penalty dice")
+
+// SuccessLevel of a percentile check.
+type SuccessLevel int
+
+const (
+	Fumble SuccessLevel = iota
+	Failure
+	RegularSuccess
+	HardSuccess
+	ExtremeSuccess
+	CriticalSuccess
+)
+
+func (l SuccessLevel) String() string {
+	switch l {
+	case Fumble:
+		return "Fumble"
+	case Failure:
+		return "Failure"
+	case RegularSuccess:
+		return "Regular"
+	case HardSuccess:
+		return "Hard"
+	case ExtremeSuccess:
+		return "Extreme"
+	case CriticalSuccess:
+		return "Critical"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsSuccess reports whether level is any kind of success.
+func (l SuccessLevel) IsSuccess() bool {
+	return l >= RegularSuccess
+}
+
+// CheckResult is a result of percentile check against a target value.
+type CheckResult struct {
+	Target  int
+	Bonus   int
+	Penalty int
+	// Tens holds all rolled tens dice (0, 10, ... 90), the first one is the regular die.
+	Tens  []int
+	Units int
+	Roll  int
+	Level SuccessLevel
+}
+
+// Check rolls d100 against target value with bonus and penalty dice.
+// Bonus and penalty dice cancel each other, any number of extra dice may remain after that.
+// Only the number of rolled dice is limited, the same way as in dice expressions.
+func (r *Roller) Check(target, bonus, penalty int) (CheckResult, error) {
+	if bonus < 0 || penalty < 0 {
+		return CheckResult{}, ErrTooManyDice
+	}
+
+	extra := bonus - penalty
+	if abs(extra) >= maxDice {
+		return CheckResult{}, ErrTooManyDice
+	}
+
+	units := r.Die(10) - 1
+
+	tens := make([]int, 0, 1+abs(extra))
+	for range 1 + abs(extra) {
+		tens = append(tens, (r.Die(10)-1)*10)
+	}
+
+	roll := percentile(tens[0], units)
+
+	for _, t := range tens[1:] {
+		v := percentile(t, units)
+
+		if (extra > 0 && v < roll) || (extra < 0 && v > roll) {
+			roll = v
+		}
+	}
+
+	return CheckResult{
+		Target:  target,
+		Bonus:   bonus,
+		Penalty: penalty,
+		Tens:    tens,
+		Units:   units,
+		Roll:    roll,
+		Level:   Resolve(roll, target),
+	}, nil
+}
+
+// Resolve returns success level of d100 roll against target value per 7e rules.
+func Resolve(roll, target int) SuccessLevel {
+	const (
+		fumbleThreshold = 50
+		lowFumble       = 96
+		highFumble      = 100
+	)
+
+	switch {
+	case roll == 1:
+		return CriticalSuccess
+	case roll >= highFumble, target < fumbleThreshold && roll >= lowFumble:
+		return Fumble
+	case roll <= target/5:
+		return ExtremeSuccess
+	case roll <= target/2:
+		return HardSuccess
+	case roll <= target:
+		return RegularSuccess
+	default:
+		return Failure
+	}
+}
+
+func percentile(tens, units int) int {
+	v := tens + units
+	if v == 0 {
+		return 100
+	}
+
+	return v
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
@@ -0,0 +1,133 @@
+package dice_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice/dicetest"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name   string
+		roll   int
+		target int
+		want   dice.SuccessLevel
+	}{
+		{name: "critical", roll: 1, target: 10, want: dice.CriticalSuccess},
+		{name: "extreme", roll: 12, target: 60, want: dice.ExtremeSuccess},
+		{name: "hard", roll: 30, target: 60, want: dice.HardSuccess},
+		{name: "regular", roll: 60, target: 60, want: dice.RegularSuccess},
+		{name: "failure", roll: 61, target: 60, want: dice.Failure},
+		{name: "fumble 96 below 50", roll: 96, target: 49, want: dice.Fumble},
+		{name: "no fumble 96 at 50", roll: 96, target: 50, want: dice.Failure},
+		{name: "fumble 100", roll: 100, target: 90, want: dice.Fumble},
+		{name: "zero target", roll: 2, target: 0, want: dice.Failure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, dice.Resolve(tt.roll, tt.target))
+		})
+	}
+}
+
+func TestRoller_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		faces   []int // units die first, then tens dice
+		bonus   int
+		penalty int
+		want    dice.CheckResult
+		wantErr error
+	}{
+		{
+			name:  "plain",
+			faces: []int{5, 3},
+			want: dice.CheckResult{
+				Target: 50, Tens: []int{20}, Units: 4, Roll: 24, Level: dice.HardSuccess,
+			},
+		},
+		{
+			name:  "bonus takes lowest",
+			faces: []int{5, 8, 1},
+			bonus: 1,
+			want: dice.CheckResult{
+				Target: 50, Bonus: 1, Tens: []int{70, 0}, Units: 4, Roll: 4, Level: dice.ExtremeSuccess,
+			},
+		},
+		{
+			name:    "penalty takes highest",
+			faces:   []int{5, 2, 9, 4},
+			penalty: 2,
+			want: dice.CheckResult{
+				Target: 50, Penalty: 2, Tens: []int{10, 80, 30}, Units: 4, Roll: 84, Level: dice.Failure,
+			},
+		},
+		{
+			name:  "00 and 0 is 100",
+			faces: []int{1, 1},
+			want: dice.CheckResult{
+				Target: 50, Tens: []int{0}, Units: 0, Roll: 100, Level: dice.Fumble,
+			},
+		},
+		{
+			name:    "bonus and penalty cancel",
+			faces:   []int{2, 5},
+			bonus:   1,
+			penalty: 1,
+			want: dice.CheckResult{
+				Target: 50, Bonus: 1, Penalty: 1, Tens: []int{40}, Units: 1, Roll: 41, Level: dice.RegularSuccess,
+			},
+		},
+		{
+			name:  "three bonus dice",
+			faces: []int{5, 8, 6, 2, 9},
+			bonus: 3,
+			want: dice.CheckResult{
+				Target: 50, Bonus: 3, Tens: []int{70, 50, 10, 80}, Units: 4, Roll: 14, Level: dice.HardSuccess,
+			},
+		},
+		{
+			name:    "penalty cancelled down to three",
+			faces:   []int{5, 2, 9, 4, 1},
+			bonus:   1,
+			penalty: 4,
+			want: dice.CheckResult{
+				Target: 50, Bonus: 1, Penalty: 4, Tens: []int{10, 80, 30, 0}, Units: 4, Roll: 84, Level: dice.Failure,
+			},
+		},
+		{
+			name:    "negative",
+			bonus:   -1,
+			wantErr: dice.ErrTooManyDice,
+		},
+		{
+			name:    "too many",
+			bonus:   100,
+			wantErr: dice.ErrTooManyDice,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faces := tt.faces
+			if len(faces) == 0 {
+				faces = []int{1}
+			}
+
+			got, err := dicetest.NewRoller(faces...).Check(50, tt.bonus, tt.penalty)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Package dicetest provides dice rollers with predefined results for tests.
+package dicetest
+
+import (
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+// FacesSource returns faces in order and starts over when they are over, face is a 1-based die result.
+// Percentile check takes units die first and tens die next: faces 1, 4 roll 30.
+type FacesSource struct {
+	faces []int
+	pos   int
+}
+
+// IntN returns the next face, faces greater than n wrap around.
+func (s *FacesSource) IntN(n int) int {
+	f := s.faces[s.pos%len(s.faces)]
+	s.pos++
+
+	return (f - 1) % n
+}
+
+// NewRoller returns roller rolling faces in order.
+func NewRoller(faces ...int) *dice.Roller {
+	return dice.NewRoller(&FacesSource{faces: faces})
+}
+
+// FixedSource always rolls the same face capped by the die size, e.g. FixedSource(100) rolls maximum on any die.
+type FixedSource int
+
+// IntN returns the face capped by n.
+func (f FixedSource) IntN(n int) int {
+	return min(int(f), n) - 1
+}
+
+// NewFixedRoller returns roller always rolling face capped by the die size.
+func NewFixedRoller(face int) *dice.Roller {
+	return dice.NewRoller(FixedSource(face))
+}
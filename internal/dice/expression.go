@@ -0,0 +1,547 @@
+package dice
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidExpression is returned when dice expression could not be parsed.
+	ErrInvalidExpression = errors.New("invalid dice expression")
+	// ErrNoDamageBonus is returned when expression refers to DB, but no damage bonus was provided.
+	ErrNoDamageBonus = errors.New("damage bonus is not set")
+)
+
+const (
+	maxDice  = 100
+	maxSides = 1000
+	// maxExpressionLength limits expression size, the longest rulebook formulas are about 20 runes.
+	maxExpressionLength = 64
+	// maxDepth limits nesting of parentheses and unary signs, so parser recursion stays shallow.
+	maxDepth = 16
+)
+
+// Roll is a result of rolling a group of identical dice, e.g. 2D6.
+type Roll struct {
+	Notation string
+	Sides    int
+	Dice     []int
+	Sum      int
+}
+
+// Result of the dice expression evaluation.
+type Result struct {
+	Expression string
+	Total      int
+	Rolls      []Roll
+}
+
+// String returns human-readable representation of the result, e.g. "1D10+2: 1D10[7] = 9".
+func (r Result) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(r.Expression)
+	sb.WriteString(":")
+
+	for _, roll := range r.Rolls {
+		sb.WriteString(" ")
+		sb.WriteString(roll.Notation)
+		sb.WriteString("[")
+
+		for i, d := range roll.Dice {
+			if i != 0 {
+				sb.WriteString(",")
+			}
+
+			sb.WriteString(strconv.Itoa(d))
+		}
+
+		sb.WriteString("]")
+	}
+
+	sb.WriteString(" = ")
+	sb.WriteString(strconv.Itoa(r.Total))
+
+	return sb.String()
+}
+
+// Option configures expression evaluation.
+type Option func(o *options)
+
+type options struct {
+	damageBonus *Expression
+	dbErr       error
+}
+
+// WithDamageBonus sets damage bonus used for DB term, e.g. "+1D4", "-1" or "None".
+func WithDamageBonus(db string) Option {
+	return func(o *options) {
+		o.damageBonus, o.dbErr = parseDamageBonus(db)
+	}
+}
+
+func parseDamageBonus(db string) (*Expression, error) {
+	db = strings.TrimSpace(db)
+
+	switch strings.ToUpper(db) {
+	case "", "NONE", "0":
+		db = "0"
+	}
+
+	db = strings.TrimPrefix(db, "+")
+
+	e, err := Parse(db)
+	if err != nil {
+		return nil, fmt.Errorf("damage bonus: %w", err)
+	}
+
+	if e.usesDB() {
+		return nil, fmt.Errorf("damage bonus: %w: recursive DB", ErrInvalidExpression)
+	}
+
+	return &e, nil
+}
+
+// Expression is a parsed dice expression.
+type Expression struct {
+	raw  string
+	root node
+}
+
+// String returns source of the expression.
+func (e Expression) String() string {
+	return e.raw
+}
+
+func (e Expression) usesDB() bool {
+	return e.root.usesDB()
+}
+
+// Roll evaluates expression with passed roller.
+func (e Expression) Roll(r *Roller, opts ...Option) (Result, error) {
+	var o options
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.dbErr != nil {
+		return Result{}, o.dbErr
+	}
+
+	ev := evaluator{
+		roller: r,
+		opts:   o,
+		rolls:  nil,
+	}
+
+	total, err := e.root.eval(&ev)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Expression: e.raw,
+		Total:      total,
+		Rolls:      ev.rolls,
+	}, nil
+}
+
+type evaluator struct {
+	roller *Roller
+	opts   options
+	rolls  []Roll
+}
+
+type node interface {
+	eval(ev *evaluator) (int, error)
+	usesDB() bool
+}
+
+type numberNode int
+
+func (n numberNode) eval(*evaluator) (int, error) {
+	return int(n), nil
+}
+
+func (numberNode) usesDB() bool {
+	return false
+}
+
+type diceNode struct {
+	count int
+	sides int
+}
+
+func (n diceNode) eval(ev *evaluator) (int, error) {
+	roll := Roll{
+		Notation: fmt.Sprintf("%dD%d", n.count, n.sides),
+		Sides:    n.sides,
+		Dice:     make([]int, 0, n.count),
+		Sum:      0,
+	}
+
+	for range n.count {
+		d := ev.roller.Die(n.sides)
+
+		roll.Dice = append(roll.Dice, d)
+		roll.Sum += d
+	}
+
+	ev.rolls = append(ev.rolls, roll)
+
+	return roll.Sum, nil
+}
+
+func (diceNode) usesDB() bool {
+	return false
+}
+
+type damageBonusNode struct {
+	half bool
+}
+
+func (n damageBonusNode) eval(ev *evaluator) (int, error) {
+	if ev.opts.damageBonus == nil {
+		return 0, ErrNoDamageBonus
+	}
+
+	v, err := ev.opts.damageBonus.root.eval(ev)
+	if err != nil {
+		return 0, err
+	}
+
+	if n.half {
+		v /= 2
+	}
+
+	return v, nil
+}
+
+func (damageBonusNode) usesDB() bool {
+	return true
+}
+
+type binaryNode struct {
+	op          rune
+	left, right node
+}
+
+func (n binaryNode) eval(ev *evaluator) (int, error) {
+	l, err := n.left.eval(ev)
+	if err != nil {
+		return 0, err
+	}
+
+	r, err := n.right.eval(ev)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	case '/':
+		if r == 0 {
+			return 0, fmt.Errorf("%w: division by zero", ErrInvalidExpression)
+		}
+
+		return l / r, nil
+	default:
+		return 0, fmt.Errorf("%w: unknown operator %q", ErrInvalidExpression, n.op)
+	}
+}
+
+func (n binaryNode) usesDB() bool {
+	return n.left.usesDB() || n.right.usesDB()
+}
+
+type negNode struct {
+	n node
+}
+
+func (n negNode) eval(ev *evaluator) (int, error) {
+	v, err := n.n.eval(ev)
+	if err != nil {
+		return 0, err
+	}
+
+	return -v, nil
+}
+
+func (n negNode) usesDB() bool {
+	return n.n.usesDB()
+}
+
+// Parse parses dice expression.
+//
+// Supported syntax (case-insensitive, spaces ignored):
+//   - dice: 1D10, D6, 1D100, D%;
+//   - integer constants: 2, 6;
+//   - damage bonus: DB, ½DB (half damage bonus for thrown weapons);
+//   - operators: +, -, multiplication x, ×, * and integer division /;
+//   - parentheses: (2D6+6)x5.
+//
+// Expressions longer than 64 runes or nested deeper than 16 levels are rejected.
+func Parse(expr string) (Expression, error) {
+	if n := utf8.RuneCountInString(expr); n > maxExpressionLength {
+		return Expression{}, fmt.Errorf("%w: %d runes long, at most %d allowed", ErrInvalidExpression, n, maxExpressionLength)
+	}
+
+	p := parser{
+		src: []rune(strings.ToUpper(strings.Join(strings.Fields(expr), ""))),
+		pos: 0,
+	}
+
+	if len(p.src) == 0 {
+		return Expression{}, fmt.Errorf("%w: empty", ErrInvalidExpression)
+	}
+
+	root, err := p.parseExpr()
+	if err != nil {
+		return Expression{}, fmt.Errorf("%w %q: %w", ErrInvalidExpression, expr, err)
+	}
+
+	if !p.eof() {
+		return Expression{}, fmt.Errorf("%w %q: unexpected %q at %d", ErrInvalidExpression, expr, p.peek(), p.pos)
+	}
+
+	return Expression{
+		raw:  strings.TrimSpace(expr),
+		root: root,
+	}, nil
+}
+
+type parser struct {
+	src   []rune
+	pos   int
+	depth int
+}
+
+// descend counts one more nesting level, it fails when expression is nested too deep.
+func (p *parser) descend() error {
+	p.depth++
+
+	if p.depth > maxDepth {
+		return fmt.Errorf("nested deeper than %d levels", maxDepth)
+	}
+
+	return nil
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+func (p *parser) parseExpr() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for !p.eof() {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			break
+		}
+
+		p.pos++
+
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		left = binaryNode{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for !p.eof() {
+		var op rune
+
+		switch p.peek() {
+		case 'X', '×', '*':
+			op = '*'
+		case '/':
+			op = '/'
+		default:
+			return left, nil
+		}
+
+		p.pos++
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = binaryNode{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	switch p.peek() {
+	case '-', '+':
+		sign := p.peek()
+		p.pos++
+
+		if err := p.descend(); err != nil {
+			return nil, err
+		}
+
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		p.depth--
+
+		if sign == '-' {
+			return negNode{n: n}, nil
+		}
+
+		return n, nil
+	default:
+		return p.parseFactor()
+	}
+}
+
+func (p *parser) parseFactor() (node, error) {
+	switch c := p.peek(); {
+	case c == '(':
+		p.pos++
+
+		if err := p.descend(); err != nil {
+			return nil, err
+		}
+
+		n, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek() != ')' {
+			return nil, errors.New("missing closing parenthesis")
+		}
+
+		p.pos++
+		p.depth--
+
+		return n, nil
+	case c == '½':
+		p.pos++
+
+		if !p.consume("DB") {
+			return nil, errors.New("½ is supported only before DB")
+		}
+
+		return damageBonusNode{half: true}, nil
+	case p.consume("DB"):
+		return damageBonusNode{half: false}, nil
+	case c == 'D':
+		return p.parseDice(1)
+	case isDigit(c):
+		n := p.parseNumber()
+
+		if p.peek() == 'D' && !p.lookingAt("DB") {
+			return p.parseDice(n)
+		}
+
+		return numberNode(n), nil
+	case c == 0:
+		return nil, errors.New("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q at %d", c, p.pos)
+	}
+}
+
+func (p *parser) parseDice(count int) (node, error) {
+	p.pos++ // skip D
+
+	var sides int
+
+	switch c := p.peek(); {
+	case c == '%':
+		p.pos++
+
+		sides = 100
+	case isDigit(c):
+		sides = p.parseNumber()
+	default:
+		return nil, fmt.Errorf("missing dice sides at %d", p.pos)
+	}
+
+	if count < 1 || count > maxDice {
+		return nil, fmt.Errorf("dice count must be in range 1..%d", maxDice)
+	}
+
+	if sides < 1 || sides > maxSides {
+		return nil, fmt.Errorf("dice sides must be in range 1..%d", maxSides)
+	}
+
+	return diceNode{count: count, sides: sides}, nil
+}
+
+func (p *parser) parseNumber() int {
+	var n int
+
+	for !p.eof() && isDigit(p.peek()) {
+		n = n*10 + int(p.peek()-'0')
+		p.pos++
+
+		if n > maxSides*maxSides {
+			n = maxSides * maxSides
+		}
+	}
+
+	return n
+}
+
+func (p *parser) lookingAt(s string) bool {
+	rs := []rune(s)
+
+	if p.pos+len(rs) > len(p.src) {
+		return false
+	}
+
+	return string(p.src[p.pos:p.pos+len(rs)]) == s
+}
+
+func (p *parser) consume(s string) bool {
+	if !p.lookingAt(s) {
+		return false
+	}
+
+	p.pos += len([]rune(s))
+
+	return true
+}
+
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
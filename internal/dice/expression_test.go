@@ -0,0 +1,207 @@
+package dice_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice/dicetest"
+)
+
+func TestRoller_Roll(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		faces   []int
+		opts    []dice.Option
+		want    int
+		rolls   []dice.Roll
+		wantErr error
+	}{
+		{
+			name:  "weapon damage with bonus",
+			expr:  "1D10+2+DB",
+			faces: []int{7, 3},
+			opts:  []dice.Option{dice.WithDamageBonus("+1D4")},
+			want:  12,
+			rolls: []dice.Roll{
+				{Notation: "1D10", Sides: 10, Dice: []int{7}, Sum: 7},
+				{Notation: "1D4", Sides: 4, Dice: []int{3}, Sum: 3},
+			},
+		},
+		{
+			name:  "lowercase db none",
+			expr:  "1d4+db",
+			faces: []int{2},
+			opts:  []dice.Option{dice.WithDamageBonus("None")},
+			want:  2,
+			rolls: []dice.Roll{{Notation: "1D4", Sides: 4, Dice: []int{2}, Sum: 2}},
+		},
+		{
+			name:  "negative bonus",
+			expr:  "1D3+DB",
+			faces: []int{1},
+			opts:  []dice.Option{dice.WithDamageBonus("-2")},
+			want:  -1,
+			rolls: []dice.Roll{{Notation: "1D3", Sides: 3, Dice: []int{1}, Sum: 1}},
+		},
+		{
+			name:  "two dice groups",
+			expr:  "1D6+1D4",
+			faces: []int{6, 4},
+			want:  10,
+			rolls: []dice.Roll{
+				{Notation: "1D6", Sides: 6, Dice: []int{6}, Sum: 6},
+				{Notation: "1D4", Sides: 4, Dice: []int{4}, Sum: 4},
+			},
+		},
+		{
+			name:  "constant",
+			expr:  "2D6+6",
+			faces: []int{1, 2},
+			want:  9,
+			rolls: []dice.Roll{{Notation: "2D6", Sides: 6, Dice: []int{1, 2}, Sum: 3}},
+		},
+		{
+			name:  "multiplied",
+			expr:  "3D6x5",
+			faces: []int{3, 4, 5},
+			want:  60,
+			rolls: []dice.Roll{{Notation: "3D6", Sides: 6, Dice: []int{3, 4, 5}, Sum: 12}},
+		},
+		{
+			name:  "parentheses",
+			expr:  "(2D6+6)×5",
+			faces: []int{1, 1},
+			want:  40,
+			rolls: []dice.Roll{{Notation: "2D6", Sides: 6, Dice: []int{1, 1}, Sum: 2}},
+		},
+		{
+			name:  "half damage bonus",
+			expr:  "1D4+½DB",
+			faces: []int{1, 6},
+			opts:  []dice.Option{dice.WithDamageBonus("1D6")},
+			want:  4,
+			rolls: []dice.Roll{
+				{Notation: "1D4", Sides: 4, Dice: []int{1}, Sum: 1},
+				{Notation: "1D6", Sides: 6, Dice: []int{6}, Sum: 6},
+			},
+		},
+		{
+			name:  "percentile",
+			expr:  "D%",
+			faces: []int{42},
+			want:  42,
+			rolls: []dice.Roll{{Notation: "1D100", Sides: 100, Dice: []int{42}, Sum: 42}},
+		},
+		{
+			name:    "missing damage bonus",
+			expr:    "1D4+DB",
+			faces:   []int{1},
+			wantErr: dice.ErrNoDamageBonus,
+		},
+		{
+			name:    "garbage",
+			expr:    "1D6+foo",
+			wantErr: dice.ErrInvalidExpression,
+		},
+		{
+			name:    "empty",
+			expr:    "",
+			wantErr: dice.ErrInvalidExpression,
+		},
+		{
+			name:    "unbalanced",
+			expr:    "(1D6+2",
+			wantErr: dice.ErrInvalidExpression,
+		},
+		{
+			name:    "zero sides",
+			expr:    "1D0",
+			wantErr: dice.ErrInvalidExpression,
+		},
+		{
+			name:    "too long",
+			expr:    strings.Repeat("1+", 32) + "1",
+			wantErr: dice.ErrInvalidExpression,
+		},
+		{
+			name:    "nested too deep",
+			expr:    strings.Repeat("(", 17) + "1" + strings.Repeat(")", 17),
+			wantErr: dice.ErrInvalidExpression,
+		},
+		{
+			name:    "too many signs",
+			expr:    strings.Repeat("-", 17) + "1",
+			wantErr: dice.ErrInvalidExpression,
+		},
+		{
+			name:  "nested",
+			expr:  strings.Repeat("(", 16) + "1D6" + strings.Repeat(")", 16),
+			faces: []int{4},
+			want:  4,
+			rolls: []dice.Roll{{Notation: "1D6", Sides: 6, Dice: []int{4}, Sum: 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faces := tt.faces
+			if len(faces) == 0 {
+				faces = []int{1}
+			}
+
+			got, err := dicetest.NewRoller(faces...).Roll(tt.expr, tt.opts...)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Total)
+			assert.Equal(t, tt.rolls, got.Rolls)
+			assert.Equal(t, tt.expr, got.Expression)
+		})
+	}
+}
+
+func TestNewSeededRoller(t *testing.T) {
+	roll := func() []int {
+		r := dice.NewSeededRoller(42)
+
+		res := make([]int, 0, 10)
+
+		for range 10 {
+			got, err := r.Roll("1D100")
+			require.NoError(t, err)
+
+			res = append(res, got.Total)
+		}
+
+		return res
+	}
+
+	assert.Equal(t, roll(), roll())
+}
+
+func TestMax(t *testing.T) {
+	got, err := dice.Max("1D10+2+DB", dice.WithDamageBonus("+1D4"))
+	require.NoError(t, err)
+	assert.Equal(t, 16, got.Total)
+}
+
+func TestResult_String(t *testing.T) {
+	got, err := dicetest.NewRoller(7, 3).Roll("2D10+2")
+	require.NoError(t, err)
+	assert.Equal(t, "2D10+2: 2D10[7,3] = 12", got.String())
+}
+
+func TestParse_Huge(t *testing.T) {
+	// Unbounded recursion used to overflow the stack and kill the process.
+	_, err := dice.Parse(strings.Repeat("(", 3_000_000) + "1")
+	require.ErrorIs(t, err, dice.ErrInvalidExpression)
+}
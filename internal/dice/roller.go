@@ -0,0 +1,72 @@
+// Package dice implements dice expressions used in Call of Cthulhu 7e.
+package dice
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+// Source is a random numbers source used to roll dice.
+// IntN returns a random number in [0, n).
+// *rand.Rand from math/rand/v2 satisfies it.
+type Source interface {
+	IntN(n int) int
+}
+
+// Roller rolls dice using configured source. It is safe for concurrent use.
+type Roller struct {
+	mu  sync.Mutex
+	src Source
+}
+
+// NewRoller creates Roller that uses passed source.
+func NewRoller(src Source) *Roller {
+	return &Roller{
+		mu:  sync.Mutex{},
+		src: src,
+	}
+}
+
+// NewSeededRoller creates Roller with a deterministic source, useful for reproducible rolls.
+func NewSeededRoller(seed uint64) *Roller {
+	return NewRoller(rand.New(rand.NewPCG(seed, seed))) //nolint:gosec // dice do not need crypto.
+}
+
+// NewRandomRoller creates Roller with a randomly seeded source.
+func NewRandomRoller() *Roller {
+	return NewSeededRoller(rand.Uint64()) //nolint:gosec // dice do not need crypto.
+}
+
+// Die rolls a single die with given number of sides.
+func (r *Roller) Die(sides int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.src.IntN(sides) + 1
+}
+
+// Roll parses and rolls the dice expression.
+func (r *Roller) Roll(expr string, opts ...Option) (Result, error) {
+	e, err := Parse(expr)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return e.Roll(r, opts...)
+}
+
+// Max evaluates the dice expression as if every die rolled its maximum.
+func Max(expr string, opts ...Option) (Result, error) {
+	e, err := Parse(expr)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return e.Roll(NewRoller(maxSource{}), opts...)
+}
+
+type maxSource struct{}
+
+func (maxSource) IntN(n int) int {
+	return n - 1
+}
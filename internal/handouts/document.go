@@ -0,0 +1,188 @@
+package handouts
+
+import (
+	"fmt"
+	"strings"
+)
+
+type fontKind int
+
+const (
+	fontRegular fontKind = iota
+	fontItalic
+	fontBold
+	fontMono
+	fontSmallCaps
+)
+
+type align int
+
+const (
+	alignLeft align = iota
+	alignCenter
+	alignRight
+)
+
+type rgb struct {
+	r, g, b uint8
+}
+
+// block is a paragraph of the handout layout, shared by PDF and PNG renderers.
+type block struct {
+	text  string
+	font  fontKind
+	size  float64 // points.
+	align align
+	// strip draws text on a pasted paper strip like telegram tape.
+	strip bool
+	// rule draws horizontal line after the block.
+	rule  bool
+	after float64 // space after the block, points.
+}
+
+// document is a renderer independent handout layout.
+type document struct {
+	paper rgb
+	ink   rgb
+	// width of the document in points.
+	width  float64
+	blocks []block
+}
+
+const (
+	letterWidth    = 420.0
+	telegramWidth  = 460.0
+	newspaperWidth = 360.0
+)
+
+func (h Handout) document() document {
+	switch h.Kind {
+	case KindTelegram:
+		return h.telegramDocument()
+	case KindNewspaper:
+		return h.newspaperDocument()
+	default:
+		return h.letterDocument()
+	}
+}
+
+func (h Handout) letterDocument() document {
+	var blocks []block
+
+	if h.Place != "" || h.Date != "" {
+		blocks = append(blocks, block{
+			text:  strings.Trim(strings.Join([]string{h.Place, h.Date}, ", "), ", "),
+			font:  fontItalic,
+			size:  11,
+			align: alignRight,
+			after: 14,
+		})
+	}
+
+	if h.Recipient != "" {
+		blocks = append(blocks, block{
+			text:  fmt.Sprintf("Dear %s,", h.Recipient),
+			font:  fontItalic,
+			size:  12,
+			after: 8,
+		})
+	}
+
+	blocks = append(blocks, block{
+		text:  h.Body,
+		font:  fontItalic,
+		size:  12,
+		after: 14,
+	})
+
+	if h.Sender != "" {
+		blocks = append(blocks, block{
+			text:  h.Sender,
+			font:  fontItalic,
+			size:  13,
+			align: alignRight,
+		})
+	}
+
+	return document{
+		paper:  rgb{r: 245, g: 236, b: 212},
+		ink:    rgb{r: 40, g: 34, b: 80},
+		width:  letterWidth,
+		blocks: blocks,
+	}
+}
+
+func (h Handout) telegramDocument() document {
+	t := h.Telegram()
+
+	header := fmt.Sprintf("%s  %d WORDS  %s", t.Number, t.Words, t.Charge)
+
+	received := "RECEIVED AT"
+	if h.Place != "" {
+		received += " " + strings.ToUpper(h.Place)
+	}
+
+	if h.Date != "" {
+		received += "  " + strings.ToUpper(h.Date)
+	}
+
+	blocks := []block{
+		{text: "WESTERN UNION", font: fontBold, size: 22, align: alignCenter, after: 2},
+		{text: "TELEGRAM", font: fontSmallCaps, size: 12, align: alignCenter, rule: true, after: 6},
+		{text: "CLASS OF SERVICE: FULL-RATE", font: fontRegular, size: 7, align: alignLeft, after: 2},
+		{text: header, font: fontMono, size: 9, align: alignLeft, after: 2},
+		{text: received, font: fontMono, size: 9, align: alignLeft, after: 10},
+	}
+
+	if h.Recipient != "" {
+		blocks = append(blocks, block{text: strings.ToUpper(h.Recipient), font: fontMono, size: 11, strip: true, after: 6})
+	}
+
+	blocks = append(blocks, block{text: t.Text, font: fontMono, size: 11, strip: true, after: 6})
+
+	if h.Sender != "" {
+		blocks = append(blocks, block{text: strings.ToUpper(h.Sender), font: fontMono, size: 11, strip: true, after: 10})
+	}
+
+	blocks = append(blocks, block{
+		text:  "THE COMPANY WILL APPRECIATE SUGGESTIONS FROM ITS PATRONS CONCERNING ITS SERVICE",
+		font:  fontRegular,
+		size:  6,
+		align: alignCenter,
+	})
+
+	return document{
+		paper:  rgb{r: 236, g: 224, b: 170},
+		ink:    rgb{r: 30, g: 30, b: 30},
+		width:  telegramWidth,
+		blocks: blocks,
+	}
+}
+
+func (h Handout) newspaperDocument() document {
+	var blocks []block
+
+	if h.Sender != "" {
+		blocks = append(blocks, block{text: h.Sender, font: fontSmallCaps, size: 20, align: alignCenter, rule: true, after: 4})
+	}
+
+	if h.Date != "" {
+		blocks = append(blocks, block{text: h.Date, font: fontRegular, size: 8, align: alignCenter, rule: true, after: 8})
+	}
+
+	blocks = append(blocks, block{text: strings.ToUpper(h.Headline), font: fontBold, size: 16, align: alignCenter, after: 8})
+
+	body := h.Body
+	if h.Place != "" {
+		body = strings.ToUpper(h.Place) + " — " + body
+	}
+
+	blocks = append(blocks, block{text: body, font: fontRegular, size: 10})
+
+	return document{
+		paper:  rgb{r: 232, g: 228, b: 214},
+		ink:    rgb{r: 20, g: 20, b: 20},
+		width:  newspaperWidth,
+		blocks: blocks,
+	}
+}
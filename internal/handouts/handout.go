@@ -0,0 +1,118 @@
+// Package handouts implements in-game handouts: letters, telegrams and newspaper clippings.
+package handouts
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Kind of the handout.
+type Kind string
+
+const (
+	KindLetter    Kind = "letter"
+	KindTelegram  Kind = "telegram"
+	KindNewspaper Kind = "newspaper"
+)
+
+// Kinds returns all supported handout kinds.
+func Kinds() []Kind {
+	return []Kind{KindLetter, KindTelegram, KindNewspaper}
+}
+
+// ParseKind parses handout kind.
+func ParseKind(s string) (Kind, error) {
+	for _, k := range Kinds() {
+		if strings.EqualFold(s, string(k)) {
+			return k, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: unknown kind %q", ErrInvalid, s)
+}
+
+// ErrInvalid is returned when handout misses required fields.
+var ErrInvalid = errors.New("invalid handout")
+
+const (
+	// MaxBodyLength is a maximum number of characters in the handout body.
+	MaxBodyLength = 5000
+	// MaxFieldLength is a maximum number of characters in other handout fields.
+	MaxFieldLength = 200
+)
+
+// Handout is a prop given to players during the game.
+//
+// Meaning of the fields depends on the kind:
+//   - letter: Sender is a signature, Recipient is addressee, Place is where the letter was written;
+//   - telegram: Sender and Recipient are telegram parties, Place is the origin office;
+//   - newspaper: Sender is a newspaper name, Headline is an article title, Place is a dateline city.
+type Handout struct {
+	ID        string    `json:"id"`
+	Kind      Kind      `json:"kind"`
+	Title     string    `json:"title"`
+	Date      string    `json:"date"`
+	Sender    string    `json:"sender"`
+	Recipient string    `json:"recipient"`
+	Place     string    `json:"place"`
+	Headline  string    `json:"headline"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	// KeeperID is ID of the user who made the handout, empty for handouts created without authentication.
+	KeeperID string `json:"keeper_id,omitempty"`
+}
+
+// Validate checks that handout has all required fields for its kind.
+func (h Handout) Validate() error {
+	var errs error
+
+	if _, err := ParseKind(string(h.Kind)); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	if strings.TrimSpace(h.Body) == "" {
+		errs = errors.Join(errs, fmt.Errorf("%w: body is required", ErrInvalid))
+	}
+
+	if h.Kind == KindNewspaper && strings.TrimSpace(h.Headline) == "" {
+		errs = errors.Join(errs, fmt.Errorf("%w: headline is required for newspaper clipping", ErrInvalid))
+	}
+
+	if utf8.RuneCountInString(h.Body) > MaxBodyLength {
+		errs = errors.Join(errs, fmt.Errorf("%w: body is longer than %d characters", ErrInvalid, MaxBodyLength))
+	}
+
+	for _, f := range []struct {
+		name  string
+		value string
+	}{
+		{name: "title", value: h.Title},
+		{name: "date", value: h.Date},
+		{name: "sender", value: h.Sender},
+		{name: "recipient", value: h.Recipient},
+		{name: "place", value: h.Place},
+		{name: "headline", value: h.Headline},
+	} {
+		if utf8.RuneCountInString(f.value) > MaxFieldLength {
+			errs = errors.Join(errs, fmt.Errorf("%w: %s is longer than %d characters", ErrInvalid, f.name, MaxFieldLength))
+		}
+	}
+
+	return errs
+}
+
+// DisplayTitle returns title to show in lists.
+func (h Handout) DisplayTitle() string {
+	if h.Title != "" {
+		return h.Title
+	}
+
+	if h.Headline != "" {
+		return h.Headline
+	}
+
+	return fmt.Sprintf("%s %s", h.Kind, h.Date)
+}
@@ -0,0 +1,149 @@
+package handouts
+
+import (
+	"bytes"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelegramText(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{
+			name: "sentences",
+			msg:  "Arrived in Arkham. Professor missing!",
+			want: "ARRIVED IN ARKHAM STOP PROFESSOR MISSING STOP",
+		},
+		{
+			name: "punctuation dropped",
+			msg:  "Come at once, bring the book; tell no one...",
+			want: "COME AT ONCE BRING THE BOOK TELL NO ONE STOP",
+		},
+		{
+			name: "keeps apostrophes and amounts",
+			msg:  "Don't wire $50?!",
+			want: "DON'T WIRE $50 STOP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, TelegramText(tt.msg))
+		})
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	assert.Equal(t, 6, CountWords("ARRIVED IN ARKHAM STOP PROFESSOR MISSING"))
+	assert.Equal(t, 3, CountWords("ANTIDISESTABLISHMENTARIANISM STOP"))
+	assert.Equal(t, 0, CountWords(""))
+}
+
+func TestRate_Charge(t *testing.T) {
+	assert.Equal(t, 36, ClassicRate.Charge(3))
+	assert.Equal(t, 36, ClassicRate.Charge(10))
+	assert.Equal(t, 51, ClassicRate.Charge(15))
+	assert.Equal(t, "$0.51", FormatCents(51))
+	assert.Equal(t, "$12.05", FormatCents(1205))
+}
+
+func TestHandout_Telegram(t *testing.T) {
+	h := Handout{
+		ID:   "3f2a9c1e-0000-0000-0000-000000000000",
+		Kind: KindTelegram,
+		Body: "Arrived in Arkham. Professor missing. Send money.",
+	}
+
+	assert.Equal(t, Telegram{
+		Text:   "ARRIVED IN ARKHAM STOP PROFESSOR MISSING STOP SEND MONEY STOP",
+		Words:  10,
+		Charge: "$0.36",
+		Number: "3F2A9C",
+	}, h.Telegram())
+}
+
+func TestHandout_Validate(t *testing.T) {
+	require.NoError(t, Handout{Kind: KindLetter, Body: "text"}.Validate())
+	require.ErrorIs(t, Handout{Kind: KindLetter}.Validate(), ErrInvalid)
+	require.ErrorIs(t, Handout{Kind: "scroll", Body: "text"}.Validate(), ErrInvalid)
+	require.ErrorIs(t, Handout{Kind: KindNewspaper, Body: "text"}.Validate(), ErrInvalid)
+	require.NoError(t, Handout{Kind: KindLetter, Body: strings.Repeat("ф", MaxBodyLength)}.Validate())
+	require.ErrorIs(t, Handout{Kind: KindLetter, Body: strings.Repeat("ф", MaxBodyLength+1)}.Validate(), ErrInvalid)
+	require.ErrorIs(t, Handout{Kind: KindLetter, Body: "text", Sender: strings.Repeat("a", MaxFieldLength+1)}.Validate(), ErrInvalid)
+}
+
+func testHandouts() []Handout {
+	return []Handout{
+		{
+			ID:        "letter",
+			Kind:      KindLetter,
+			Date:      "March 3rd, 1925",
+			Place:     "Arkham",
+			Sender:    "Prof. Henry Armitage",
+			Recipient: "Mr. Smith",
+			Body:      "I write to you in haste.\nThe volume you enquired about has vanished from the restricted collection.",
+		},
+		{
+			ID:        "telegram",
+			Kind:      KindTelegram,
+			Date:      "Mar 4 1925 9:15 AM",
+			Place:     "Boston Mass",
+			Sender:    "Armitage",
+			Recipient: "Richard Smith, Chicago",
+			Body:      "Book stolen. Come at once.",
+		},
+		{
+			ID:       "newspaper",
+			Kind:     KindNewspaper,
+			Date:     "Thursday, March 5, 1925",
+			Place:    "Arkham",
+			Sender:   "The Arkham Advertiser",
+			Headline: "Burglary at Miskatonic Library",
+			Body:     "Police are investigating a break-in at the university library. Ричард Смит assists.",
+		},
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	for _, h := range testHandouts() {
+		t.Run(string(h.Kind), func(t *testing.T) {
+			var buf bytes.Buffer
+
+			require.NoError(t, RenderPDF(&buf, h))
+			assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+		})
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	for _, h := range testHandouts() {
+		t.Run(string(h.Kind), func(t *testing.T) {
+			var buf bytes.Buffer
+
+			require.NoError(t, RenderPNG(&buf, h))
+
+			img, err := png.Decode(&buf)
+			require.NoError(t, err)
+			assert.Positive(t, img.Bounds().Dx())
+			assert.Positive(t, img.Bounds().Dy())
+		})
+	}
+}
+
+func TestRenderPNG_TooLarge(t *testing.T) {
+	h := Handout{
+		Kind: KindLetter,
+		Body: strings.Repeat("a\n", MaxBodyLength/2),
+	}
+
+	require.NoError(t, h.Validate())
+	require.ErrorIs(t, RenderPNG(io.Discard, h), ErrTooLarge)
+}
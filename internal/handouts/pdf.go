@@ -0,0 +1,125 @@
+package handouts
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/gofont/gosmallcaps"
+)
+
+const pdfMargin = 36.0
+
+var pdfFonts = map[fontKind]struct {
+	family string
+	ttf    []byte
+}{
+	fontRegular:   {family: "goregular", ttf: goregular.TTF},
+	fontItalic:    {family: "goitalic", ttf: goitalic.TTF},
+	fontBold:      {family: "gobold", ttf: gobold.TTF},
+	fontMono:      {family: "gomono", ttf: gomono.TTF},
+	fontSmallCaps: {family: "gosmallcaps", ttf: gosmallcaps.TTF},
+}
+
+// RenderPDF writes handout as a single page PDF sized to its content.
+func RenderPDF(w io.Writer, h Handout) error {
+	doc := h.document()
+
+	width := doc.width + 2*pdfMargin
+	height := pdfHeight(doc)
+
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "pt",
+		Size:           fpdf.SizeType{Wd: width, Ht: height},
+	})
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetTitle(h.DisplayTitle(), true)
+	pdf.SetCreator("cthulhu-mythos-tools", true)
+
+	for _, f := range pdfFonts {
+		pdf.AddUTF8FontFromBytes(f.family, "", f.ttf)
+	}
+
+	pdf.AddPage()
+
+	pdf.SetFillColor(int(doc.paper.r), int(doc.paper.g), int(doc.paper.b))
+	pdf.Rect(0, 0, width, height, "F")
+
+	pdf.SetTextColor(int(doc.ink.r), int(doc.ink.g), int(doc.ink.b))
+	pdf.SetDrawColor(int(doc.ink.r), int(doc.ink.g), int(doc.ink.b))
+	pdf.SetY(pdfMargin)
+
+	for _, b := range doc.blocks {
+		pdf.SetFont(pdfFonts[b.font].family, "", b.size)
+
+		lineH := b.size * lineSpacing
+
+		if b.strip {
+			pdf.SetFillColor(250, 248, 240)
+		}
+
+		pdf.MultiCell(doc.width, lineH, normalizeText(b.text), "", pdfAlign(b.align), b.strip)
+
+		if b.rule {
+			y := pdf.GetY() + 1
+			pdf.Line(pdfMargin, y, pdfMargin+doc.width, y)
+		}
+
+		pdf.Ln(b.after)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("render handout pdf: %w", err)
+	}
+
+	return pdf.Output(w)
+}
+
+const lineSpacing = 1.35
+
+// pdfHeight estimates page height big enough to fit the document.
+func pdfHeight(doc document) float64 {
+	const (
+		minHeight = 200.0
+		// average glyph width relative to font size.
+		glyphWidth = 0.55
+	)
+
+	height := 2 * pdfMargin
+
+	for _, b := range doc.blocks {
+		perLine := int(doc.width / (b.size * glyphWidth))
+
+		lines := 0
+
+		for _, p := range strings.Split(normalizeText(b.text), "\n") {
+			lines += len([]rune(p))/max(perLine, 1) + 1
+		}
+
+		height += float64(lines)*b.size*lineSpacing + b.after + 2
+	}
+
+	return max(height, minHeight)
+}
+
+func pdfAlign(a align) string {
+	switch a {
+	case alignCenter:
+		return "C"
+	case alignRight:
+		return "R"
+	default:
+		return "L"
+	}
+}
+
+func normalizeText(s string) string {
+	return strings.TrimSpace(strings.ReplaceAll(s, "\r", ""))
+}
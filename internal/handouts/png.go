@@ -0,0 +1,199 @@
+package handouts
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// pngScale is a number of pixels per point.
+	pngScale  = 2.0
+	pngMargin = pdfMargin * pngScale
+	pngDPI    = 72 * pngScale
+	// maxPNGHeight limits height of the image in pixels, so a body of blank lines
+	// could not make a canvas of gigabytes.
+	maxPNGHeight = 10000
+)
+
+// ErrTooLarge is returned when handout does not fit into the rendered image.
+var ErrTooLarge = errors.New("handout is too large to render")
+
+// RenderPNG writes handout as PNG image.
+func RenderPNG(w io.Writer, h Handout) error {
+	doc := h.document()
+
+	faces := make(map[fontKind]map[float64]font.Face)
+
+	defer func() {
+		for _, sized := range faces {
+			for _, f := range sized {
+				_ = f.Close()
+			}
+		}
+	}()
+
+	face := func(kind fontKind, size float64) (font.Face, error) {
+		if f, ok := faces[kind][size]; ok {
+			return f, nil
+		}
+
+		parsed, err := opentype.Parse(pdfFonts[kind].ttf)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := opentype.NewFace(parsed, &opentype.FaceOptions{
+			Size:    size,
+			DPI:     pngDPI,
+			Hinting: font.HintingFull,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if faces[kind] == nil {
+			faces[kind] = make(map[float64]font.Face)
+		}
+
+		faces[kind][size] = f
+
+		return f, nil
+	}
+
+	width := int(doc.width*pngScale + 2*pngMargin)
+	textWidth := fixed.I(int(doc.width * pngScale))
+
+	type line struct {
+		text string
+		face font.Face
+	}
+
+	type laidBlock struct {
+		b     block
+		lines []line
+	}
+
+	var (
+		laid   []laidBlock
+		height = 2 * pngMargin
+	)
+
+	for _, b := range doc.blocks {
+		f, err := face(b.font, b.size)
+		if err != nil {
+			return fmt.Errorf("load font: %w", err)
+		}
+
+		lb := laidBlock{b: b}
+
+		for _, l := range wrapText(f, normalizeText(b.text), textWidth) {
+			lb.lines = append(lb.lines, line{text: l, face: f})
+		}
+
+		height += float64(len(lb.lines))*b.size*lineSpacing*pngScale + b.after*pngScale
+
+		if height > maxPNGHeight {
+			return ErrTooLarge
+		}
+
+		laid = append(laid, lb)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, int(height)))
+
+	paper := color.RGBA{R: doc.paper.r, G: doc.paper.g, B: doc.paper.b, A: 0xff}
+	ink := color.RGBA{R: doc.ink.r, G: doc.ink.g, B: doc.ink.b, A: 0xff}
+	strip := color.RGBA{R: 250, G: 248, B: 240, A: 0xff}
+
+	draw.Draw(img, img.Bounds(), image.NewUniform(paper), image.Point{}, draw.Src)
+
+	y := pngMargin
+
+	for _, lb := range laid {
+		lineH := lb.b.size * lineSpacing * pngScale
+
+		for _, l := range lb.lines {
+			d := font.Drawer{
+				Dst:  img,
+				Src:  image.NewUniform(ink),
+				Face: l.face,
+			}
+
+			adv := d.MeasureString(l.text)
+
+			x := fixed.I(int(pngMargin))
+
+			switch lb.b.align {
+			case alignCenter:
+				x += (textWidth - adv) / 2
+			case alignRight:
+				x += textWidth - adv
+			case alignLeft:
+			}
+
+			if lb.b.strip {
+				r := image.Rect(int(pngMargin)-4, int(y), int(pngMargin)+adv.Ceil()+4, int(y+lineH))
+				draw.Draw(img, r, image.NewUniform(strip), image.Point{}, draw.Src)
+			}
+
+			ascent := l.face.Metrics().Ascent
+
+			d.Dot = fixed.Point26_6{X: x, Y: fixed.I(int(y)) + ascent}
+			d.DrawString(l.text)
+
+			y += lineH
+		}
+
+		if lb.b.rule {
+			r := image.Rect(int(pngMargin), int(y)+1, width-int(pngMargin), int(y)+1+int(pngScale))
+			draw.Draw(img, r, image.NewUniform(ink), image.Point{}, draw.Src)
+		}
+
+		y += lb.b.after * pngScale
+	}
+
+	return png.Encode(w, img)
+}
+
+// wrapText splits text into lines that fit into width.
+func wrapText(f font.Face, text string, width fixed.Int26_6) []string {
+	var lines []string
+
+	for _, para := range strings.Split(text, "\n") {
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			lines = append(lines, "")
+
+			continue
+		}
+
+		cur := words[0]
+
+		for _, w := range words[1:] {
+			candidate := cur + " " + w
+
+			if font.MeasureString(f, candidate) > width {
+				lines = append(lines, cur)
+				cur = w
+
+				continue
+			}
+
+			cur = candidate
+		}
+
+		lines = append(lines, cur)
+	}
+
+	return lines
+}
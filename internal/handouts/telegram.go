@@ -0,0 +1,119 @@
+package handouts
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// stopWord replaces full stops in telegrams.
+	stopWord = "STOP"
+	// maxWordLength is a number of characters charged as a single word.
+	maxWordLength = 15
+)
+
+// Rate is a telegram tariff: fixed charge for the first words and charge for each extra word.
+type Rate struct {
+	BaseWords  int
+	BaseCents  int
+	ExtraCents int
+}
+
+// ClassicRate is an approximate 1920s domestic full-rate tariff.
+var ClassicRate = Rate{
+	BaseWords:  10,
+	BaseCents:  36,
+	ExtraCents: 3,
+}
+
+// Charge returns telegram cost in cents for the number of words.
+func (r Rate) Charge(words int) int {
+	if words <= r.BaseWords {
+		return r.BaseCents
+	}
+
+	return r.BaseCents + (words-r.BaseWords)*r.ExtraCents
+}
+
+// FormatCents formats cents as dollars, e.g. $0.42.
+func FormatCents(cents int) string {
+	const centsInDollar = 100
+
+	return fmt.Sprintf("$%d.%02d", cents/centsInDollar, cents%centsInDollar)
+}
+
+// TelegramText converts message to telegraphic style: upper case,
+// sentence ends replaced with STOP and other punctuation dropped.
+func TelegramText(msg string) string {
+	var sb strings.Builder
+
+	for _, r := range strings.ToUpper(msg) {
+		switch {
+		case r == '.' || r == '!' || r == '?':
+			sb.WriteString(" " + stopWord + " ")
+		case r == '\'' || r == '-' || r == '$' || r == '%' || r == '&':
+			sb.WriteRune(r)
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			sb.WriteRune(' ')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	words := strings.Fields(sb.String())
+
+	// Collapse repeated STOPs produced by "..." or "?!".
+	res := words[:0]
+
+	for _, w := range words {
+		if w == stopWord && len(res) > 0 && res[len(res)-1] == stopWord {
+			continue
+		}
+
+		res = append(res, w)
+	}
+
+	return strings.Join(res, " ")
+}
+
+// CountWords counts chargeable words of telegraphic text.
+// STOP is charged as a word, words longer than 15 characters are charged for each 15 characters.
+func CountWords(text string) int {
+	var n int
+
+	for _, w := range strings.Fields(text) {
+		n += (utf8.RuneCountInString(w) + maxWordLength - 1) / maxWordLength
+	}
+
+	return n
+}
+
+// Telegram holds telegram specific values derived from the handout.
+type Telegram struct {
+	Text   string
+	Words  int
+	Charge string
+	Number string
+}
+
+// Telegram returns telegram rendering of the handout body billed with the ClassicRate.
+func (h Handout) Telegram() Telegram {
+	text := TelegramText(h.Body)
+	words := CountWords(text)
+
+	const numberLen = 6
+
+	number := strings.ToUpper(strings.ReplaceAll(h.ID, "-", ""))
+	if len(number) > numberLen {
+		number = number[:numberLen]
+	}
+
+	return Telegram{
+		Text:   text,
+		Words:  words,
+		Charge: FormatCents(ClassicRate.Charge(words)),
+		Number: number,
+	}
+}
@@ -0,0 +1,152 @@
+// Package metrics exposes Prometheus metrics of the service: HTTP requests and domain events.
+package metrics
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+const namespace = "cthulhu_mythos_tools"
+
+// unmatchedRoute labels requests that did not match any route, so unknown paths do not create new series.
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels requests with non-standard methods, so arbitrary methods do not create new series.
+const otherMethod = "other"
+
+// knownMethods are HTTP methods reported as is.
+var knownMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodHead,
+	http.MethodOptions,
+}
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of processed HTTP requests by route pattern and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of processed HTTP requests by route pattern and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	characterImports = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "character_imports_total",
+		Help:      "Number of investigator sheet imports by result.",
+	}, []string{"result"})
+
+	diceRolled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dice_rolled_total",
+		Help:      "Number of rolled dice by number of sides.",
+	}, []string{"sides"})
+)
+
+// ObserveRequest records processed HTTP request. Route is a path pattern of the matched route, empty when no route matched.
+func ObserveRequest(method, route string, status int, latency time.Duration) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+
+	if !slices.Contains(knownMethods, method) {
+		method = otherMethod
+	}
+
+	code := strconv.Itoa(status)
+
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(latency.Seconds())
+}
+
+// CharacterImported records result of the investigator sheet import.
+func CharacterImported(ok bool) {
+	result := "failure"
+	if ok {
+		result = "success"
+	}
+
+	characterImports.WithLabelValues(result).Inc()
+}
+
+// CountDice wraps dice source to count rolled dice.
+func CountDice(src dice.Source) dice.Source {
+	return countingSource{src: src}
+}
+
+type countingSource struct {
+	src dice.Source
+}
+
+func (s countingSource) IntN(n int) int {
+	diceRolled.WithLabelValues(strconv.Itoa(n)).Inc()
+
+	return s.src.IntN(n)
+}
+
+// Handler creates HTTP handler serving metrics in Prometheus format.
+// characters returns number of stored characters, it is called on each scrape and should be cheap.
+func Handler(characters func() (int, error)) http.Handler {
+	reg := prometheus.NewRegistry()
+
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		characterImports,
+		diceRolled,
+		newCharactersCollector(characters),
+	)
+
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
+
+// charactersCollector reports number of stored characters, scrape fails when storage can not be read.
+type charactersCollector struct {
+	desc  *prometheus.Desc
+	count func() (int, error)
+}
+
+func newCharactersCollector(count func() (int, error)) *charactersCollector {
+	return &charactersCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "characters"),
+			"Number of stored characters.",
+			nil, nil,
+		),
+		count: count,
+	}
+}
+
+func (c *charactersCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *charactersCollector) Collect(ch chan<- prometheus.Metric) {
+	n, err := c.count()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n))
+}
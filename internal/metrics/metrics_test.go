@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+func scrape(tb testing.TB, h http.Handler) (int, string) {
+	tb.Helper()
+
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, err := io.ReadAll(rec.Result().Body)
+	require.NoError(tb, err)
+
+	return rec.Code, string(body)
+}
+
+func TestHandler(t *testing.T) {
+	ObserveRequest(http.MethodGet, "/characters/{id}", http.StatusOK, 20*time.Millisecond)
+	ObserveRequest(http.MethodGet, "", http.StatusNotFound, time.Millisecond)
+	ObserveRequest("PROPFIND", "", http.StatusNotFound, time.Millisecond)
+	ObserveRequest("get", "", http.StatusNotFound, time.Millisecond)
+	CharacterImported(true)
+	CharacterImported(false)
+
+	r := dice.NewRoller(CountDice(fixedSource(0)))
+
+	assert.Equal(t, 1, r.Die(6))
+	assert.Equal(t, 1, r.Die(6))
+	assert.Equal(t, 1, r.Die(20))
+
+	code, body := scrape(t, Handler(func() (int, error) {
+		return 3, nil
+	}))
+
+	require.Equal(t, http.StatusOK, code)
+
+	for _, want := range []string{
+		`cthulhu_mythos_tools_http_requests_total{method="GET",route="/characters/{id}",status="200"} 1`,
+		`cthulhu_mythos_tools_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`cthulhu_mythos_tools_http_requests_total{method="other",route="unmatched",status="404"} 2`,
+		`cthulhu_mythos_tools_http_request_duration_seconds_count{method="GET",route="/characters/{id}",status="200"} 1`,
+		`cthulhu_mythos_tools_character_imports_total{result="success"} 1`,
+		`cthulhu_mythos_tools_character_imports_total{result="failure"} 1`,
+		`cthulhu_mythos_tools_dice_rolled_total{sides="6"} 2`,
+		`cthulhu_mythos_tools_dice_rolled_total{sides="20"} 1`,
+		`cthulhu_mythos_tools_characters 3`,
+	} {
+		assert.Contains(t, body, want)
+	}
+
+	t.Run("storage error", func(t *testing.T) {
+		code, _ := scrape(t, Handler(func() (int, error) {
+			return 0, errors.New("storage closed")
+		}))
+
+		assert.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+// fixedSource always returns the same value.
+type fixedSource int
+
+func (s fixedSource) IntN(n int) int {
+	return int(s) % n
+}
@@ -0,0 +1,70 @@
+package occupation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+)
+
+// ErrInvalidFormula is returned for malformed skill points formula.
+var ErrInvalidFormula = errors.New("invalid skill points formula")
+
+// EvalSkillPoints calculates skill points by the formula, e.g. "EDU×2+(DEX|STR)×2".
+// Alternatives in parentheses are separated by '|' and the highest characteristic is used.
+// Values are characteristics keyed by their abbreviations: STR, CON, SIZ, DEX, APP, INT, POW, EDU.
+func EvalSkillPoints(formula string, values map[string]int) (int, error) {
+	normalized := strings.NewReplacer(" ", "", "×", "*").Replace(formula)
+	if normalized == "" {
+		return 0, fmt.Errorf("%w: empty formula", ErrInvalidFormula)
+	}
+
+	var total int
+
+	for term := range strings.SplitSeq(normalized, "+") {
+		// Multiplication sign could be 'x' which is also a letter of DEX, so split at the last one.
+		idx := strings.LastIndexAny(term, "*xX")
+		if idx < 0 {
+			return 0, fmt.Errorf("%w: term %q has no multiplier", ErrInvalidFormula, term)
+		}
+
+		chars, mult := term[:idx], term[idx+1:]
+
+		m, err := strconv.Atoi(mult)
+		if err != nil || m <= 0 {
+			return 0, fmt.Errorf("%w: term %q has wrong multiplier", ErrInvalidFormula, term)
+		}
+
+		chars = strings.TrimSuffix(strings.TrimPrefix(chars, "("), ")")
+
+		best := -1
+
+		for name := range strings.SplitSeq(chars, "|") {
+			v, ok := values[strings.ToUpper(name)]
+			if !ok {
+				return 0, fmt.Errorf("%w: unknown characteristic %q", ErrInvalidFormula, name)
+			}
+
+			best = max(best, v)
+		}
+
+		total += best * m
+	}
+
+	return total, nil
+}
+
+// StatsValues returns characteristics of stats keyed for EvalSkillPoints.
+func StatsValues(s character.Stats) map[string]int {
+	return map[string]int{
+		"STR": s.STR.Full, "CON": s.CON.Full, "SIZ": s.SIZ.Full, "DEX": s.DEX.Full,
+		"APP": s.APP.Full, "INT": s.INT.Full, "POW": s.POW.Full, "EDU": s.EDU.Full,
+	}
+}
+
+// SkillPointsFor calculates occupation skill points for the characteristics.
+func (o Occupation) SkillPointsFor(s character.Stats) (int, error) {
+	return EvalSkillPoints(o.SkillPoints, StatsValues(s))
+}
@@ -0,0 +1,116 @@
+// Package occupation provides catalogue of Call of Cthulhu 7e occupations.
+package occupation
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+)
+
+// ErrNotFound is returned when occupation is not in the catalogue.
+var ErrNotFound = errors.New("occupation not found")
+
+// Range is an inclusive range of values.
+type Range struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// Contains reports whether v is in the range.
+func (r Range) Contains(v int) bool {
+	return v >= r.Min && v <= r.Max
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+// Choice is a slot of Count occupation skills picked by the player.
+// Skills are picked from the From list or, when it is empty, any skill could be picked.
+type Choice struct {
+	Count int      `json:"count"`
+	From  []string `json:"from,omitempty"`
+}
+
+// Any reports whether any skill could be picked.
+func (c Choice) Any() bool {
+	return len(c.From) == 0
+}
+
+// Occupation describes investigator occupation.
+type Occupation struct {
+	Name string `json:"name"`
+	// SkillPoints is a formula of occupation skill points, e.g. "EDU×2+(DEX|STR)×2".
+	SkillPoints  string          `json:"skill_points"`
+	CreditRating Range           `json:"credit_rating"`
+	Skills       []string        `json:"skills"`
+	Choices      []Choice        `json:"choices,omitempty"`
+	Eras         []character.Era `json:"eras"`
+}
+
+// AvailableIn reports whether occupation could be taken in the era.
+func (o Occupation) AvailableIn(era character.Era) bool {
+	return slices.Contains(o.Eras, era)
+}
+
+// SkillsCount returns number of occupation skills besides Credit Rating.
+func (o Occupation) SkillsCount() int {
+	n := len(o.Skills)
+
+	for _, c := range o.Choices {
+		n += c.Count
+	}
+
+	return n
+}
+
+//go:embed occupations.json
+var occupationsJSON []byte
+
+var catalogue = mustLoad(occupationsJSON)
+
+func mustLoad(data []byte) []Occupation {
+	var list []Occupation
+
+	if err := json.Unmarshal(data, &list); err != nil {
+		panic(fmt.Errorf("load occupations catalogue: %w", err))
+	}
+
+	return list
+}
+
+// All returns all occupations from the catalogue sorted by name.
+func All() []Occupation {
+	return slices.Clone(catalogue)
+}
+
+// ForEra returns occupations available in the era.
+func ForEra(era character.Era) []Occupation {
+	var res []Occupation
+
+	for _, o := range catalogue {
+		if o.AvailableIn(era) {
+			res = append(res, o)
+		}
+	}
+
+	return res
+}
+
+// Find looks up occupation by name, case-insensitively.
+func Find(name string) (Occupation, error) {
+	name = strings.TrimSpace(name)
+
+	for _, o := range catalogue {
+		if strings.EqualFold(o.Name, name) {
+			return o, nil
+		}
+	}
+
+	return Occupation{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+}
@@ -0,0 +1,141 @@
+package occupation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+)
+
+func TestCatalogue(t *testing.T) {
+	values := map[string]int{"STR": 50, "CON": 50, "SIZ": 50, "DEX": 50, "APP": 50, "INT": 50, "POW": 50, "EDU": 50}
+
+	all := All()
+	require.NotEmpty(t, all)
+
+	for _, o := range all {
+		t.Run(o.Name, func(t *testing.T) {
+			assert.Equal(t, 8, o.SkillsCount())
+			assert.NotEmpty(t, o.Eras)
+			assert.LessOrEqual(t, o.CreditRating.Min, o.CreditRating.Max)
+
+			points, err := EvalSkillPoints(o.SkillPoints, values)
+			require.NoError(t, err)
+			assert.Equal(t, 200, points)
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	o, err := Find("private investigator")
+	require.NoError(t, err)
+	assert.Equal(t, "Private Investigator", o.Name)
+
+	_, err = Find("Shoggoth Tamer")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestForEra(t *testing.T) {
+	names := func(list []Occupation) []string {
+		res := make([]string, 0, len(list))
+		for _, o := range list {
+			res = append(res, o.Name)
+		}
+
+		return res
+	}
+
+	assert.NotContains(t, names(ForEra(character.EraFor(character.GameTypeClassic))), "Hacker")
+	assert.Contains(t, names(ForEra(character.EraFor(character.GameTypeModern))), "Hacker")
+}
+
+func TestEvalSkillPoints(t *testing.T) {
+	values := map[string]int{"STR": 50, "DEX": 60, "APP": 40, "EDU": 70}
+
+	tests := []struct {
+		formula string
+		want    int
+		wantErr bool
+	}{
+		{formula: "EDU×4", want: 280},
+		{formula: "EDU x 2 + DEX x 2", want: 260},
+		{formula: "EDU*2+(STR|DEX|APP)*2", want: 260},
+		{formula: "EDU", wantErr: true},
+		{formula: "EDU×0", wantErr: true},
+		{formula: "LUCK×4", wantErr: true},
+		{formula: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.formula, func(t *testing.T) {
+			got, err := EvalSkillPoints(tt.formula, values)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidFormula)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOccupation_Unassigned(t *testing.T) {
+	o, err := Find("Police Officer")
+	require.NoError(t, err)
+
+	assert.Empty(t, o.Unassigned([]string{"Credit Rating", "Fighting (Brawl)", "Firearms (Handgun)", "Law", "Drive Auto", "Charm"}))
+	assert.Equal(t, []string{"Ride"}, o.Unassigned([]string{"Drive Auto", "Ride"}))
+	assert.Equal(t, []string{"Occult"}, o.Unassigned([]string{"Occult"}))
+
+	// Specialisation could be chosen later, but only once.
+	pi, err := Find("Private Investigator")
+	require.NoError(t, err)
+
+	assert.Empty(t, pi.Unassigned([]string{"Art/Craft", "Persuade", "Stealth"}))
+	assert.Equal(t, []string{"Swim"}, pi.Unassigned([]string{"Persuade", "Stealth", "Swim"}))
+	require.ErrorIs(t, pi.ValidateSkills([]string{"Persuade", "Stealth", "Swim"}), ErrNotOccupationSkill)
+}
+
+func TestOccupation_Mismatches(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "character", "testdata", "character.json"))
+	require.NoError(t, err)
+
+	inv, err := character.UnmarshalInvestigator(data)
+	require.NoError(t, err)
+
+	ic := inv.Investigator
+
+	o, err := Find(ic.PersonalDetails.Occupation)
+	require.NoError(t, err)
+
+	got, err := o.Mismatches(ic)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	occ := "true"
+
+	for i, s := range ic.Skills.Skill {
+		switch s.Name {
+		case "Accounting":
+			ic.Skills.Skill[i].Occupation = &occ
+		case CreditRating:
+			ic.Skills.Skill[i].Value = "50"
+		}
+	}
+
+	ic.Characteristics.OccupationSkillPoints = "300"
+
+	got, err = o.Mismatches(ic)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, character.Mismatch{Field: "Characteristics.OccupationSkillPoints", Stored: "300", Expected: "250"}, got[0])
+	assert.Equal(t, character.Mismatch{Field: "Skills.Credit Rating", Stored: "50", Expected: "9-30"}, got[1])
+	// Accounting and Stealth compete for the single "any skill" slot, so either is reported.
+	assert.Contains(t, []string{"Skills.Accounting.occupation", "Skills.Stealth.occupation"}, got[2].Field)
+}
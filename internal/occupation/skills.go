@@ -0,0 +1,190 @@
+package occupation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+)
+
+// CreditRating skill is occupation skill of every occupation.
+const CreditRating = "Credit Rating"
+
+// ErrNotOccupationSkill is returned when skill is not allowed by the occupation.
+var ErrNotOccupationSkill = errors.New("not an occupation skill")
+
+// MatchSkill reports whether skill full name matches catalogue skill name.
+// Skill family matches any specialisation ("Firearms" matches "Firearms (Handgun)")
+// and skill without specialisation could take the required one ("Art/Craft" matches "Art/Craft (Photography)").
+func MatchSkill(pattern, skill string) bool {
+	pattern, skill = strings.ToLower(pattern), strings.ToLower(skill)
+
+	return pattern == skill ||
+		strings.HasPrefix(skill, pattern+" (") ||
+		strings.HasPrefix(pattern, skill+" (")
+}
+
+// slot is a place for a single occupation skill.
+type slot struct {
+	// from is a list of allowed skills, empty means any skill.
+	from []string
+}
+
+func (s slot) allows(skill string) bool {
+	if len(s.from) == 0 {
+		return true
+	}
+
+	for _, p := range s.from {
+		if MatchSkill(p, skill) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o Occupation) slots() []slot {
+	res := make([]slot, 0, o.SkillsCount())
+
+	// Fixed skills go first so they are preferred by the matching.
+	for _, s := range o.Skills {
+		res = append(res, slot{from: []string{s}})
+	}
+
+	for _, c := range o.Choices {
+		if c.Any() {
+			continue
+		}
+
+		for range c.Count {
+			res = append(res, slot{from: c.From})
+		}
+	}
+
+	for _, c := range o.Choices {
+		if !c.Any() {
+			continue
+		}
+
+		for range c.Count {
+			res = append(res, slot{})
+		}
+	}
+
+	return res
+}
+
+// Unassigned returns skills (by full names) that could not be occupation skills of the occupation,
+// either because occupation does not allow them or because there are more skills than slots.
+// Credit Rating is always allowed.
+func (o Occupation) Unassigned(skills []string) []string {
+	slots := o.slots()
+	owner := make([]int, len(slots))
+
+	for i := range owner {
+		owner[i] = -1
+	}
+
+	// Kuhn's algorithm of maximum bipartite matching between skills and slots.
+	var try func(skill int, seen []bool) bool
+
+	try = func(skill int, seen []bool) bool {
+		for i, s := range slots {
+			if seen[i] || !s.allows(skills[skill]) {
+				continue
+			}
+
+			seen[i] = true
+
+			if owner[i] < 0 || try(owner[i], seen) {
+				owner[i] = skill
+
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var res []string
+
+	for i, s := range skills {
+		if strings.EqualFold(s, CreditRating) {
+			continue
+		}
+
+		if !try(i, make([]bool, len(slots))) {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
+
+// ValidateSkills checks that skills could be occupation skills of the occupation.
+func (o Occupation) ValidateSkills(skills []string) error {
+	var errs error
+
+	for _, s := range o.Unassigned(skills) {
+		errs = errors.Join(errs, fmt.Errorf("%w: %s for %s", ErrNotOccupationSkill, s, o.Name))
+	}
+
+	return errs
+}
+
+// Mismatches compares investigator sheet with the occupation: occupation skill points,
+// Credit Rating range and skills marked as occupation ones.
+func (o Occupation) Mismatches(inv character.InvestigatorClass) ([]character.Mismatch, error) {
+	stats, err := inv.Characteristics.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []character.Mismatch
+
+	points, err := o.SkillPointsFor(stats)
+	if err != nil {
+		return nil, err
+	}
+
+	if stored, err := character.ParseNumber(inv.Characteristics.OccupationSkillPoints); err != nil || stored != points {
+		res = append(res, character.Mismatch{
+			Field:    "Characteristics.OccupationSkillPoints",
+			Stored:   inv.Characteristics.OccupationSkillPoints,
+			Expected: strconv.Itoa(points),
+		})
+	}
+
+	var flagged []string
+
+	for _, s := range inv.Skills.Skill {
+		if strings.EqualFold(s.Name, CreditRating) {
+			if v, err := character.ParseNumber(s.Value); err != nil || !o.CreditRating.Contains(v) {
+				res = append(res, character.Mismatch{
+					Field:    "Skills." + CreditRating,
+					Stored:   s.Value,
+					Expected: o.CreditRating.String(),
+				})
+			}
+
+			continue
+		}
+
+		if s.IsOccupation() {
+			flagged = append(flagged, s.FullName())
+		}
+	}
+
+	for _, s := range o.Unassigned(flagged) {
+		res = append(res, character.Mismatch{
+			Field:    "Skills." + s + ".occupation",
+			Stored:   "true",
+			Expected: "false",
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,47 @@
+// Package sanity implements Call of Cthulhu 7e Sanity rules: SAN rolls, insanity and bouts of madness.
+package sanity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+// ErrInvalidLoss is returned when Sanity loss expression could not be parsed.
+var ErrInvalidLoss = errors.New("invalid sanity loss")
+
+// Loss is a Sanity loss written as "success/failure", e.g. "1/1D6" or "0/1D4+1".
+type Loss struct {
+	// Success is lost when SAN roll succeeds.
+	Success string
+	// Failure is lost when SAN roll fails.
+	Failure string
+}
+
+// ParseLoss parses Sanity loss expression. Both parts should be valid dice expressions.
+func ParseLoss(s string) (Loss, error) {
+	success, failure, ok := strings.Cut(s, "/")
+	if !ok {
+		return Loss{}, fmt.Errorf("%w: %q should be in form success/failure", ErrInvalidLoss, s)
+	}
+
+	l := Loss{
+		Success: strings.TrimSpace(success),
+		Failure: strings.TrimSpace(failure),
+	}
+
+	for _, part := range []string{l.Success, l.Failure} {
+		if _, err := dice.Parse(part); err != nil {
+			return Loss{}, fmt.Errorf("%w: %q: %w", ErrInvalidLoss, s, err)
+		}
+	}
+
+	return l, nil
+}
+
+// String returns loss in "success/failure" form.
+func (l Loss) String() string {
+	return l.Success + "/" + l.Failure
+}
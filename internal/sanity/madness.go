@@ -0,0 +1,203 @@
+package sanity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+)
+
+// ErrUnknownBoutMode is returned for unsupported bout of madness mode.
+var ErrUnknownBoutMode = errors.New("unknown bout of madness mode")
+
+// BoutMode selects the bout of madness table.
+type BoutMode string
+
+const (
+	// BoutRealTime is played out round by round, when investigator is in company of others.
+	BoutRealTime BoutMode = "realtime"
+	// BoutSummary is summarised by the Keeper, when investigator is alone.
+	BoutSummary BoutMode = "summary"
+)
+
+// ParseBoutMode returns bout mode by its name, empty name is treated as real-time.
+func ParseBoutMode(s string) (BoutMode, error) {
+	switch m := BoutMode(strings.ToLower(strings.TrimSpace(s))); m {
+	case "", BoutRealTime:
+		return BoutRealTime, nil
+	case BoutSummary:
+		return BoutSummary, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownBoutMode, s)
+	}
+}
+
+const (
+	boutTableDie = 10
+	// Real-time bouts last 1D10 rounds, summary ones 1D10 hours.
+	boutDuration = "1D10"
+
+	boutPhobia = 9
+	boutMania  = 10
+)
+
+var realTimeBouts = [boutTableDie]string{
+	"Amnesia: the investigator has no memory of events since they were last in a place of safety",
+	"Psychosomatic disability: the investigator suffers psychosomatic blindness, deafness or loss of the use of a limb",
+	"Violence: the investigator explodes in a spree of uncontrolled violence against everyone around",
+	"Paranoia: the investigator suffers severe paranoia, everyone is out to get them",
+	"Significant person: the investigator mistakes someone nearby for a significant person from their backstory",
+	"Faint: the investigator faints",
+	"Flee in panic: the investigator is compelled to get as far away as possible by any means",
+	"Physical hysterics or emotional outburst: the investigator is incapacitated by laughing, crying or screaming",
+	"Phobia: the investigator gains a new phobia",
+	"Mania: the investigator gains a new mania",
+}
+
+var summaryBouts = [boutTableDie]string{
+	"Amnesia: the investigator comes to their senses in an unfamiliar place with no memory of who they are",
+	"Robbed: the investigator comes to their senses hours later, robbed of items of value",
+	"Battered: the investigator comes to their senses battered and bruised, hit points reduced to half",
+	"Violence: the investigator explodes in a spree of violence and destruction",
+	"Ideology/Beliefs: the investigator takes an extreme and crazed action based on their ideology",
+	"Significant people: the investigator goes to great lengths to get close to a significant person from their backstory",
+	"Institutionalised: the investigator wakes up in a psychiatric ward or a police cell",
+	"Flee in panic: the investigator comes to their senses far away, lost in the wilderness or on a train",
+	"Phobia: the investigator gains a new phobia",
+	"Mania: the investigator gains a new mania",
+}
+
+// phobias is a selection from the Keeper Rulebook phobias table.
+var phobias = []string{
+	"Acrophobia: fear of heights",
+	"Agoraphobia: fear of open, public places",
+	"Ailurophobia: fear of cats",
+	"Arachnophobia: fear of spiders",
+	"Astraphobia: fear of lightning",
+	"Bacteriophobia: fear of bacteria",
+	"Claustrophobia: fear of confined spaces",
+	"Cynophobia: fear of dogs",
+	"Demonophobia: fear of spirits or demons",
+	"Entomophobia: fear of insects",
+	"Hemophobia: fear of blood",
+	"Hydrophobia: fear of water",
+	"Ichthyophobia: fear of fish",
+	"Necrophobia: fear of dead things",
+	"Nyctophobia: fear of the dark or of night-time",
+	"Ophidiophobia: fear of snakes",
+	"Scotophobia: fear of darkness",
+	"Selenophobia: fear of the moon",
+	"Thalassophobia: fear of the sea",
+	"Xenophobia: fear of strangers or foreigners",
+}
+
+// manias is a selection from the Keeper Rulebook manias table.
+var manias = []string{
+	"Ablutomania: compulsion for washing oneself",
+	"Agromania: compulsion to be in open spaces",
+	"Arithmomania: obsessive preoccupation with numbers",
+	"Bibliomania: obsession with books",
+	"Cleptomania: compulsion for theft",
+	"Demonomania: pathological belief that one is possessed",
+	"Dromomania: compulsion for travel",
+	"Egomania: irrational self-centred preoccupation or self-worship",
+	"Graphomania: obsession with writing everything down",
+	"Hedonomania: obsessive drive towards pleasure",
+	"Hippomania: obsession with horses",
+	"Mythomania: lying or exaggerating to an abnormal extent",
+	"Necromania: obsession with death or dead things",
+	"Nosomania: delusion of suffering from an imagined disease",
+	"Oniomania: compulsion to buy things",
+	"Pyromania: compulsion to start fires",
+	"Sitomania: obsession with food",
+	"Thalassomania: obsession with the sea",
+	"Theomania: conviction that one is a god",
+	"Xenomania: obsession with foreign things",
+}
+
+// Bout is a rolled bout of madness.
+type Bout struct {
+	Mode        BoutMode
+	Roll        int
+	Description string
+	// Duration is in rounds for real-time bouts and in hours for summary ones.
+	Duration dice.Result
+	// Phobia or Mania is set when the bout gave investigator a new one.
+	Phobia string
+	Mania  string
+}
+
+// String returns human-readable bout description.
+func (b Bout) String() string {
+	unit := "rounds"
+	if b.Mode == BoutSummary {
+		unit = "hours"
+	}
+
+	s := fmt.Sprintf("%s (%d %s)", b.Description, b.Duration.Total, unit)
+
+	switch {
+	case b.Phobia != "":
+		s += ": " + b.Phobia
+	case b.Mania != "":
+		s += ": " + b.Mania
+	}
+
+	return s
+}
+
+// RollBout rolls bout of madness on the table of given mode.
+func RollBout(r *dice.Roller, mode BoutMode) (Bout, error) {
+	table := realTimeBouts
+	if mode == BoutSummary {
+		table = summaryBouts
+	}
+
+	duration, err := r.Roll(boutDuration)
+	if err != nil {
+		return Bout{}, err
+	}
+
+	b := Bout{
+		Mode:     mode,
+		Roll:     r.Die(boutTableDie),
+		Duration: duration,
+	}
+
+	b.Description = table[b.Roll-1]
+
+	switch b.Roll {
+	case boutPhobia:
+		b.Phobia = phobias[r.Die(len(phobias))-1]
+	case boutMania:
+		b.Mania = manias[r.Die(len(manias))-1]
+	}
+
+	return b, nil
+}
+
+// recordBout appends phobia or mania gained in the bout to investigator backstory.
+func recordBout(inv *character.InvestigatorClass, b Bout) {
+	var entry string
+
+	switch {
+	case b.Phobia != "":
+		entry = "Phobia: " + b.Phobia
+	case b.Mania != "":
+		entry = "Mania: " + b.Mania
+	default:
+		return
+	}
+
+	bs := &inv.Backstory
+
+	if strings.TrimSpace(bs.Phobias) == "" {
+		bs.Phobias = entry
+
+		return
+	}
+
+	bs.Phobias += "; " + entry
+}
@@ -0,0 +1,256 @@
+package sanity
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/skills"
+)
+
+// ErrInvalidMythos is returned when Cthulhu Mythos could not be raised by given points.
+var ErrInvalidMythos = errors.New("invalid cthulhu mythos gain")
+
+const (
+	// MaxSanity is the Sanity limit of investigator without Cthulhu Mythos knowledge.
+	MaxSanity = 99
+	// TemporaryThreshold is a loss in a single roll that calls for an INT roll against temporary insanity.
+	TemporaryThreshold = 5
+	// indefiniteFraction of the Sanity at the start of the day lost within the day causes indefinite insanity.
+	indefiniteFraction = 5
+)
+
+// State tracks investigator Sanity between SAN rolls.
+type State struct {
+	// DayStart is Sanity at the start of the current game day.
+	DayStart int `json:"dayStart"`
+	// DayLoss is Sanity lost since the start of the current game day.
+	DayLoss    int  `json:"dayLoss"`
+	Temporary  bool `json:"temporary,omitempty"`
+	Indefinite bool `json:"indefinite,omitempty"`
+	Permanent  bool `json:"permanent,omitempty"`
+}
+
+// Insane reports whether investigator suffers any kind of insanity.
+func (s State) Insane() bool {
+	return s.Temporary || s.Indefinite || s.Permanent
+}
+
+// StartDay starts a new game day with the current Sanity. Temporary insanity lasts for hours and ends with the day.
+func (s *State) StartDay(sanity int) {
+	s.DayStart = sanity
+	s.DayLoss = 0
+	s.Temporary = false
+}
+
+// Recover ends temporary and indefinite insanity, e.g. after treatment. Permanent insanity never ends.
+func (s *State) Recover() {
+	s.Temporary = false
+	s.Indefinite = false
+}
+
+// Outcome is a result of the SAN roll.
+type Outcome struct {
+	Loss  Loss
+	Check dice.CheckResult
+	// LossRoll is the rolled loss, on fumble it is the maximum of failure loss.
+	LossRoll dice.Result
+	From     int
+	To       int
+	// INTCheck is rolled when investigator lost TemporaryThreshold or more Sanity in one roll.
+	INTCheck *dice.CheckResult
+	// Temporary, Indefinite and Permanent are set when the roll caused such insanity.
+	Temporary  bool
+	Indefinite bool
+	Permanent  bool
+	// Bout is rolled when investigator went insane or lost Sanity while already insane.
+	Bout *Bout
+}
+
+// Lost returns amount of Sanity lost.
+func (o Outcome) Lost() int {
+	return o.From - o.To
+}
+
+// Roll makes the SAN roll against current investigator Sanity and applies the loss.
+// Loss of TemporaryThreshold or more calls for an INT roll: success means investigator fully
+// understands what they saw and goes temporarily insane. Losing a fifth of Sanity within a game day
+// causes indefinite insanity and reaching zero - permanent one. Phobias and manias gained in the bout
+// of madness are recorded in investigator backstory.
+func Roll(r *dice.Roller, inv *character.InvestigatorClass, st *State, loss Loss, mode BoutMode) (Outcome, error) {
+	c := &inv.Characteristics
+
+	sanity, err := character.ParseNumber(c.Sanity)
+	if err != nil {
+		return Outcome{}, fmt.Errorf("sanity: %w", err)
+	}
+
+	if st.DayStart == 0 {
+		st.DayStart = sanity
+	}
+
+	check, err := r.Check(sanity, 0, 0)
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	out := Outcome{
+		Loss:  loss,
+		Check: check,
+		From:  sanity,
+	}
+
+	switch {
+	case check.Level == dice.Fumble:
+		out.LossRoll, err = dice.Max(loss.Failure)
+	case check.Level.IsSuccess():
+		out.LossRoll, err = r.Roll(loss.Success)
+	default:
+		out.LossRoll, err = r.Roll(loss.Failure)
+	}
+
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	out.To = max(sanity-max(out.LossRoll.Total, 0), 0)
+	c.Sanity = strconv.Itoa(out.To)
+
+	lost := out.Lost()
+	if lost == 0 {
+		return out, nil
+	}
+
+	wasInsane := st.Insane()
+	st.DayLoss += lost
+
+	if out.To == 0 {
+		out.Permanent = true
+		st.Permanent = true
+
+		return out, nil
+	}
+
+	if lost >= TemporaryThreshold {
+		if err = rollTemporary(r, inv, &out); err != nil {
+			return Outcome{}, err
+		}
+
+		st.Temporary = st.Temporary || out.Temporary
+	}
+
+	if !st.Indefinite && st.DayLoss*indefiniteFraction >= st.DayStart {
+		out.Indefinite = true
+		st.Indefinite = true
+	}
+
+	if !wasInsane && !out.Temporary && !out.Indefinite {
+		return out, nil
+	}
+
+	bout, err := RollBout(r, mode)
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	recordBout(inv, bout)
+
+	out.Bout = &bout
+
+	return out, nil
+}
+
+// String returns human-readable roll result, e.g. "SAN 1/1D6: rolled 80 (Failure), Sanity 40 → 34".
+func (o Outcome) String() string {
+	s := fmt.Sprintf("SAN %s: rolled %d (%s), Sanity %d → %d", o.Loss, o.Check.Roll, o.Check.Level, o.From, o.To)
+
+	if o.INTCheck != nil {
+		s += fmt.Sprintf(", INT rolled %d (%s)", o.INTCheck.Roll, o.INTCheck.Level)
+	}
+
+	switch {
+	case o.Permanent:
+		s += ", permanent insanity"
+	case o.Indefinite:
+		s += ", indefinite insanity"
+	case o.Temporary:
+		s += ", temporary insanity"
+	}
+
+	return s
+}
+
+func rollTemporary(r *dice.Roller, inv *character.InvestigatorClass, out *Outcome) error {
+	intValue, err := character.ParseNumber(inv.Characteristics.Int)
+	if err != nil {
+		return fmt.Errorf("INT: %w", err)
+	}
+
+	check, err := r.Check(intValue, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	out.INTCheck = &check
+	out.Temporary = check.Level.IsSuccess()
+
+	return nil
+}
+
+// MythosChange is a result of gaining Cthulhu Mythos knowledge.
+type MythosChange struct {
+	MythosFrom    int
+	MythosTo      int
+	SanityMaxFrom int
+	SanityMaxTo   int
+	SanityFrom    int
+	SanityTo      int
+}
+
+// String returns human-readable change, e.g. "Cthulhu Mythos 0% → 5%, maximum Sanity 99 → 94".
+func (c MythosChange) String() string {
+	return fmt.Sprintf("%s %d%% → %d%%, maximum Sanity %d → %d",
+		skills.CthulhuMythos, c.MythosFrom, c.MythosTo, c.SanityMaxFrom, c.SanityMaxTo)
+}
+
+// GainMythos raises investigator Cthulhu Mythos skill by points and lowers maximum Sanity
+// to 99 minus Cthulhu Mythos. Current Sanity is capped with the new maximum.
+func GainMythos(inv *character.InvestigatorClass, points int) (MythosChange, error) {
+	if points <= 0 {
+		return MythosChange{}, fmt.Errorf("%w: %d points", ErrInvalidMythos, points)
+	}
+
+	var (
+		ch  MythosChange
+		err error
+	)
+
+	if s, ok := inv.FindSkill(skills.CthulhuMythos); ok {
+		if ch.MythosFrom, err = character.ParseNumber(s.Value); err != nil {
+			return MythosChange{}, fmt.Errorf("%s: %w", skills.CthulhuMythos, err)
+		}
+	}
+
+	c := &inv.Characteristics
+
+	if ch.SanityMaxFrom, err = character.ParseNumber(c.SanityMax); err != nil {
+		return MythosChange{}, fmt.Errorf("sanity max: %w", err)
+	}
+
+	if ch.SanityFrom, err = character.ParseNumber(c.Sanity); err != nil {
+		return MythosChange{}, fmt.Errorf("sanity: %w", err)
+	}
+
+	ch.MythosTo = min(ch.MythosFrom+points, MaxSanity)
+	ch.SanityMaxTo = MaxSanity - ch.MythosTo
+	ch.SanityTo = min(ch.SanityFrom, ch.SanityMaxTo)
+
+	inv.SetSkillValue(skills.CthulhuMythos, ch.MythosTo)
+
+	c.SanityMax = strconv.Itoa(ch.SanityMaxTo)
+	c.Sanity = strconv.Itoa(ch.SanityTo)
+
+	return ch, nil
+}
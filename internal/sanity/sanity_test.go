@@ -0,0 +1,248 @@
+package sanity
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character/charactertest"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice/dicetest"
+)
+
+func TestParseLoss(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Loss
+		wantErr bool
+	}{
+		{in: "1/1D6", want: Loss{Success: "1", Failure: "1D6"}},
+		{in: " 0 / 1D4+1 ", want: Loss{Success: "0", Failure: "1D4+1"}},
+		{in: "1D3/1D10", want: Loss{Success: "1D3", Failure: "1D10"}},
+		{in: "1D6", wantErr: true},
+		{in: "1/", wantErr: true},
+		{in: "1/foo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLoss(tt.in)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidLoss)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseBoutMode(t *testing.T) {
+	got, err := ParseBoutMode("")
+	require.NoError(t, err)
+	assert.Equal(t, BoutRealTime, got)
+
+	got, err = ParseBoutMode("Summary")
+	require.NoError(t, err)
+	assert.Equal(t, BoutSummary, got)
+
+	_, err = ParseBoutMode("forever")
+	require.ErrorIs(t, err, ErrUnknownBoutMode)
+}
+
+func TestRoll(t *testing.T) {
+	tests := []struct {
+		name string
+		// faces are in roll order: SAN units and tens, loss dice, INT units and tens, bout duration and table, phobia.
+		faces   []int
+		loss    string
+		sanity  string
+		state   State
+		mode    BoutMode
+		want    Outcome
+		wantSt  State
+		phobias string
+	}{
+		{
+			name:   "success",
+			faces:  []int{1, 2},
+			loss:   "1/1D6",
+			want:   Outcome{From: 40, To: 39},
+			wantSt: State{DayStart: 40, DayLoss: 1},
+		},
+		{
+			name:   "failure without loss",
+			faces:  []int{1, 9},
+			loss:   "0/0",
+			want:   Outcome{From: 40, To: 40},
+			wantSt: State{DayStart: 40},
+		},
+		{
+			name:  "temporary insanity with phobia",
+			faces: []int{1, 9, 6, 1, 3, 3, 9, 4},
+			loss:  "1/1D6",
+			mode:  BoutRealTime,
+			want: Outcome{
+				From: 40, To: 34, Temporary: true,
+				Bout: &Bout{
+					Mode:        BoutRealTime,
+					Roll:        9,
+					Description: realTimeBouts[8],
+					Phobia:      "Arachnophobia: fear of spiders",
+				},
+			},
+			wantSt:  State{DayStart: 40, DayLoss: 6, Temporary: true},
+			phobias: "Боязнь высоты; Phobia: Arachnophobia: fear of spiders",
+		},
+		{
+			name:   "repressed memory on failed INT roll",
+			faces:  []int{1, 9, 5, 1, 10},
+			loss:   "1/1D6",
+			want:   Outcome{From: 40, To: 35},
+			wantSt: State{DayStart: 40, DayLoss: 5},
+		},
+		{
+			name:  "indefinite insanity on fifth lost within a day",
+			faces: []int{1, 9, 4, 2, 10},
+			loss:  "0/1D4",
+			state: State{DayStart: 40, DayLoss: 4},
+			mode:  BoutSummary,
+			want: Outcome{
+				From: 40, To: 36, Indefinite: true,
+				Bout: &Bout{
+					Mode:        BoutSummary,
+					Roll:        10,
+					Description: summaryBouts[9],
+					Mania:       "Ablutomania: compulsion for washing oneself",
+				},
+			},
+			wantSt:  State{DayStart: 40, DayLoss: 8, Indefinite: true},
+			phobias: "Боязнь высоты; Mania: Ablutomania: compulsion for washing oneself",
+		},
+		{
+			name:   "fumble loses maximum",
+			faces:  []int{1, 1},
+			loss:   "0/1D3",
+			want:   Outcome{From: 40, To: 37},
+			wantSt: State{DayStart: 40, DayLoss: 3},
+		},
+		{
+			name:   "permanent insanity at zero",
+			faces:  []int{1, 9, 6},
+			loss:   "1/1D6",
+			sanity: "3",
+			want:   Outcome{From: 3, To: 0, Permanent: true},
+			wantSt: State{DayStart: 3, DayLoss: 3, Permanent: true},
+		},
+		{
+			name:   "any loss while insane causes a bout",
+			faces:  []int{1, 2, 5, 6},
+			loss:   "1/1D6",
+			state:  State{DayStart: 80, Indefinite: true},
+			want:   Outcome{From: 40, To: 39, Bout: &Bout{Mode: BoutRealTime, Roll: 6, Description: realTimeBouts[5]}},
+			wantSt: State{DayStart: 80, DayLoss: 1, Indefinite: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := charactertest.LoadInvestigator(t)
+			if tt.sanity != "" {
+				inv.Characteristics.Sanity = tt.sanity
+			}
+
+			loss, err := ParseLoss(tt.loss)
+			require.NoError(t, err)
+
+			mode := tt.mode
+			if mode == "" {
+				mode = BoutRealTime
+			}
+
+			st := tt.state
+
+			got, err := Roll(dicetest.NewRoller(tt.faces...), &inv, &st, loss, mode)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want.From, got.From)
+			assert.Equal(t, tt.want.To, got.To)
+			assert.Equal(t, tt.want.Temporary, got.Temporary)
+			assert.Equal(t, tt.want.Indefinite, got.Indefinite)
+			assert.Equal(t, tt.want.Permanent, got.Permanent)
+			assert.Equal(t, tt.wantSt, st)
+			assert.Equal(t, strconv.Itoa(tt.want.To), inv.Characteristics.Sanity)
+
+			if tt.want.Bout == nil {
+				assert.Nil(t, got.Bout)
+			} else {
+				require.NotNil(t, got.Bout)
+				assert.Equal(t, tt.want.Bout.Mode, got.Bout.Mode)
+				assert.Equal(t, tt.want.Bout.Roll, got.Bout.Roll)
+				assert.Equal(t, tt.want.Bout.Description, got.Bout.Description)
+				assert.Equal(t, tt.want.Bout.Phobia, got.Bout.Phobia)
+				assert.Equal(t, tt.want.Bout.Mania, got.Bout.Mania)
+			}
+
+			wantPhobias := tt.phobias
+			if wantPhobias == "" {
+				wantPhobias = "Боязнь высоты"
+			}
+
+			assert.Equal(t, wantPhobias, inv.Backstory.Phobias)
+		})
+	}
+}
+
+func TestState_StartDay(t *testing.T) {
+	st := State{DayStart: 50, DayLoss: 7, Temporary: true, Indefinite: true}
+
+	st.StartDay(43)
+	assert.Equal(t, State{DayStart: 43, Indefinite: true}, st)
+
+	st.Recover()
+	assert.False(t, st.Insane())
+}
+
+func TestGainMythos(t *testing.T) {
+	inv := charactertest.LoadInvestigator(t)
+
+	got, err := GainMythos(&inv, 5)
+	require.NoError(t, err)
+	assert.Equal(t, MythosChange{
+		MythosFrom: 0, MythosTo: 5,
+		SanityMaxFrom: 99, SanityMaxTo: 94,
+		SanityFrom: 40, SanityTo: 40,
+	}, got)
+
+	s, ok := inv.FindSkill("Cthulhu Mythos")
+	require.True(t, ok)
+	assert.Equal(t, "5", s.Value)
+	assert.Equal(t, "2", s.Half)
+	assert.Equal(t, "1", s.Fifth)
+	assert.Equal(t, "94", inv.Characteristics.SanityMax)
+
+	inv.Characteristics.Sanity = "93"
+
+	got, err = GainMythos(&inv, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 91, got.SanityMaxTo)
+	assert.Equal(t, 91, got.SanityTo)
+	assert.Equal(t, "91", inv.Characteristics.Sanity)
+
+	_, err = GainMythos(&inv, 0)
+	require.ErrorIs(t, err, ErrInvalidMythos)
+
+	var empty character.InvestigatorClass
+
+	_, err = GainMythos(&empty, 2)
+	require.NoError(t, err)
+
+	s, ok = empty.FindSkill("Cthulhu Mythos")
+	require.True(t, ok)
+	assert.Equal(t, "2", s.Value)
+	assert.Equal(t, "97", empty.Characteristics.SanityMax)
+}
@@ -0,0 +1,450 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/metrics"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/occupation"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/validation"
+)
+
+const (
+	apiPrefix      = "/api/v1"
+	maxAPIBodySize = 10 << 20
+)
+
+func apiRoutes(db storage.Storage) map[string]http.HandlerFunc {
+	routes := map[string]http.HandlerFunc{
+		makePathPattern(http.MethodGet, apiPrefix+"/characters"):                 apiListCharactersHandler(db),
+		makePathPattern(http.MethodPost, apiPrefix+"/characters"):                apiCreateCharacterHandler(db),
+		makePathPattern(http.MethodPost, apiPrefix+"/characters/import"):         apiImportCharacterHandler(db),
+		makePathPattern(http.MethodGet, apiPrefix+"/characters/{id}"):            apiGetCharacterHandler(db),
+		makePathPattern(http.MethodPut, apiPrefix+"/characters/{id}"):            apiUpdateCharacterHandler(db),
+		makePathPattern(http.MethodDelete, apiPrefix+"/characters/{id}"):         apiDeleteCharacterHandler(db),
+		makePathPattern(http.MethodGet, apiPrefix+"/characters/{id}/validation"): apiCharacterValidationHandler(db),
+		makePathPattern(http.MethodGet, apiPrefix+"/occupations"):                apiListOccupationsHandler(),
+		makePathPattern(http.MethodGet, apiPrefix+"/occupations/{name}"):         apiGetOccupationHandler(),
+	}
+
+	// Catch-all to not fall back to HTML pages for unknown API paths.
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		routes[makePathPattern(method, apiPrefix+"/")] = apiNotFoundHandler()
+	}
+
+	return routes
+}
+
+type apiError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+type apiErrorResponse struct {
+	Error apiError `json:"error"`
+}
+
+type apiCharacter struct {
+	ID           string                      `json:"id"`
+	Version      int                         `json:"version"`
+	Investigator character.InvestigatorClass `json:"investigator"`
+}
+
+type apiCharacterRequest struct {
+	// Version is required for updates unless If-Match header is set, nil when it is missing.
+	Version      *int                        `json:"version,omitempty"`
+	Investigator character.InvestigatorClass `json:"investigator"`
+}
+
+type apiCharactersList struct {
+	Characters []apiCharacter `json:"characters"`
+}
+
+type apiValidation struct {
+	ID       string            `json:"id"`
+	Valid    bool              `json:"valid"`
+	Errors   int               `json:"errors"`
+	Warnings int               `json:"warnings"`
+	Findings validation.Report `json:"findings"`
+}
+
+// apiImportedCharacter is an imported character with the validation report of its sheet.
+type apiImportedCharacter struct {
+	apiCharacter
+	Validation apiValidation `json:"validation"`
+}
+
+type apiOccupationsList struct {
+	Occupations []occupation.Occupation `json:"occupations"`
+}
+
+func newAPICharacter(ch storage.Character) apiCharacter {
+	return apiCharacter{
+		ID:           ch.ID,
+		Version:      ch.Version,
+		Investigator: ch.Investigator,
+	}
+}
+
+func apiNotFoundHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		apiErrorResponseWrite(w, r, http.StatusNotFound, "Not found")
+	}
+}
+
+func apiListCharactersHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := db.List()
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get characters list")
+
+			apiErrorResponseWrite(w, r, http.StatusInternalServerError, "Failed to get characters list")
+
+			return
+		}
+
+		list, err = charactersInCampaign(db, viewerFromContext(r.Context()).accessible(list), r.URL.Query().Get("campaign"))
+		if err != nil {
+			switch {
+			case errors.Is(err, errCampaignID):
+				apiErrorResponseWrite(w, r, http.StatusBadRequest, "Wrong campaign ID format")
+			case errors.Is(err, storage.ErrNotFound):
+				apiErrorResponseWrite(w, r, http.StatusNotFound, "Campaign not found")
+			default:
+				logger.WithError(r.Context(), err).Error("Failed to get campaign")
+
+				apiErrorResponseWrite(w, r, http.StatusInternalServerError, "Failed to get campaign")
+			}
+
+			return
+		}
+
+		resp := apiCharactersList{
+			Characters: make([]apiCharacter, 0, len(list)),
+		}
+
+		for _, ch := range list {
+			resp.Characters = append(resp.Characters, newAPICharacter(ch))
+		}
+
+		apiResponse(w, r, http.StatusOK, resp)
+	}
+}
+
+func apiGetCharacterHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := apiCharacterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		w.Header().Set("ETag", characterETag(ch.Version))
+
+		apiResponse(w, r, http.StatusOK, newAPICharacter(ch))
+	}
+}
+
+func apiCreateCharacterHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req apiCharacterRequest
+
+		if !apiDecodeBody(w, r, &req) {
+			return
+		}
+
+		if req.Investigator.PersonalDetails.Name == "" {
+			apiErrorResponseWrite(w, r, http.StatusBadRequest, "Investigator name is required")
+
+			return
+		}
+
+		ch := storage.Character{
+			ID:           uuid.New().String(),
+			Version:      storage.InitialVersion,
+			Investigator: req.Investigator,
+			Owner:        viewerFromContext(r.Context()).ownerID(),
+		}
+
+		if err := db.Create(ch); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to save character to storage")
+
+			apiErrorResponseWrite(w, r, http.StatusInternalServerError, "Failed to save character to storage")
+
+			return
+		}
+
+		w.Header().Set("Location", apiPrefix+"/characters/"+ch.ID)
+
+		apiResponse(w, r, http.StatusCreated, newAPICharacter(ch))
+	}
+}
+
+func apiImportCharacterHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var imported bool
+
+		defer func() {
+			metrics.CharacterImported(imported)
+		}()
+
+		var investigator character.Investigator
+
+		if !apiDecodeBody(w, r, &investigator) {
+			return
+		}
+
+		inv, report := importSheet(r.Context(), investigator.Investigator)
+
+		ch := storage.Character{
+			ID:           uuid.New().String(),
+			Version:      storage.InitialVersion,
+			Investigator: inv,
+			Owner:        viewerFromContext(r.Context()).ownerID(),
+		}
+
+		if err := db.Create(ch); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to save character to storage")
+
+			apiErrorResponseWrite(w, r, http.StatusInternalServerError, "Failed to save character to storage")
+
+			return
+		}
+
+		imported = true
+
+		w.Header().Set("Location", apiPrefix+"/characters/"+ch.ID)
+
+		apiResponse(w, r, http.StatusCreated, apiImportedCharacter{
+			apiCharacter: newAPICharacter(ch),
+			Validation:   newAPIValidation(ch.ID, report),
+		})
+	}
+}
+
+func apiUpdateCharacterHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := apiCharacterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		var req apiCharacterRequest
+
+		if !apiDecodeBody(w, r, &req) {
+			return
+		}
+
+		var bodyVersion string
+		if req.Version != nil {
+			bodyVersion = strconv.Itoa(*req.Version)
+		}
+
+		version, err := requestVersion(r, bodyVersion)
+		if err != nil {
+			if errors.Is(err, errNoVersion) {
+				apiErrorResponseWrite(w, r, http.StatusPreconditionRequired, "Character version is required")
+
+				return
+			}
+
+			apiErrorResponseWrite(w, r, http.StatusBadRequest, "Wrong character version")
+
+			return
+		}
+
+		if req.Investigator.PersonalDetails.Name == "" {
+			apiErrorResponseWrite(w, r, http.StatusBadRequest, "Investigator name is required")
+
+			return
+		}
+
+		ch.Version = version
+		ch.Investigator = req.Investigator
+
+		updated, err := db.Update(ch)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrConflict):
+				apiErrorResponseWrite(w, r, http.StatusPreconditionFailed, "Character was modified by someone else")
+			case errors.Is(err, storage.ErrNotFound):
+				apiErrorResponseWrite(w, r, http.StatusNotFound, "Character not found")
+			default:
+				logger.WithError(r.Context(), err).Error("Failed to update character")
+
+				apiErrorResponseWrite(w, r, http.StatusInternalServerError, "Failed to update character")
+			}
+
+			return
+		}
+
+		w.Header().Set("ETag", characterETag(updated.Version))
+
+		apiResponse(w, r, http.StatusOK, newAPICharacter(updated))
+	}
+}
+
+func apiDeleteCharacterHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if !isValidID(id) {
+			apiErrorResponseWrite(w, r, http.StatusBadRequest, "Wrong character ID format")
+
+			return
+		}
+
+		if err := deleteCharacter(db, id); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				apiErrorResponseWrite(w, r, http.StatusNotFound, "Character not found")
+
+				return
+			}
+
+			logger.WithError(r.Context(), err).Error("Failed to delete character")
+
+			apiErrorResponseWrite(w, r, http.StatusInternalServerError, "Failed to delete character")
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func apiCharacterValidationHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := apiCharacterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		apiResponse(w, r, http.StatusOK, newAPIValidation(ch.ID, validation.Validate(ch.Investigator)))
+	}
+}
+
+func newAPIValidation(id string, report validation.Report) apiValidation {
+	if report == nil {
+		report = validation.Report{}
+	}
+
+	return apiValidation{
+		ID:       id,
+		Valid:    !report.HasErrors(),
+		Errors:   report.Count(validation.SeverityError),
+		Warnings: report.Count(validation.SeverityWarning),
+		Findings: report,
+	}
+}
+
+// apiListOccupationsHandler returns occupations catalogue, optionally filtered by era query parameter.
+func apiListOccupationsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list := occupation.All()
+
+		switch era := character.Era(r.URL.Query().Get("era")); era {
+		case "":
+		case character.EraClassic, character.EraModern:
+			list = occupation.ForEra(era)
+		default:
+			apiErrorResponseWrite(w, r, http.StatusBadRequest, "Unknown era")
+
+			return
+		}
+
+		apiResponse(w, r, http.StatusOK, apiOccupationsList{Occupations: list})
+	}
+}
+
+func apiGetOccupationHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		o, err := occupation.Find(r.PathValue("name"))
+		if err != nil {
+			apiErrorResponseWrite(w, r, http.StatusNotFound, "Occupation not found")
+
+			return
+		}
+
+		apiResponse(w, r, http.StatusOK, o)
+	}
+}
+
+// apiCharacterFromPath loads character by {id} path value.
+// It writes JSON error response and returns false when character could not be loaded.
+func apiCharacterFromPath(w http.ResponseWriter, r *http.Request, db storage.Storage) (storage.Character, bool) {
+	id := r.PathValue("id")
+	if !isValidID(id) {
+		apiErrorResponseWrite(w, r, http.StatusBadRequest, "Wrong character ID format")
+
+		return storage.Character{}, false
+	}
+
+	ch, err := db.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			apiErrorResponseWrite(w, r, http.StatusNotFound, "Character not found")
+
+			return storage.Character{}, false
+		}
+
+		logger.WithError(r.Context(), err).Error("Failed to get character")
+
+		apiErrorResponseWrite(w, r, http.StatusInternalServerError, "Failed to get character")
+
+		return storage.Character{}, false
+	}
+
+	return ch, true
+}
+
+// apiDecodeBody decodes JSON request body into v.
+// It writes JSON error response and returns false when body could not be decoded.
+func apiDecodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	body := http.MaxBytesReader(w, r.Body, maxAPIBodySize)
+
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		logger.WithError(r.Context(), err).Error("Failed to decode request body")
+
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			apiErrorResponseWrite(w, r, http.StatusRequestEntityTooLarge, "Request body is too large")
+
+			return false
+		}
+
+		apiErrorResponseWrite(w, r, http.StatusBadRequest, "Failed to decode request body")
+
+		return false
+	}
+
+	return true
+}
+
+func apiErrorResponseWrite(w http.ResponseWriter, r *http.Request, status int, message string) {
+	logger.WithFields(r.Context(), logger.Fields{
+		"status":  status,
+		"message": message,
+	}).Error("API request failed")
+
+	apiResponse(w, r, status, apiErrorResponse{
+		Error: apiError{
+			Status:  status,
+			Message: message,
+		},
+	})
+}
+
+func apiResponse(w http.ResponseWriter, r *http.Request, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.WithError(r.Context(), err).Error("Failed to write API response")
+	}
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/campaign"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/testlogger"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/validation"
+)
+
+// newAPIRouter returns router without authentication and its storage.
+func newAPIRouter(t *testing.T) (http.Handler, storage.Storage) {
+	t.Helper()
+
+	db := storage.NewInMemoryStorage()
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	return NewRouter(db, AuthParams{}), db
+}
+
+// apiDo sends request with the body to the router, headers are pairs of name and value.
+func apiDo(t *testing.T, h http.Handler, method, target string, body io.Reader, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	if body == nil {
+		body = http.NoBody
+	}
+
+	req := httptest.NewRequestWithContext(testlogger.New(context.Background()), method, target, body)
+	req.Header.Set("Content-Type", "application/json")
+
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+// decodeAPI decodes JSON response body into v.
+func decodeAPI(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(v))
+}
+
+// assertAPIError checks status of the response and its error envelope.
+func assertAPIError(t *testing.T, rec *httptest.ResponseRecorder, status int, message string) {
+	t.Helper()
+
+	require.Equal(t, status, rec.Code)
+
+	var resp apiErrorResponse
+
+	decodeAPI(t, rec, &resp)
+
+	assert.Equal(t, apiErrorResponse{Error: apiError{Status: status, Message: message}}, resp)
+}
+
+func createAPICharacter(t *testing.T, h http.Handler, name string) apiCharacter {
+	t.Helper()
+
+	rec := apiDo(t, h, http.MethodPost, apiPrefix+"/characters",
+		strings.NewReader(`{"investigator":{"PersonalDetails":{"Name":"`+name+`"}}}`))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var ch apiCharacter
+
+	decodeAPI(t, rec, &ch)
+
+	assert.Equal(t, apiPrefix+"/characters/"+ch.ID, rec.Header().Get("Location"))
+
+	return ch
+}
+
+func TestAPI_Characters(t *testing.T) {
+	h, _ := newAPIRouter(t)
+
+	ch := createAPICharacter(t, h, "Harvey Walters")
+	assert.Equal(t, storage.InitialVersion, ch.Version)
+	assert.Equal(t, "Harvey Walters", ch.Investigator.PersonalDetails.Name)
+
+	createAPICharacter(t, h, "Roger Carlyle")
+
+	rec := apiDo(t, h, http.MethodGet, apiPrefix+"/characters/"+ch.ID, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var got apiCharacter
+
+	decodeAPI(t, rec, &got)
+	assert.Equal(t, ch, got)
+
+	rec = apiDo(t, h, http.MethodGet, apiPrefix+"/characters", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var list apiCharactersList
+
+	decodeAPI(t, rec, &list)
+	assert.Len(t, list.Characters, 2)
+
+	rec = apiDo(t, h, http.MethodDelete, apiPrefix+"/characters/"+ch.ID, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	assertAPIError(t, apiDo(t, h, http.MethodGet, apiPrefix+"/characters/"+ch.ID, nil),
+		http.StatusNotFound, "Character not found")
+	assertAPIError(t, apiDo(t, h, http.MethodDelete, apiPrefix+"/characters/"+ch.ID, nil),
+		http.StatusNotFound, "Character not found")
+}
+
+func TestDeleteCharacter_LeavesCampaigns(t *testing.T) {
+	for _, prefix := range []string{"", apiPrefix} {
+		t.Run("route "+prefix+"/characters", func(t *testing.T) {
+			h, db := newAPIRouter(t)
+
+			ch := createAPICharacter(t, h, "Harvey Walters")
+			other := createAPICharacter(t, h, "Roger Carlyle")
+
+			c, err := campaign.New(uuid.New().String(), "Masks of Nyarlathotep", character.EraClassic, "Anna", "", time.Now().UTC())
+			require.NoError(t, err)
+			require.NoError(t, c.Join(ch.ID, false))
+			require.NoError(t, c.Join(other.ID, true))
+			require.NoError(t, db.Campaigns().Create(c.ID, c))
+
+			rec := apiDo(t, h, http.MethodDelete, prefix+"/characters/"+ch.ID, nil)
+			require.Less(t, rec.Code, http.StatusMultipleChoices)
+
+			got, err := db.Campaigns().Get(c.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []campaign.Member{{CharacterID: other.ID, NPC: true}}, got.Members)
+		})
+	}
+}
+
+func TestAPI_Import(t *testing.T) {
+	h, _ := newAPIRouter(t)
+
+	f, err := os.Open(filepath.Join("..", "character", "testdata", "character.json"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, f.Close())
+	})
+
+	rec := apiDo(t, h, http.MethodPost, apiPrefix+"/characters/import", f)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var ch apiImportedCharacter
+
+	decodeAPI(t, rec, &ch)
+	assert.Equal(t, apiPrefix+"/characters/"+ch.ID, rec.Header().Get("Location"))
+	assert.Equal(t, "Ричард Смит", ch.Investigator.PersonalDetails.Name)
+	assert.Equal(t, ch.ID, ch.Validation.ID)
+	assert.Equal(t, len(ch.Validation.Findings), ch.Validation.Errors+ch.Validation.Warnings)
+
+	// Sheet which could not be normalised is stored as is.
+	rec = apiDo(t, h, http.MethodPost, apiPrefix+"/characters/import",
+		strings.NewReader(`{"Investigator":{"PersonalDetails":{"Name":"Harvey Walters"},"Characteristics":{"STR":"strong"}}}`))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var broken apiImportedCharacter
+
+	decodeAPI(t, rec, &broken)
+	assert.Equal(t, "strong", broken.Investigator.Characteristics.Str)
+	assert.False(t, broken.Validation.Valid)
+	assert.Contains(t, broken.Validation.Findings, validation.Finding{
+		Severity: validation.SeverityError,
+		Path:     "Characteristics",
+		Message:  `STR: invalid number "strong"`,
+	})
+}
+
+func TestAPI_Errors(t *testing.T) {
+	h, _ := newAPIRouter(t)
+
+	tooLarge := `{"investigator":{"PersonalDetails":{"Name":"` + strings.Repeat("a", maxAPIBodySize) + `"}}}`
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		body    string
+		status  int
+		message string
+	}{
+		{
+			name:    "create without name",
+			method:  http.MethodPost,
+			target:  apiPrefix + "/characters",
+			body:    `{"investigator":{}}`,
+			status:  http.StatusBadRequest,
+			message: "Investigator name is required",
+		},
+		{
+			name:    "malformed body",
+			method:  http.MethodPost,
+			target:  apiPrefix + "/characters",
+			body:    `{"investigator":`,
+			status:  http.StatusBadRequest,
+			message: "Failed to decode request body",
+		},
+		{
+			name:    "body too large",
+			method:  http.MethodPost,
+			target:  apiPrefix + "/characters",
+			body:    tooLarge,
+			status:  http.StatusRequestEntityTooLarge,
+			message: "Request body is too large",
+		},
+		{
+			name:    "import body too large",
+			method:  http.MethodPost,
+			target:  apiPrefix + "/characters/import",
+			body:    tooLarge,
+			status:  http.StatusRequestEntityTooLarge,
+			message: "Request body is too large",
+		},
+		{
+			name:    "wrong id",
+			method:  http.MethodGet,
+			target:  apiPrefix + "/characters/not-an-id",
+			status:  http.StatusBadRequest,
+			message: "Wrong character ID format",
+		},
+		{
+			name:    "missing character",
+			method:  http.MethodGet,
+			target:  apiPrefix + "/characters/0b7a2c1e-4c1a-4f0e-9d5e-9a1b2c3d4e5f",
+			status:  http.StatusNotFound,
+			message: "Character not found",
+		},
+		{
+			name:    "update missing character",
+			method:  http.MethodPut,
+			target:  apiPrefix + "/characters/0b7a2c1e-4c1a-4f0e-9d5e-9a1b2c3d4e5f",
+			body:    `{"version":1,"investigator":{"PersonalDetails":{"Name":"Harvey Walters"}}}`,
+			status:  http.StatusNotFound,
+			message: "Character not found",
+		},
+		{
+			name:    "unknown campaign filter",
+			method:  http.MethodGet,
+			target:  apiPrefix + "/characters?campaign=0b7a2c1e-4c1a-4f0e-9d5e-9a1b2c3d4e5f",
+			status:  http.StatusNotFound,
+			message: "Campaign not found",
+		},
+		{
+			name:    "wrong campaign filter",
+			method:  http.MethodGet,
+			target:  apiPrefix + "/characters?campaign=masks",
+			status:  http.StatusBadRequest,
+			message: "Wrong campaign ID format",
+		},
+		{
+			name:    "unknown era",
+			method:  http.MethodGet,
+			target:  apiPrefix + "/occupations?era=gaslight",
+			status:  http.StatusBadRequest,
+			message: "Unknown era",
+		},
+		{
+			name:    "unknown occupation",
+			method:  http.MethodGet,
+			target:  apiPrefix + "/occupations/Necromancer",
+			status:  http.StatusNotFound,
+			message: "Occupation not found",
+		},
+		{
+			name:    "unknown path",
+			method:  http.MethodGet,
+			target:  apiPrefix + "/spells",
+			status:  http.StatusNotFound,
+			message: "Not found",
+		},
+		{
+			name:    "unknown method",
+			method:  http.MethodPatch,
+			target:  apiPrefix + "/characters",
+			status:  http.StatusNotFound,
+			message: "Not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := apiDo(t, h, tt.method, tt.target, strings.NewReader(tt.body))
+
+			assertAPIError(t, rec, tt.status, tt.message)
+		})
+	}
+}
+
+func TestAPI_Occupations(t *testing.T) {
+	h, _ := newAPIRouter(t)
+
+	rec := apiDo(t, h, http.MethodGet, apiPrefix+"/occupations?era=modern", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var list apiOccupationsList
+
+	decodeAPI(t, rec, &list)
+	assert.NotEmpty(t, list.Occupations)
+
+	rec = apiDo(t, h, http.MethodGet, apiPrefix+"/occupations/Antiquarian", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAPI_UpdateVersion(t *testing.T) {
+	h, _ := newAPIRouter(t)
+
+	ch := createAPICharacter(t, h, "Harvey Walters")
+	target := apiPrefix + "/characters/" + ch.ID
+
+	rec := apiDo(t, h, http.MethodGet, target, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+
+	update := func(body string) string {
+		return `{` + body + `"investigator":{"PersonalDetails":{"Name":"Harvey Walters"}}}`
+	}
+
+	assertAPIError(t, apiDo(t, h, http.MethodPut, target, strings.NewReader(update(``))),
+		http.StatusPreconditionRequired, "Character version is required")
+	assertAPIError(t, apiDo(t, h, http.MethodPut, target, strings.NewReader(update(`"version":0,`))),
+		http.StatusPreconditionFailed, "Character was modified by someone else")
+	assertAPIError(t, apiDo(t, h, http.MethodPut, target, strings.NewReader(update(``)), "If-Match", `"one"`),
+		http.StatusBadRequest, "Wrong character version")
+
+	rec = apiDo(t, h, http.MethodPut, target, strings.NewReader(update(``)), "If-Match", `"1"`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+	var got apiCharacter
+
+	decodeAPI(t, rec, &got)
+	assert.Equal(t, 2, got.Version)
+
+	assertAPIError(t, apiDo(t, h, http.MethodPut, target, strings.NewReader(update(``)), "If-Match", `"1"`),
+		http.StatusPreconditionFailed, "Character was modified by someone else")
+
+	rec = apiDo(t, h, http.MethodPut, target, strings.NewReader(update(`"version":2,`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+}
@@ -0,0 +1,714 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/auth"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/service/assets"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+)
+
+// AuthParams configures authentication of the service users.
+type AuthParams struct {
+	// Enabled turns on login and access control. When disabled everyone can see and edit everything.
+	Enabled bool
+	// SessionTTL is a lifetime of the login session.
+	SessionTTL time.Duration
+	// SecureCookie marks session cookie to be sent over HTTPS only.
+	SecureCookie bool
+}
+
+const sessionCookie = "cmt_session"
+
+var (
+	// errUnauthenticated is returned when request has no valid login session.
+	errUnauthenticated = errors.New("not authenticated")
+	// errForbidden is returned when user has no access to the requested character.
+	errForbidden = errors.New("access denied")
+)
+
+// publicPaths are available without login.
+var publicPaths = []string{
+	"/login",
+	"/logout",
+	"/register",
+	"/favicon.ico",
+	"/healthz",
+	"/readyz",
+	// Metrics are scraped by monitoring without login session.
+	"/metrics",
+}
+
+// keeperPaths are path prefixes available to keepers only.
+var keeperPaths = []string{
+	"/campaigns",
+	"/encounters",
+	"/chases",
+	"/handouts",
+	"/users",
+}
+
+func authRoutes(db storage.Storage, params AuthParams) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		makePathPattern(http.MethodGet, "/login"):     loginFormHandler(),
+		makePathPattern(http.MethodPost, "/login"):    loginHandler(db, params),
+		makePathPattern(http.MethodPost, "/logout"):   logoutHandler(db, params),
+		makePathPattern(http.MethodGet, "/register"):  registerFormHandler(db),
+		makePathPattern(http.MethodPost, "/register"): registerHandler(db, params),
+		makePathPattern(http.MethodGet, "/users"):     listUsersHandler(db),
+		makePathPattern(http.MethodPost, "/users"):    userCreateHandler(db),
+	}
+}
+
+type viewerKey struct{}
+
+// viewer is the logged-in user making the request.
+// Nil viewer means authentication is disabled and everything is allowed.
+type viewer struct {
+	user auth.User
+	// kept are IDs of characters in campaigns run by the keeper.
+	kept map[string]bool
+}
+
+// viewerFromContext returns viewer of the request, nil when authentication is disabled.
+func viewerFromContext(ctx context.Context) *viewer {
+	v, _ := ctx.Value(viewerKey{}).(*viewer)
+
+	return v
+}
+
+// User returns logged-in user, nil when authentication is disabled.
+func (v *viewer) User() *auth.User {
+	if v == nil {
+		return nil
+	}
+
+	return &v.user
+}
+
+// ownerID returns ID to set as the owner of created characters.
+func (v *viewer) ownerID() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.user.ID
+}
+
+// canAccess reports whether viewer may see and edit the character.
+// Players have access to their own investigators only, keepers also to characters of their campaigns
+// and characters created before authentication was enabled.
+func (v *viewer) canAccess(ch storage.Character) bool {
+	switch {
+	case v == nil, ch.Owner == v.user.ID:
+		return true
+	case v.user.IsKeeper():
+		return ch.Owner == "" || v.kept[ch.ID]
+	default:
+		return false
+	}
+}
+
+// canKeep reports whether viewer is the keeper of campaign, encounter, chase or handout with keeperID.
+// Records created without authentication are shared by all keepers.
+func (v *viewer) canKeep(keeperID string) bool {
+	if v == nil {
+		return true
+	}
+
+	return v.user.IsKeeper() && (keeperID == "" || keeperID == v.user.ID)
+}
+
+// accessible filters characters available to the viewer.
+func (v *viewer) accessible(list []storage.Character) []storage.Character {
+	return slices.DeleteFunc(list, func(ch storage.Character) bool {
+		return !v.canAccess(ch)
+	})
+}
+
+// getCharacter loads character by ID and checks that viewer of the request has access to it.
+func getCharacter(r *http.Request, db storage.Storage, id string) (storage.Character, error) {
+	ch, err := db.Get(id)
+	if err != nil {
+		return storage.Character{}, err
+	}
+
+	if !viewerFromContext(r.Context()).canAccess(ch) {
+		return storage.Character{}, errForbidden
+	}
+
+	return ch, nil
+}
+
+// authMiddleware requires login session for all non-public routes of the mux and enforces user roles:
+// keeper only sections, access to characters and campaigns in the path.
+func authMiddleware(db storage.Storage, mux *http.ServeMux, params AuthParams) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !params.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, pattern := mux.Handler(r)
+
+			// Requests rejected here do not reach the mux, so route is reported from this pattern.
+			setRoute(w, pattern)
+
+			_, path, _ := strings.Cut(pattern, " ")
+
+			if slices.Contains(publicPaths, path) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			v, err := sessionViewer(db, r)
+			if err != nil {
+				if !errors.Is(err, errUnauthenticated) {
+					logger.WithError(r.Context(), err).Error("Failed to check session")
+				}
+
+				unauthorizedResponse(w, r)
+
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), viewerKey{}, v)
+			ctx = logger.ContextWithLogger(ctx, logger.FromContext(ctx).WithField("user", v.user.Name))
+
+			r = r.WithContext(ctx)
+
+			allowed, err := v.allowed(db, r, path)
+			if err != nil {
+				logger.WithError(r.Context(), err).Error("Failed to check access")
+
+				operationResponse(w, r, http.StatusInternalServerError, "Failed to check access")
+
+				return
+			}
+
+			if !allowed {
+				forbiddenResponse(w, r)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// allowed reports whether viewer may use the route with pattern path.
+// Missing or malformed IDs are allowed here, so route handler responds with proper error.
+func (v *viewer) allowed(db storage.Storage, r *http.Request, path string) (bool, error) {
+	if !v.user.IsKeeper() && slices.ContainsFunc(keeperPaths, func(p string) bool {
+		return path == p || strings.HasPrefix(path, p+"/")
+	}) {
+		return false, nil
+	}
+
+	id := pathID(path, r.URL.Path)
+	if id == "" || !isValidID(id) {
+		return true, nil
+	}
+
+	switch {
+	case strings.HasPrefix(path, "/characters/{id}"), strings.HasPrefix(path, apiPrefix+"/characters/{id}"):
+		ch, err := db.Get(id)
+		if err != nil {
+			return errors.Is(err, storage.ErrNotFound), ignoreNotFound(err)
+		}
+
+		return v.canAccess(ch), nil
+	case strings.HasPrefix(path, "/campaigns/{id}"):
+		c, err := db.Campaigns().Get(id)
+		if err != nil {
+			return errors.Is(err, storage.ErrNotFound), ignoreNotFound(err)
+		}
+
+		return v.canKeep(c.KeeperID), nil
+	case strings.HasPrefix(path, "/encounters/{id}"):
+		e, err := db.Encounters().Get(id)
+		if err != nil {
+			return errors.Is(err, storage.ErrNotFound), ignoreNotFound(err)
+		}
+
+		return v.canKeep(e.KeeperID), nil
+	case strings.HasPrefix(path, "/chases/{id}"):
+		c, err := db.Chases().Get(id)
+		if err != nil {
+			return errors.Is(err, storage.ErrNotFound), ignoreNotFound(err)
+		}
+
+		return v.canKeep(c.KeeperID), nil
+	case strings.HasPrefix(path, "/handouts/{id}"):
+		h, err := db.Handouts().Get(id)
+		if err != nil {
+			return errors.Is(err, storage.ErrNotFound), ignoreNotFound(err)
+		}
+
+		return v.canKeep(h.KeeperID), nil
+	default:
+		return true, nil
+	}
+}
+
+// pathID returns value of the {id} wildcard of the pattern path in the request path.
+func pathID(pattern, path string) string {
+	idx := slices.Index(strings.Split(pattern, "/"), "{id}")
+	if idx < 0 {
+		return ""
+	}
+
+	segments := strings.Split(path, "/")
+	if idx >= len(segments) {
+		return ""
+	}
+
+	return segments[idx]
+}
+
+func ignoreNotFound(err error) error {
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// sessionViewer returns viewer by the session cookie of the request.
+func sessionViewer(db storage.Storage, r *http.Request) (*viewer, error) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil || cookie.Value == "" {
+		return nil, errUnauthenticated
+	}
+
+	s, err := db.Sessions().Get(auth.SessionID(cookie.Value))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, errUnauthenticated
+		}
+
+		return nil, err
+	}
+
+	if s.Expired(time.Now().UTC()) {
+		if err = db.Sessions().Delete(s.ID); err != nil {
+			return nil, err
+		}
+
+		return nil, errUnauthenticated
+	}
+
+	u, err := db.Users().Get(s.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, errUnauthenticated
+		}
+
+		return nil, err
+	}
+
+	v := &viewer{
+		user: u,
+		kept: make(map[string]bool),
+	}
+
+	if !u.IsKeeper() {
+		return v, nil
+	}
+
+	campaigns, err := db.Campaigns().List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range campaigns {
+		if c.KeeperID != u.ID {
+			continue
+		}
+
+		for _, m := range c.Members {
+			v.kept[m.CharacterID] = true
+		}
+	}
+
+	return v, nil
+}
+
+func unauthorizedResponse(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasPrefix(r.URL.Path, apiPrefix+"/"):
+		apiErrorResponseWrite(w, r, http.StatusUnauthorized, "Login required")
+	case r.Method == http.MethodGet:
+		http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+	default:
+		operationResponse(w, r, http.StatusUnauthorized, "Login required")
+	}
+}
+
+func forbiddenResponse(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, apiPrefix+"/") {
+		apiErrorResponseWrite(w, r, http.StatusForbidden, "Access denied")
+
+		return
+	}
+
+	operationResponse(w, r, http.StatusForbidden, "Access denied")
+}
+
+type loginView struct {
+	Next  string
+	Error string
+}
+
+func loginFormHandler() http.HandlerFunc {
+	html := string(assets.MustLoad("login.gohtml"))
+	tmpl := template.Must(template.New("login").Parse(html))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		renderLogin(w, r, tmpl, http.StatusOK, loginView{
+			Next: r.URL.Query().Get("next"),
+		})
+	}
+}
+
+func loginHandler(db storage.Storage, params AuthParams) http.HandlerFunc {
+	html := string(assets.MustLoad("login.gohtml"))
+	tmpl := template.Must(template.New("login").Parse(html))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Failed to parse form")
+
+			return
+		}
+
+		next := r.FormValue("next")
+
+		u, err := findUser(db, r.FormValue("name"))
+		if err == nil {
+			err = u.CheckPassword(r.FormValue("password"))
+		}
+
+		if err != nil {
+			if !errors.Is(err, storage.ErrNotFound) && !errors.Is(err, auth.ErrInvalidCredentials) {
+				logger.WithError(r.Context(), err).Error("Failed to get user")
+
+				operationResponse(w, r, http.StatusInternalServerError, "Failed to get user")
+
+				return
+			}
+
+			logger.WithField(r.Context(), "name", r.FormValue("name")).Warn("Failed login attempt")
+
+			renderLogin(w, r, tmpl, http.StatusUnauthorized, loginView{
+				Next:  next,
+				Error: "Неверное имя или пароль",
+			})
+
+			return
+		}
+
+		if err = startSession(w, db, params, u); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to start session")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to start session")
+
+			return
+		}
+
+		logger.WithFields(r.Context(), logger.Fields{
+			"id":   u.ID,
+			"name": u.Name,
+		}).Info("User logged in")
+
+		http.Redirect(w, r, safeRedirect(next), http.StatusSeeOther)
+	}
+}
+
+func renderLogin(w http.ResponseWriter, r *http.Request, tmpl *template.Template, status int, view loginView) {
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(status)
+
+	if err := tmpl.Execute(w, view); err != nil {
+		logger.WithError(r.Context(), err).Error("Failed to render login form")
+	}
+}
+
+// safeRedirect returns local path to redirect after login, so login form can not send user to another site.
+func safeRedirect(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+
+	return next
+}
+
+func logoutHandler(db storage.Storage, params AuthParams) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie(sessionCookie); err == nil && cookie.Value != "" {
+			err = db.Sessions().Delete(auth.SessionID(cookie.Value))
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				logger.WithError(r.Context(), err).Error("Failed to delete session")
+			}
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     sessionCookie,
+			Path:     "/",
+			MaxAge:   -1,
+			HttpOnly: true,
+			Secure:   params.SecureCookie,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+	}
+}
+
+// startSession stores new session of the user and sets its cookie.
+func startSession(w http.ResponseWriter, db storage.Storage, params AuthParams, u auth.User) error {
+	now := time.Now().UTC()
+
+	s, token, err := auth.NewSession(u.ID, now, params.SessionTTL)
+	if err != nil {
+		return err
+	}
+
+	if err = db.Sessions().Create(s.ID, s); err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  s.ExpiresAt,
+		MaxAge:   int(params.SessionTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   params.SecureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return nil
+}
+
+type registerView struct {
+	Error       string
+	MinPassword int
+}
+
+func registerFormHandler(db storage.Storage) http.HandlerFunc {
+	html := string(assets.MustLoad("register.gohtml"))
+	tmpl := template.Must(template.New("register").Parse(html))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		users, err := db.Users().List()
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get users list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get users list")
+
+			return
+		}
+
+		if len(users) != 0 {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+
+		if err = tmpl.Execute(w, registerView{MinPassword: auth.MinPasswordLength}); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render register form")
+		}
+	}
+}
+
+// registerHandler creates the first user of the service, it gets the keeper role.
+// Other accounts are created by keepers.
+func registerHandler(db storage.Storage, params AuthParams) http.HandlerFunc {
+	// Check for existing users and creation of the first one are serialised,
+	// so concurrent requests could not register two keepers.
+	var mu sync.Mutex
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Failed to parse form")
+
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		users, err := db.Users().List()
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get users list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get users list")
+
+			return
+		}
+
+		if len(users) != 0 {
+			operationResponse(w, r, http.StatusForbidden, "Registration is closed, ask the keeper for an account")
+
+			return
+		}
+
+		u, ok := createUser(w, r, db, auth.RoleKeeper)
+		if !ok {
+			return
+		}
+
+		if err = startSession(w, db, params, u); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to start session")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to start session")
+
+			return
+		}
+
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	}
+}
+
+func listUsersHandler(db storage.Storage) http.HandlerFunc {
+	html := string(assets.MustLoad("users.gohtml"))
+	tmpl := template.Must(template.New("users").Parse(html))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		users, err := db.Users().List()
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get users list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get users list")
+
+			return
+		}
+
+		slices.SortFunc(users, func(a, b auth.User) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+
+		w.Header().Set("Content-Type", "text/html")
+
+		err = tmpl.Execute(w, struct {
+			Users       []auth.User
+			MinPassword int
+		}{
+			Users:       users,
+			MinPassword: auth.MinPasswordLength,
+		})
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render users list")
+		}
+	}
+}
+
+func userCreateHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Failed to parse form")
+
+			return
+		}
+
+		role, err := auth.ParseRole(r.FormValue("role"))
+		if err != nil {
+			operationResponse(w, r, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		if _, ok := createUser(w, r, db, role); !ok {
+			return
+		}
+
+		http.Redirect(w, r, "/users", http.StatusSeeOther)
+	}
+}
+
+// createUser stores new user from the form values.
+// It writes error response and returns false when user could not be created.
+func createUser(w http.ResponseWriter, r *http.Request, db storage.Storage, role auth.Role) (auth.User, bool) {
+	name := r.FormValue("name")
+
+	_, err := findUser(db, name)
+
+	switch {
+	case err == nil:
+		operationResponse(w, r, http.StatusConflict, "User with this name already exists")
+
+		return auth.User{}, false
+	case !errors.Is(err, storage.ErrNotFound):
+		logger.WithError(r.Context(), err).Error("Failed to get user")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to get user")
+
+		return auth.User{}, false
+	}
+
+	u, err := auth.NewUser(uuid.New().String(), name, r.FormValue("password"), role, time.Now().UTC())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidUser) {
+			operationResponse(w, r, http.StatusBadRequest, err.Error())
+
+			return auth.User{}, false
+		}
+
+		logger.WithError(r.Context(), err).Error("Failed to create user")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to create user")
+
+		return auth.User{}, false
+	}
+
+	if err = db.Users().Create(u.ID, u); err != nil {
+		logger.WithError(r.Context(), err).Error("Failed to save user to storage")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to save user to storage")
+
+		return auth.User{}, false
+	}
+
+	logger.WithFields(r.Context(), logger.Fields{
+		"id":   u.ID,
+		"name": u.Name,
+		"role": u.Role,
+	}).Info("Create user")
+
+	return u, true
+}
+
+// findUser returns user by name, names are case-insensitive.
+func findUser(db storage.Storage, name string) (auth.User, error) {
+	name = strings.TrimSpace(name)
+
+	users, err := db.Users().List()
+	if err != nil {
+		return auth.User{}, err
+	}
+
+	for _, u := range users {
+		if strings.EqualFold(u.Name, name) {
+			return u, nil
+		}
+	}
+
+	return auth.User{}, storage.ErrNotFound
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/auth"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/campaign"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/chase"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/combat"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/handouts"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/testlogger"
+)
+
+// authFixture is a router with authentication enabled and session tokens of its users.
+type authFixture struct {
+	t      *testing.T
+	ctx    context.Context
+	db     storage.Storage
+	router http.Handler
+	tokens map[string]string
+}
+
+func newAuthFixture(t *testing.T) *authFixture {
+	t.Helper()
+
+	db := storage.NewInMemoryStorage()
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	return &authFixture{
+		t:   t,
+		ctx: testlogger.New(context.Background()),
+		db:  db,
+		router: NewRouter(db, AuthParams{
+			Enabled:    true,
+			SessionTTL: time.Hour,
+		}),
+		tokens: make(map[string]string),
+	}
+}
+
+// user creates user with the role and login session for it.
+func (f *authFixture) user(name string, role auth.Role) auth.User {
+	f.t.Helper()
+
+	now := time.Now().UTC()
+
+	u, err := auth.NewUser(uuid.New().String(), name, "password", role, now)
+	require.NoError(f.t, err)
+	require.NoError(f.t, f.db.Users().Create(u.ID, u))
+
+	s, token, err := auth.NewSession(u.ID, now, time.Hour)
+	require.NoError(f.t, err)
+	require.NoError(f.t, f.db.Sessions().Create(s.ID, s))
+
+	f.tokens[u.ID] = token
+
+	return u
+}
+
+// character creates character owned by the user.
+func (f *authFixture) character(owner auth.User) string {
+	f.t.Helper()
+
+	ch := storage.Character{
+		ID:    uuid.New().String(),
+		Owner: owner.ID,
+	}
+	ch.Investigator.PersonalDetails.Name = owner.Name + "'s investigator"
+
+	require.NoError(f.t, f.db.Create(ch))
+
+	return ch.ID
+}
+
+// campaign creates campaign run by the keeper.
+func (f *authFixture) campaign(keeper auth.User) string {
+	f.t.Helper()
+
+	c, err := campaign.New(uuid.New().String(), "Masks of Nyarlathotep", character.EraClassic, keeper.Name, "", time.Now().UTC())
+	require.NoError(f.t, err)
+
+	c.KeeperID = keeper.ID
+
+	require.NoError(f.t, f.db.Campaigns().Create(c.ID, c))
+
+	return c.ID
+}
+
+// do sends request as the user, nil user is not logged in.
+func (f *authFixture) do(u *auth.User, method, target string, form url.Values) *httptest.ResponseRecorder {
+	f.t.Helper()
+
+	req := httptest.NewRequestWithContext(f.ctx, method, target, strings.NewReader(form.Encode()))
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	if u != nil {
+		req.AddCookie(&http.Cookie{Name: sessionCookie, Value: f.tokens[u.ID]})
+	}
+
+	rec := httptest.NewRecorder()
+
+	f.router.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestAuthMiddleware_Player(t *testing.T) {
+	f := newAuthFixture(t)
+
+	player := f.user("Roger", auth.RolePlayer)
+	other := f.user("Harvey", auth.RolePlayer)
+
+	own := f.character(player)
+	foreign := f.character(other)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   int
+	}{
+		{name: "own character", method: http.MethodGet, target: "/characters/" + own, want: http.StatusOK},
+		{name: "own character api", method: http.MethodGet, target: apiPrefix + "/characters/" + own, want: http.StatusOK},
+		{name: "foreign character", method: http.MethodGet, target: "/characters/" + foreign, want: http.StatusForbidden},
+		{name: "delete foreign character", method: http.MethodDelete, target: "/characters/" + foreign, want: http.StatusForbidden},
+		{name: "foreign character api", method: http.MethodGet, target: apiPrefix + "/characters/" + foreign, want: http.StatusForbidden},
+		{name: "delete foreign character api", method: http.MethodDelete, target: apiPrefix + "/characters/" + foreign, want: http.StatusForbidden},
+		{name: "campaigns", method: http.MethodGet, target: "/campaigns", want: http.StatusForbidden},
+		{name: "encounters", method: http.MethodGet, target: "/encounters", want: http.StatusForbidden},
+		{name: "chases", method: http.MethodGet, target: "/chases", want: http.StatusForbidden},
+		{name: "handouts", method: http.MethodGet, target: "/handouts", want: http.StatusForbidden},
+		{name: "users", method: http.MethodGet, target: "/users", want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := f.do(&player, tt.method, tt.target, nil)
+
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+
+	_, err := f.db.Get(foreign)
+	require.NoError(t, err, "foreign character should not be deleted")
+}
+
+func TestAuthMiddleware_Unauthenticated(t *testing.T) {
+	f := newAuthFixture(t)
+
+	owner := f.user("Roger", auth.RolePlayer)
+	id := f.character(owner)
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		want     int
+		location string
+	}{
+		{name: "login", method: http.MethodGet, target: "/login", want: http.StatusOK},
+		{name: "healthz", method: http.MethodGet, target: "/healthz", want: http.StatusOK},
+		{name: "api list", method: http.MethodGet, target: apiPrefix + "/characters", want: http.StatusUnauthorized},
+		{name: "api character", method: http.MethodGet, target: apiPrefix + "/characters/" + id, want: http.StatusUnauthorized},
+		{name: "api delete", method: http.MethodDelete, target: apiPrefix + "/characters/" + id, want: http.StatusUnauthorized},
+		{
+			name:     "page redirects to login",
+			method:   http.MethodGet,
+			target:   "/characters/" + id,
+			want:     http.StatusSeeOther,
+			location: "/login?next=" + url.QueryEscape("/characters/"+id),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := f.do(nil, tt.method, tt.target, nil)
+
+			assert.Equal(t, tt.want, rec.Code)
+
+			if tt.location != "" {
+				assert.Equal(t, tt.location, rec.Header().Get("Location"))
+			}
+		})
+	}
+
+	t.Run("expired session", func(t *testing.T) {
+		s, token, err := auth.NewSession(owner.ID, time.Now().UTC().Add(-2*time.Hour), time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, f.db.Sessions().Create(s.ID, s))
+
+		f.tokens[owner.ID] = token
+
+		rec := f.do(&owner, http.MethodGet, apiPrefix+"/characters/"+id, nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestAuthMiddleware_KeeperCampaign(t *testing.T) {
+	f := newAuthFixture(t)
+
+	keeper := f.user("Anna", auth.RoleKeeper)
+	rival := f.user("Boris", auth.RoleKeeper)
+	player := f.user("Roger", auth.RolePlayer)
+
+	id := f.character(player)
+	campaignID := f.campaign(keeper)
+	rivalCampaignID := f.campaign(rival)
+
+	target := apiPrefix + "/characters/" + id
+
+	assert.Equal(t, http.StatusForbidden, f.do(&keeper, http.MethodGet, target, nil).Code)
+	assert.Equal(t, http.StatusForbidden, f.do(&keeper, http.MethodGet, "/campaigns/"+rivalCampaignID, nil).Code)
+
+	page := f.do(&keeper, http.MethodGet, "/campaigns/"+campaignID, nil)
+	require.Equal(t, http.StatusOK, page.Code)
+	assert.Contains(t, page.Body.String(), id)
+
+	join := f.do(&keeper, http.MethodPost, "/campaigns/"+campaignID+"/members", url.Values{"character": {id}})
+	require.Equal(t, http.StatusSeeOther, join.Code)
+
+	assert.Equal(t, http.StatusOK, f.do(&keeper, http.MethodGet, target, nil).Code)
+	assert.Equal(t, http.StatusOK, f.do(&keeper, http.MethodGet, "/characters/"+id, nil).Code)
+
+	// Character is taken by the campaign of another keeper now.
+	assert.Equal(t, http.StatusForbidden, f.do(&rival, http.MethodGet, target, nil).Code)
+
+	page = f.do(&rival, http.MethodGet, "/campaigns/"+rivalCampaignID, nil)
+	require.Equal(t, http.StatusOK, page.Code)
+	assert.NotContains(t, page.Body.String(), id)
+
+	join = f.do(&rival, http.MethodPost, "/campaigns/"+rivalCampaignID+"/members", url.Values{"character": {id}})
+	assert.Equal(t, http.StatusForbidden, join.Code)
+}
+
+func TestAuthMiddleware_KeeperRecords(t *testing.T) {
+	f := newAuthFixture(t)
+
+	keeper := f.user("Anna", auth.RoleKeeper)
+	rival := f.user("Boris", auth.RoleKeeper)
+
+	now := time.Now().UTC()
+
+	e := combat.NewEncounter(uuid.New().String(), "Ambush at the docks", now)
+	e.KeeperID = keeper.ID
+	require.NoError(t, f.db.Encounters().Create(e.ID, e))
+
+	c, err := chase.NewChase(uuid.New().String(), "Through the market", []chase.Location{{Name: "Alley"}, {Name: "Market"}}, now)
+	require.NoError(t, err)
+
+	c.KeeperID = keeper.ID
+	require.NoError(t, f.db.Chases().Create(c.ID, c))
+
+	h := handouts.Handout{ID: uuid.New().String(), Kind: handouts.KindTelegram, Body: "Come at once.", CreatedAt: now, KeeperID: keeper.ID}
+	require.NoError(t, f.db.Handouts().Create(h.ID, h))
+
+	for _, target := range []string{"/encounters/" + e.ID, "/chases/" + c.ID, "/handouts/" + h.ID} {
+		t.Run(target, func(t *testing.T) {
+			assert.Equal(t, http.StatusOK, f.do(&keeper, http.MethodGet, target, nil).Code)
+			assert.Equal(t, http.StatusForbidden, f.do(&rival, http.MethodGet, target, nil).Code)
+
+			list := f.do(&rival, http.MethodGet, path.Dir(target), nil)
+			require.Equal(t, http.StatusOK, list.Code)
+			assert.NotContains(t, list.Body.String(), path.Base(target))
+		})
+	}
+
+	finish := f.do(&rival, http.MethodPost, "/encounters/"+e.ID+"/finish", url.Values{})
+	assert.Equal(t, http.StatusForbidden, finish.Code)
+}
+
+func TestRegister_Concurrent(t *testing.T) {
+	f := newAuthFixture(t)
+
+	const requests = 10
+
+	var wg sync.WaitGroup
+
+	for i := range requests {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			f.do(nil, http.MethodPost, "/register", url.Values{
+				"name":     {fmt.Sprintf("keeper-%d", i)},
+				"password": {"correct horse battery staple"},
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	users, err := f.db.Users().List()
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+}
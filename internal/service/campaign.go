@@ -0,0 +1,374 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/campaign"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/service/assets"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+)
+
+func campaignRoutes(db storage.Storage) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		makePathPattern(http.MethodGet, "/campaigns"):               listCampaignsHandler(db),
+		makePathPattern(http.MethodPost, "/campaigns"):              campaignCreateHandler(db),
+		makePathPattern(http.MethodGet, "/campaigns/{id}"):          campaignHandler(db),
+		makePathPattern(http.MethodPost, "/campaigns/{id}"):         campaignActionHandler(db, updateCampaign),
+		makePathPattern(http.MethodPost, "/campaigns/{id}/members"): campaignActionHandler(db, joinCampaign(db)),
+		makePathPattern(http.MethodPost, "/campaigns/{id}/leave"):   campaignActionHandler(db, leaveCampaign),
+	}
+}
+
+var (
+	// errCampaignID is returned when campaign filter is not a valid ID.
+	errCampaignID = errors.New("wrong campaign ID format")
+	// errWrongCharacterID is returned when character ID form value has wrong format.
+	errWrongCharacterID = errors.New("wrong character ID format")
+)
+
+func listCampaignsHandler(db storage.Storage) http.HandlerFunc {
+	listHTML := string(assets.MustLoad("campaigns.gohtml"))
+	listTmpl := template.Must(template.New("campaigns").Parse(listHTML))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := listCampaigns(db, viewerFromContext(r.Context()))
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get campaigns list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get campaigns list")
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+
+		if err = listTmpl.Execute(w, list); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render campaigns list")
+		}
+	}
+}
+
+// listCampaigns returns campaigns run by the viewer sorted by name.
+func listCampaigns(db storage.Storage, v *viewer) ([]campaign.Campaign, error) {
+	list, err := db.Campaigns().List()
+	if err != nil {
+		return nil, err
+	}
+
+	list = slices.DeleteFunc(list, func(c campaign.Campaign) bool {
+		return !v.canKeep(c.KeeperID)
+	})
+
+	slices.SortFunc(list, func(a, b campaign.Campaign) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return list, nil
+}
+
+func campaignCreateHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Failed to parse form")
+
+			return
+		}
+
+		c, err := campaign.New(
+			uuid.New().String(),
+			r.FormValue("name"),
+			character.Era(r.FormValue("era")),
+			r.FormValue("keeper"),
+			r.FormValue("notes"),
+			time.Now().UTC(),
+		)
+		if err != nil {
+			campaignErrorResponse(w, r, err)
+
+			return
+		}
+
+		c.KeeperID = viewerFromContext(r.Context()).ownerID()
+
+		if err = db.Campaigns().Create(c.ID, c); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to save campaign to storage")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to save campaign to storage")
+
+			return
+		}
+
+		logger.WithFields(r.Context(), logger.Fields{
+			"id":   c.ID,
+			"name": c.Name,
+		}).Info("Create campaign")
+
+		http.Redirect(w, r, "/campaigns/"+c.ID, http.StatusSeeOther)
+	}
+}
+
+type campaignView struct {
+	campaign.Campaign
+	Investigators []storage.Character
+	NPCs          []storage.Character
+	// Others are characters that could join the campaign.
+	Others []storage.Character
+}
+
+func campaignHandler(db storage.Storage) http.HandlerFunc {
+	html := string(assets.MustLoad("campaign.gohtml"))
+	tmpl := template.Must(template.New("campaign").Parse(html))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, ok := campaignFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		characters, err := db.List()
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get characters list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get characters list")
+
+			return
+		}
+
+		view := campaignView{
+			Campaign: c,
+		}
+
+		// Members deleted from the characters list are not shown.
+		for _, ch := range characters {
+			m, ok := c.Member(ch.ID)
+
+			switch {
+			case !ok:
+				view.Others = append(view.Others, ch)
+			case m.NPC:
+				view.NPCs = append(view.NPCs, ch)
+			default:
+				view.Investigators = append(view.Investigators, ch)
+			}
+		}
+
+		view.Others, err = recruits(db, viewerFromContext(r.Context()), view.Others)
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get characters list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get characters list")
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+
+		if err = tmpl.Execute(w, view); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render campaign")
+		}
+	}
+}
+
+// campaignAction changes the campaign using the request form values.
+type campaignAction func(r *http.Request, c *campaign.Campaign) error
+
+// campaignActionHandler applies action to the campaign, stores it and redirects back to the campaign page.
+func campaignActionHandler(db storage.Storage, action campaignAction) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, ok := campaignFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		if err := action(r, &c); err != nil {
+			campaignErrorResponse(w, r, err)
+
+			return
+		}
+
+		if err := db.Campaigns().Update(c.ID, c); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to save campaign to storage")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to save campaign to storage")
+
+			return
+		}
+
+		http.Redirect(w, r, "/campaigns/"+c.ID, http.StatusSeeOther)
+	}
+}
+
+func updateCampaign(r *http.Request, c *campaign.Campaign) error {
+	return c.SetDetails(r.FormValue("name"), character.Era(r.FormValue("era")), r.FormValue("keeper"), r.FormValue("notes"))
+}
+
+func joinCampaign(db storage.Storage) campaignAction {
+	return func(r *http.Request, c *campaign.Campaign) error {
+		id := r.FormValue("character")
+		if !isValidID(id) {
+			return errWrongCharacterID
+		}
+
+		ch, err := db.Get(id)
+		if err != nil {
+			return err
+		}
+
+		list, err := recruits(db, viewerFromContext(r.Context()), []storage.Character{ch})
+		if err != nil {
+			return err
+		}
+
+		if len(list) == 0 {
+			return errForbidden
+		}
+
+		return c.Join(id, r.FormValue("npc") != "")
+	}
+}
+
+// recruits filters characters the viewer may add to campaigns: characters accessible to the viewer
+// and investigators of players who do not play in a campaign of another keeper.
+func recruits(db storage.Storage, v *viewer, list []storage.Character) ([]storage.Character, error) {
+	if v == nil {
+		return list, nil
+	}
+
+	users, err := db.Users().List()
+	if err != nil {
+		return nil, err
+	}
+
+	players := make(map[string]bool)
+
+	for _, u := range users {
+		if !u.IsKeeper() {
+			players[u.ID] = true
+		}
+	}
+
+	campaigns, err := db.Campaigns().List()
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[string]bool)
+
+	for _, c := range campaigns {
+		if c.KeeperID == "" || c.KeeperID == v.user.ID {
+			continue
+		}
+
+		for _, m := range c.Members {
+			taken[m.CharacterID] = true
+		}
+	}
+
+	return slices.DeleteFunc(list, func(ch storage.Character) bool {
+		return !v.canAccess(ch) && (!players[ch.Owner] || taken[ch.ID])
+	}), nil
+}
+
+func leaveCampaign(r *http.Request, c *campaign.Campaign) error {
+	return c.Leave(r.FormValue("character"))
+}
+
+// deleteCharacter removes character from its campaigns and deletes it.
+// Memberships are removed first, so a failed deletion never leaves members without a character.
+func deleteCharacter(db storage.Storage, id string) error {
+	if _, err := db.Get(id); err != nil {
+		return err
+	}
+
+	campaigns, err := db.Campaigns().List()
+	if err != nil {
+		return fmt.Errorf("list campaigns: %w", err)
+	}
+
+	for _, c := range campaigns {
+		if err = c.Leave(id); err != nil {
+			continue
+		}
+
+		if err = db.Campaigns().Update(c.ID, c); err != nil {
+			return fmt.Errorf("update campaign %s: %w", c.ID, err)
+		}
+	}
+
+	return db.Delete(id)
+}
+
+// charactersInCampaign filters characters by campaign ID, empty ID keeps all of them.
+func charactersInCampaign(db storage.Storage, list []storage.Character, id string) ([]storage.Character, error) {
+	if id == "" {
+		return list, nil
+	}
+
+	if !isValidID(id) {
+		return nil, errCampaignID
+	}
+
+	c, err := db.Campaigns().Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(list, func(ch storage.Character) bool {
+		return !c.Has(ch.ID)
+	}), nil
+}
+
+// campaignErrorResponse writes error response for failed campaign operation.
+func campaignErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		operationResponse(w, r, http.StatusNotFound, "Character not found")
+	case errors.Is(err, errForbidden):
+		operationResponse(w, r, http.StatusForbidden, "Character could not join the campaign")
+	case errors.Is(err, errWrongCharacterID),
+		errors.Is(err, campaign.ErrInvalidCampaign),
+		errors.Is(err, campaign.ErrUnknownMember):
+		operationResponse(w, r, http.StatusBadRequest, err.Error())
+	default:
+		logger.WithError(r.Context(), err).Error("Failed to update campaign")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to update campaign")
+	}
+}
+
+// campaignFromPath loads campaign by {id} path value.
+func campaignFromPath(w http.ResponseWriter, r *http.Request, db storage.Storage) (campaign.Campaign, bool) {
+	id := r.PathValue("id")
+	if !isValidID(id) {
+		operationResponse(w, r, http.StatusBadRequest, "Wrong campaign ID format")
+
+		return campaign.Campaign{}, false
+	}
+
+	c, err := db.Campaigns().Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			operationResponse(w, r, http.StatusNotFound, "Campaign not found")
+
+			return campaign.Campaign{}, false
+		}
+
+		logger.WithError(r.Context(), err).Error("Failed to get campaign")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to get campaign")
+
+		return campaign.Campaign{}, false
+	}
+
+	return c, true
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/chase"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/service/assets"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+)
+
+func chaseRoutes(db storage.Storage) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		makePathPattern(http.MethodGet, "/chases"):                    listChasesHandler(db),
+		makePathPattern(http.MethodPost, "/chases"):                   chaseCreateHandler(db),
+		makePathPattern(http.MethodGet, "/chases/{id}"):               chaseHandler(db),
+		makePathPattern(http.MethodPost, "/chases/{id}/participants"): chaseActionHandler(db, addParticipant(db)),
+		makePathPattern(http.MethodPost, "/chases/{id}/start"):        chaseActionHandler(db, startChase),
+		makePathPattern(http.MethodPost, "/chases/{id}/moves"):        chaseActionHandler(db, moveParticipant),
+		makePathPattern(http.MethodPost, "/chases/{id}/rounds"):       chaseActionHandler(db, nextChaseRound),
+		makePathPattern(http.MethodPost, "/chases/{id}/finish"):       chaseActionHandler(db, finishChase),
+	}
+}
+
+func listChasesHandler(db storage.Storage) http.HandlerFunc {
+	listHTML := string(assets.MustLoad("chases.gohtml"))
+	listTmpl := template.Must(template.New("chases").Parse(listHTML))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := db.Chases().List()
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get chases list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get chases list")
+
+			return
+		}
+
+		v := viewerFromContext(r.Context())
+
+		list = slices.DeleteFunc(list, func(c chase.Chase) bool {
+			return !v.canKeep(c.KeeperID)
+		})
+
+		slices.SortFunc(list, func(a, b chase.Chase) int {
+			return b.CreatedAt.Compare(a.CreatedAt)
+		})
+
+		w.Header().Set("Content-Type", "text/html")
+
+		err = listTmpl.Execute(w, struct {
+			Chases []chase.Chase
+		}{
+			Chases: list,
+		})
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render chases list")
+		}
+	}
+}
+
+func chaseCreateHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Failed to parse form")
+
+			return
+		}
+
+		locations, err := chase.ParseLocations(r.FormValue("locations"))
+		if err != nil {
+			chaseErrorResponse(w, r, err)
+
+			return
+		}
+
+		c, err := chase.NewChase(uuid.New().String(), r.FormValue("name"), locations, time.Now().UTC())
+		if err != nil {
+			chaseErrorResponse(w, r, err)
+
+			return
+		}
+
+		c.KeeperID = viewerFromContext(r.Context()).ownerID()
+
+		if err = db.Chases().Create(c.ID, c); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to save chase to storage")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to save chase to storage")
+
+			return
+		}
+
+		logger.WithFields(r.Context(), logger.Fields{
+			"id":        c.ID,
+			"locations": len(c.Locations),
+		}).Info("Create chase")
+
+		http.Redirect(w, r, "/chases/"+c.ID, http.StatusSeeOther)
+	}
+}
+
+type chaseView struct {
+	chase.Chase
+	Characters []storage.Character
+}
+
+func chaseHandler(db storage.Storage) http.HandlerFunc {
+	html := string(assets.MustLoad("chase.gohtml"))
+	tmpl := template.Must(template.New("chase").Funcs(template.FuncMap{
+		"inc": func(i int) int { return i + 1 },
+	}).Parse(html))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, ok := chaseFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		characters, err := db.List()
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get characters list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get characters list")
+
+			return
+		}
+
+		characters = viewerFromContext(r.Context()).accessible(characters)
+
+		w.Header().Set("Content-Type", "text/html")
+
+		err = tmpl.Execute(w, chaseView{
+			Chase:      c,
+			Characters: characters,
+		})
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render chase")
+		}
+	}
+}
+
+// chaseAction changes the chase using the request form values.
+type chaseAction func(r *http.Request, c *chase.Chase) error
+
+// chaseActionHandler applies action to the chase, stores it and redirects back to the track.
+func chaseActionHandler(db storage.Storage, action chaseAction) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, ok := chaseFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		if err := action(r, &c); err != nil {
+			chaseErrorResponse(w, r, err)
+
+			return
+		}
+
+		if err := db.Chases().Update(c.ID, c); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to save chase to storage")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to save chase to storage")
+
+			return
+		}
+
+		http.Redirect(w, r, "/chases/"+c.ID, http.StatusSeeOther)
+	}
+}
+
+// addParticipant adds stored character or NPC to the chase. Position form value is 1-based,
+// positive vehicle value puts participant into a vehicle with such MOV.
+func addParticipant(db storage.Storage) chaseAction {
+	return func(r *http.Request, c *chase.Chase) error {
+		var errs error
+
+		num := func(key string) int {
+			v, err := strconv.Atoi(strings.TrimSpace(r.FormValue(key)))
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("%w: %s", errCombatForm, key))
+			}
+
+			return v
+		}
+
+		role, err := chase.ParseRole(r.FormValue("role"))
+		if err != nil {
+			return err
+		}
+
+		position := num("position") - 1
+
+		var vehicle int
+
+		if v := strings.TrimSpace(r.FormValue("vehicle")); v != "" {
+			vehicle = num("vehicle")
+		}
+
+		var p chase.Participant
+
+		if id := r.FormValue("character"); id != "" {
+			if !isValidID(id) {
+				return fmt.Errorf("%w: character id %q", errCombatForm, id)
+			}
+
+			ch, err := getCharacter(r, db, id)
+			if err != nil {
+				return err
+			}
+
+			if p, err = chase.FromInvestigator(ch.ID, ch.ID, role, ch.Investigator); err != nil {
+				return err
+			}
+		} else {
+			p = chase.Participant{
+				ID:           uuid.New().String(),
+				Name:         strings.TrimSpace(r.FormValue("name")),
+				Role:         role,
+				DEX:          num("dex"),
+				MOV:          num("mov"),
+				Skills:       map[string]int{"con": num("con")},
+				DefaultSkill: num("skill"),
+			}
+		}
+
+		if errs != nil {
+			return errs
+		}
+
+		if vehicle > 0 {
+			p.UseVehicle(vehicle)
+		}
+
+		p.Position = position
+
+		return c.Add(p)
+	}
+}
+
+func startChase(_ *http.Request, c *chase.Chase) error {
+	return c.Start(roller)
+}
+
+func moveParticipant(r *http.Request, c *chase.Chase) error {
+	m, err := c.Move(roller, r.FormValue("participant"))
+	if err != nil {
+		return err
+	}
+
+	logger.WithFields(r.Context(), logger.Fields{
+		"chase":       c.ID,
+		"participant": m.Participant,
+		"from":        m.From,
+		"to":          m.To,
+	}).Info("Chase movement")
+
+	return nil
+}
+
+func nextChaseRound(_ *http.Request, c *chase.Chase) error {
+	return c.NextRound()
+}
+
+func finishChase(_ *http.Request, c *chase.Chase) error {
+	if c.Finished {
+		return chase.ErrFinished
+	}
+
+	c.Finish()
+
+	return nil
+}
+
+// chaseErrorResponse writes error response for failed chase operation.
+func chaseErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		operationResponse(w, r, http.StatusNotFound, "Character not found")
+	case errors.Is(err, errForbidden):
+		operationResponse(w, r, http.StatusForbidden, "Access denied")
+	case errors.Is(err, errCombatForm),
+		errors.Is(err, chase.ErrInvalidTrack),
+		errors.Is(err, chase.ErrInvalidParticipant),
+		errors.Is(err, chase.ErrUnknownParticipant),
+		errors.Is(err, chase.ErrCannotMove),
+		errors.Is(err, chase.ErrNotStarted),
+		errors.Is(err, chase.ErrStarted),
+		errors.Is(err, chase.ErrFinished):
+		operationResponse(w, r, http.StatusBadRequest, err.Error())
+	default:
+		logger.WithError(r.Context(), err).Error("Failed to update chase")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to update chase")
+	}
+}
+
+// chaseFromPath loads chase by {id} path value.
+func chaseFromPath(w http.ResponseWriter, r *http.Request, db storage.Storage) (chase.Chase, bool) {
+	id := r.PathValue("id")
+	if !isValidID(id) {
+		operationResponse(w, r, http.StatusBadRequest, "Wrong chase ID format")
+
+		return chase.Chase{}, false
+	}
+
+	c, err := db.Chases().Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			operationResponse(w, r, http.StatusNotFound, "Chase not found")
+
+			return chase.Chase{}, false
+		}
+
+		logger.WithError(r.Context(), err).Error("Failed to get chase")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to get chase")
+
+		return chase.Chase{}, false
+	}
+
+	return c, true
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/combat"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/service/assets"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+)
+
+func combatRoutes(db storage.Storage) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		makePathPattern(http.MethodGet, "/encounters"):                  listEncountersHandler(db),
+		makePathPattern(http.MethodPost, "/encounters"):                 encounterCreateHandler(db),
+		makePathPattern(http.MethodGet, "/encounters/{id}"):             encounterHandler(db),
+		makePathPattern(http.MethodPost, "/encounters/{id}/combatants"): encounterActionHandler(db, addCombatant(db)),
+		makePathPattern(http.MethodPost, "/encounters/{id}/ready"):      encounterActionHandler(db, readyFirearm),
+		makePathPattern(http.MethodPost, "/encounters/{id}/next"):       encounterActionHandler(db, nextTurn),
+		makePathPattern(http.MethodPost, "/encounters/{id}/attacks"):    encounterActionHandler(db, attack),
+		makePathPattern(http.MethodPost, "/encounters/{id}/hp"):         encounterActionHandler(db, changeHP),
+		makePathPattern(http.MethodPost, "/encounters/{id}/finish"):     encounterActionHandler(db, finishEncounter(db)),
+	}
+}
+
+// errCombatForm is returned when combat form values could not be parsed.
+var errCombatForm = errors.New("wrong form value")
+
+func listEncountersHandler(db storage.Storage) http.HandlerFunc {
+	listHTML := string(assets.MustLoad("encounters.gohtml"))
+	listTmpl := template.Must(template.New("encounters").Parse(listHTML))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := db.Encounters().List()
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get encounters list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get encounters list")
+
+			return
+		}
+
+		v := viewerFromContext(r.Context())
+
+		list = slices.DeleteFunc(list, func(e combat.Encounter) bool {
+			return !v.canKeep(e.KeeperID)
+		})
+
+		slices.SortFunc(list, func(a, b combat.Encounter) int {
+			return b.CreatedAt.Compare(a.CreatedAt)
+		})
+
+		characters, err := db.List()
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get characters list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get characters list")
+
+			return
+		}
+
+		characters = v.accessible(characters)
+
+		w.Header().Set("Content-Type", "text/html")
+
+		err = listTmpl.Execute(w, struct {
+			Encounters []combat.Encounter
+			Characters []storage.Character
+		}{
+			Encounters: list,
+			Characters: characters,
+		})
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render encounters list")
+		}
+	}
+}
+
+func encounterCreateHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Failed to parse form")
+
+			return
+		}
+
+		e := combat.NewEncounter(uuid.New().String(), r.FormValue("name"), time.Now().UTC())
+		e.KeeperID = viewerFromContext(r.Context()).ownerID()
+
+		for _, id := range r.Form["id"] {
+			if err := addInvestigator(r, db, &e, id); err != nil {
+				encounterErrorResponse(w, r, err)
+
+				return
+			}
+		}
+
+		if err := db.Encounters().Create(e.ID, e); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to save encounter to storage")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to save encounter to storage")
+
+			return
+		}
+
+		logger.WithFields(r.Context(), logger.Fields{
+			"id":         e.ID,
+			"combatants": len(e.Combatants),
+		}).Info("Create encounter")
+
+		http.Redirect(w, r, "/encounters/"+e.ID, http.StatusSeeOther)
+	}
+}
+
+// addInvestigator adds stored character to the encounter, character ID is used as combatant ID.
+func addInvestigator(r *http.Request, db storage.Storage, e *combat.Encounter, id string) error {
+	if !isValidID(id) {
+		return fmt.Errorf("%w: character id %q", errCombatForm, id)
+	}
+
+	ch, err := getCharacter(r, db, id)
+	if err != nil {
+		return err
+	}
+
+	c, err := combat.FromInvestigator(ch.ID, ch.ID, ch.Investigator)
+	if err != nil {
+		return err
+	}
+
+	return e.Add(c)
+}
+
+type encounterView struct {
+	combat.Encounter
+	Characters []storage.Character
+}
+
+func encounterHandler(db storage.Storage) http.HandlerFunc {
+	html := string(assets.MustLoad("encounter.gohtml"))
+	tmpl := template.Must(template.New("encounter").Parse(html))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		e, ok := encounterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		characters, err := db.List()
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get characters list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get characters list")
+
+			return
+		}
+
+		characters = viewerFromContext(r.Context()).accessible(characters)
+
+		w.Header().Set("Content-Type", "text/html")
+
+		err = tmpl.Execute(w, encounterView{
+			Encounter:  e,
+			Characters: characters,
+		})
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render encounter")
+		}
+	}
+}
+
+// encounterAction changes the encounter using the request form values.
+type encounterAction func(r *http.Request, e *combat.Encounter) error
+
+// encounterActionHandler applies action to the encounter, stores it and redirects back to the tracker.
+func encounterActionHandler(db storage.Storage, action encounterAction) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		e, ok := encounterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		if err := action(r, &e); err != nil {
+			encounterErrorResponse(w, r, err)
+
+			return
+		}
+
+		if err := db.Encounters().Update(e.ID, e); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to save encounter to storage")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to save encounter to storage")
+
+			return
+		}
+
+		http.Redirect(w, r, "/encounters/"+e.ID, http.StatusSeeOther)
+	}
+}
+
+func addCombatant(db storage.Storage) encounterAction {
+	return func(r *http.Request, e *combat.Encounter) error {
+		if id := r.FormValue("character"); id != "" {
+			return addInvestigator(r, db, e, id)
+		}
+
+		var errs error
+
+		num := func(key string) int {
+			v, err := strconv.Atoi(strings.TrimSpace(r.FormValue(key)))
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("%w: %s", errCombatForm, key))
+			}
+
+			return v
+		}
+
+		hp := num("hp")
+
+		c := combat.Combatant{
+			ID:          uuid.New().String(),
+			Name:        strings.TrimSpace(r.FormValue("name")),
+			Kind:        combat.KindNPC,
+			DEX:         num("dex"),
+			CON:         num("con"),
+			HP:          hp,
+			HPMax:       hp,
+			Dodge:       num("dodge"),
+			DamageBonus: strings.TrimSpace(r.FormValue("db")),
+			Weapons: []combat.Weapon{{
+				Name:    strings.TrimSpace(r.FormValue("weapon")),
+				Skill:   num("skill"),
+				Damage:  strings.TrimSpace(r.FormValue("damage")),
+				Firearm: r.FormValue("firearm") != "",
+				Impale:  r.FormValue("impale") != "",
+			}},
+		}
+
+		if errs != nil {
+			return errs
+		}
+
+		return e.Add(c)
+	}
+}
+
+func readyFirearm(r *http.Request, e *combat.Encounter) error {
+	return e.ReadyFirearm(r.FormValue("combatant"), r.FormValue("readied") != "")
+}
+
+func nextTurn(_ *http.Request, e *combat.Encounter) error {
+	return e.Next(roller)
+}
+
+// attack resolves attack with weapon form value in "combatant/weapon index" form.
+func attack(r *http.Request, e *combat.Encounter) error {
+	attacker, idx, ok := strings.Cut(r.FormValue("weapon"), "/")
+	if !ok {
+		return fmt.Errorf("%w: weapon", errCombatForm)
+	}
+
+	weapon, err := strconv.Atoi(idx)
+	if err != nil {
+		return fmt.Errorf("%w: weapon", errCombatForm)
+	}
+
+	resp, err := combat.ParseResponse(r.FormValue("response"))
+	if err != nil {
+		return err
+	}
+
+	a, err := e.Attack(roller, attacker, weapon, r.FormValue("target"), resp)
+	if err != nil {
+		return err
+	}
+
+	logger.WithFields(r.Context(), logger.Fields{
+		"encounter": e.ID,
+		"attacker":  a.Attacker,
+		"target":    a.Target,
+		"hit":       a.Hit,
+	}).Info("Attack resolved")
+
+	return nil
+}
+
+// changeHP heals combatant by positive amount and damages by negative one.
+func changeHP(r *http.Request, e *combat.Encounter) error {
+	amount, err := strconv.Atoi(strings.TrimSpace(r.FormValue("amount")))
+	if err != nil {
+		return fmt.Errorf("%w: amount", errCombatForm)
+	}
+
+	_, err = e.Heal(roller, r.FormValue("combatant"), amount)
+
+	return err
+}
+
+// finishEncounter ends the combat and writes hit points of investigators back to their sheets.
+// Characters are updated one by one before the encounter is stored as finished,
+// so the sheets already updated by the failed attempt are skipped when it is retried.
+func finishEncounter(db storage.Storage) encounterAction {
+	return func(r *http.Request, e *combat.Encounter) error {
+		if e.Finished {
+			return combat.ErrFinished
+		}
+
+		now := time.Now().UTC()
+
+		for _, c := range e.Combatants {
+			if c.CharacterID == "" {
+				continue
+			}
+
+			ch, err := getCharacter(r, db, c.CharacterID)
+			if err != nil {
+				if errors.Is(err, storage.ErrNotFound) {
+					logger.WithFields(r.Context(), logger.Fields{
+						"character": c.CharacterID,
+					}).Warn("Character of the encounter is deleted")
+
+					continue
+				}
+
+				return err
+			}
+
+			if ch.HasSourceLog(storage.LogCombat, e.ID) {
+				continue
+			}
+
+			from := ch.Investigator.Characteristics.HitPts
+			ch.Investigator.Characteristics.HitPts = strconv.Itoa(c.HP)
+
+			ch.AddSourceLog(now, storage.LogCombat, e.ID, fmt.Sprintf("%s: HP %s → %d, %s", e.Name, from, c.HP, c.Status()))
+
+			if _, err = db.Update(ch); err != nil {
+				return err
+			}
+		}
+
+		e.Finish()
+
+		return nil
+	}
+}
+
+// encounterErrorResponse writes error response for failed encounter operation.
+func encounterErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		operationResponse(w, r, http.StatusNotFound, "Character not found")
+	case errors.Is(err, errForbidden):
+		operationResponse(w, r, http.StatusForbidden, "Access denied")
+	case errors.Is(err, storage.ErrConflict):
+		operationResponse(w, r, http.StatusConflict, "Character was modified by someone else, try again")
+	case errors.Is(err, errCombatForm),
+		errors.Is(err, combat.ErrInvalidCombatant),
+		errors.Is(err, combat.ErrUnknownCombatant),
+		errors.Is(err, combat.ErrCannotAct),
+		errors.Is(err, combat.ErrUnknownResponse),
+		errors.Is(err, combat.ErrNotStarted),
+		errors.Is(err, combat.ErrFinished):
+		operationResponse(w, r, http.StatusBadRequest, err.Error())
+	default:
+		logger.WithError(r.Context(), err).Error("Failed to update encounter")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to update encounter")
+	}
+}
+
+// encounterFromPath loads encounter by {id} path value.
+func encounterFromPath(w http.ResponseWriter, r *http.Request, db storage.Storage) (combat.Encounter, bool) {
+	id := r.PathValue("id")
+	if !isValidID(id) {
+		operationResponse(w, r, http.StatusBadRequest, "Wrong encounter ID format")
+
+		return combat.Encounter{}, false
+	}
+
+	e, err := db.Encounters().Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			operationResponse(w, r, http.StatusNotFound, "Encounter not found")
+
+			return combat.Encounter{}, false
+		}
+
+		logger.WithError(r.Context(), err).Error("Failed to get encounter")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to get encounter")
+
+		return combat.Encounter{}, false
+	}
+
+	return e, true
+}
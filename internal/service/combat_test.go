@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/combat"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/testlogger"
+)
+
+func TestFinishEncounter_Retry(t *testing.T) {
+	db := storage.NewInMemoryStorage()
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	now := time.Now().UTC()
+
+	e := combat.NewEncounter(uuid.New().String(), "Ambush at the docks", now)
+
+	for i, name := range []string{"Harvey Walters", "Roger Carlyle"} {
+		ch := storage.Character{ID: uuid.New().String()}
+		ch.Investigator.PersonalDetails.Name = name
+		ch.Investigator.Characteristics.HitPts = "12"
+
+		// The first investigator was updated by the attempt which failed on the second one.
+		if i == 0 {
+			ch.Investigator.Characteristics.HitPts = "4"
+			ch.AddSourceLog(now, storage.LogCombat, e.ID, e.Name+": HP 12 → 4, wounded")
+		}
+
+		require.NoError(t, db.Create(ch))
+
+		e.Combatants = append(e.Combatants, combat.Combatant{
+			ID:          ch.ID,
+			Name:        name,
+			Kind:        combat.KindInvestigator,
+			CharacterID: ch.ID,
+			HP:          4 + i,
+			HPMax:       12,
+		})
+	}
+
+	req := httptest.NewRequestWithContext(testlogger.New(context.Background()), http.MethodPost, "/encounters/"+e.ID+"/finish", nil)
+
+	require.NoError(t, finishEncounter(db)(req, &e))
+	assert.True(t, e.Finished)
+
+	for _, c := range e.Combatants {
+		ch, err := db.Get(c.CharacterID)
+		require.NoError(t, err)
+
+		assert.Len(t, ch.LogOf(storage.LogCombat), 1, "HP change is logged once")
+		assert.Equal(t, strconv.Itoa(c.HP), ch.Investigator.Characteristics.HitPts)
+	}
+}
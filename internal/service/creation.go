@@ -0,0 +1,416 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/creation"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/occupation"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/service/assets"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+)
+
+const (
+	creationActionRoll     = "roll"
+	creationActionPointBuy = "pointbuy"
+	creationActionBack     = "back"
+)
+
+// skillPointsFormulas are offered in the occupation step, custom formula could be entered as well.
+var skillPointsFormulas = []string{
+	"EDU×4",
+	"EDU×2+DEX×2",
+	"EDU×2+STR×2",
+	"EDU×2+APP×2",
+	"EDU×2+POW×2",
+	"EDU×2+(STR|DEX)×2",
+}
+
+func creationRoutes(db storage.Storage) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		makePathPattern(http.MethodGet, "/creation"):       creationStartFormHandler(),
+		makePathPattern(http.MethodPost, "/creation"):      creationStartHandler(db),
+		makePathPattern(http.MethodGet, "/creation/{id}"):  creationStepHandler(db),
+		makePathPattern(http.MethodPost, "/creation/{id}"): creationSubmitHandler(db),
+	}
+}
+
+type creationSkillView struct {
+	Index      int
+	Name       string
+	Base       int
+	Occupation bool
+	Allocation creation.Allocation
+}
+
+type creationView struct {
+	creation.Draft
+	StepName      string
+	Error         string
+	Budget        creation.Budget
+	Skills        []creationSkillView
+	AgeBrackets   []creation.AgeBracket
+	Formulas      []string
+	Occupations   []occupation.Occupation
+	PointBuyTotal int
+	MinAge        int
+	MaxAge        int
+}
+
+func newCreationView(d creation.Draft, errMsg string) creationView {
+	bases := d.Skills()
+
+	skills := make([]creationSkillView, 0, len(bases))
+	for i, s := range bases {
+		skills = append(skills, creationSkillView{
+			Index:      i,
+			Name:       s.FullName(),
+			Base:       s.Base,
+			Occupation: d.IsOccupationSkill(s.FullName()),
+			Allocation: d.Allocations[s.FullName()],
+		})
+	}
+
+	return creationView{
+		Draft:         d,
+		StepName:      d.Step.String(),
+		Error:         errMsg,
+		Budget:        d.Budget(),
+		Skills:        skills,
+		AgeBrackets:   creation.AgeBrackets(),
+		Formulas:      skillPointsFormulas,
+		Occupations:   occupation.ForEra(character.EraFor(d.GameType)),
+		PointBuyTotal: creation.PointBuyTotal,
+		MinAge:        creation.MinAge,
+		MaxAge:        creation.MaxAge,
+	}
+}
+
+func creationStartFormHandler() http.HandlerFunc {
+	formHTML := string(assets.MustLoad("creation_start.gohtml"))
+	formTmpl := template.Must(template.New("form").Parse(formHTML))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+
+		data := struct {
+			GameTypes []string
+		}{
+			GameTypes: []string{character.GameTypeClassic, character.GameTypeModern},
+		}
+
+		if err := formTmpl.Execute(w, data); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render form")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to render form")
+		}
+	}
+}
+
+func creationStartHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimSpace(r.FormValue("name"))
+		if name == "" {
+			operationResponse(w, r, http.StatusBadRequest, "Investigator name is required")
+
+			return
+		}
+
+		gameType := r.FormValue("game_type")
+		if gameType != character.GameTypeClassic && gameType != character.GameTypeModern {
+			operationResponse(w, r, http.StatusBadRequest, "Wrong game type")
+
+			return
+		}
+
+		d := creation.NewDraft(uuid.New().String(), name, gameType, time.Now().UTC())
+
+		if err := db.Drafts().Create(d.ID, d); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to save draft to storage")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to save draft to storage")
+
+			return
+		}
+
+		http.Redirect(w, r, "/creation/"+d.ID, http.StatusSeeOther)
+	}
+}
+
+func creationStepHandler(db storage.Storage) http.HandlerFunc {
+	tmpl := creationTemplate()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		d, ok := draftFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		renderCreationStep(w, r, tmpl, http.StatusOK, d, "")
+	}
+}
+
+func creationSubmitHandler(db storage.Storage) http.HandlerFunc {
+	tmpl := creationTemplate()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		d, ok := draftFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		if r.FormValue("action") == creationActionBack {
+			d.Back()
+
+			saveDraftAndRedirect(w, r, db, d)
+
+			return
+		}
+
+		if d.Step == creation.StepBackstory {
+			creationFinish(w, r, tmpl, db, d)
+
+			return
+		}
+
+		if err := applyCreationForm(r, &d); err != nil {
+			if errors.Is(err, creation.ErrInvalidValues) || errors.Is(err, errCreationForm) {
+				renderCreationStep(w, r, tmpl, http.StatusBadRequest, d, err.Error())
+
+				return
+			}
+
+			logger.WithError(r.Context(), err).Error("Failed to process creation step")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to process creation step")
+
+			return
+		}
+
+		saveDraftAndRedirect(w, r, db, d)
+	}
+}
+
+var errCreationForm = errors.New("wrong form value")
+
+func applyCreationForm(r *http.Request, d *creation.Draft) error {
+	switch d.Step {
+	case creation.StepCharacteristics:
+		switch r.FormValue("action") {
+		case creationActionRoll:
+			return d.RollCharacteristics(roller)
+		case creationActionPointBuy:
+			c, err := formCore(r)
+			if err != nil {
+				return err
+			}
+
+			return d.PointBuy(roller, c)
+		default:
+			return d.ConfirmCharacteristics()
+		}
+	case creation.StepAge:
+		age, err := formInt(r, "age")
+		if err != nil {
+			return fmt.Errorf("%w: age", errCreationForm)
+		}
+
+		deductions := make(creation.Deductions)
+
+		for _, name := range []string{"STR", "CON", "DEX", "SIZ"} {
+			v, err := formInt(r, "deduct_"+name)
+			if err != nil {
+				return fmt.Errorf("%w: %s deduction", errCreationForm, name)
+			}
+
+			if v != 0 {
+				deductions[name] = v
+			}
+		}
+
+		return d.SetAge(roller, age, deductions)
+	case creation.StepOccupation:
+		if err := r.ParseForm(); err != nil {
+			return fmt.Errorf("%w: %w", errCreationForm, err)
+		}
+
+		formula := strings.TrimSpace(r.FormValue("custom_formula"))
+		if formula == "" {
+			formula = r.FormValue("formula")
+		}
+
+		if name := r.FormValue("catalogue"); name != "" {
+			o, err := occupation.Find(name)
+			if err != nil {
+				return fmt.Errorf("%w: %w", errCreationForm, err)
+			}
+
+			return d.ChooseOccupation(o, r.Form["skill"])
+		}
+
+		return d.SetOccupation(r.FormValue("occupation"), formula, r.Form["skill"])
+	case creation.StepSkills:
+		allocations := make(map[string]creation.Allocation)
+
+		for i, s := range d.Skills() {
+			occ, err := formInt(r, fmt.Sprintf("occ_%d", i))
+			if err != nil {
+				return fmt.Errorf("%w: %s occupation points", errCreationForm, s.FullName())
+			}
+
+			personal, err := formInt(r, fmt.Sprintf("pi_%d", i))
+			if err != nil {
+				return fmt.Errorf("%w: %s personal interest points", errCreationForm, s.FullName())
+			}
+
+			if occ != 0 || personal != 0 {
+				allocations[s.FullName()] = creation.Allocation{Occupation: occ, Personal: personal}
+			}
+		}
+
+		return d.SetAllocations(allocations)
+	default:
+		return creation.ErrWrongStep
+	}
+}
+
+func formCore(r *http.Request) (creation.Core, error) {
+	var c creation.Core
+
+	fields := []struct {
+		name string
+		dst  *int
+	}{
+		{name: "STR", dst: &c.STR},
+		{name: "CON", dst: &c.CON},
+		{name: "SIZ", dst: &c.SIZ},
+		{name: "DEX", dst: &c.DEX},
+		{name: "APP", dst: &c.APP},
+		{name: "INT", dst: &c.INT},
+		{name: "POW", dst: &c.POW},
+		{name: "EDU", dst: &c.EDU},
+	}
+
+	for _, f := range fields {
+		v, err := formInt(r, f.name)
+		if err != nil {
+			return creation.Core{}, fmt.Errorf("%w: %s", errCreationForm, f.name)
+		}
+
+		*f.dst = v
+	}
+
+	return c, nil
+}
+
+func creationFinish(w http.ResponseWriter, r *http.Request, tmpl *template.Template, db storage.Storage, d creation.Draft) {
+	d.PersonalDetails.Gender = strings.TrimSpace(r.FormValue("gender"))
+	d.PersonalDetails.Birthplace = strings.TrimSpace(r.FormValue("birthplace"))
+	d.PersonalDetails.Residence = strings.TrimSpace(r.FormValue("residence"))
+
+	inv, err := d.Finish(character.Backstory{
+		Description: r.FormValue("description"),
+		Ideology:    r.FormValue("ideology"),
+		People:      r.FormValue("people"),
+		Locations:   r.FormValue("locations"),
+		Possessions: r.FormValue("possessions"),
+		Traits:      r.FormValue("traits"),
+	})
+	if err != nil {
+		renderCreationStep(w, r, tmpl, http.StatusBadRequest, d, err.Error())
+
+		return
+	}
+
+	ch := storage.Character{
+		ID:           uuid.New().String(),
+		Version:      storage.InitialVersion,
+		Investigator: inv,
+		Owner:        viewerFromContext(r.Context()).ownerID(),
+	}
+
+	if err = db.Create(ch); err != nil {
+		logger.WithError(r.Context(), err).Error("Failed to save character to storage")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to save character to storage")
+
+		return
+	}
+
+	if err = db.Drafts().Delete(d.ID); err != nil {
+		logger.WithError(r.Context(), err).Error("Failed to delete draft")
+	}
+
+	logger.WithFields(r.Context(), logger.Fields{
+		"id":   ch.ID,
+		"name": ch.Name(),
+	}).Info("Character created with wizard")
+
+	http.Redirect(w, r, "/characters/"+ch.ID, http.StatusSeeOther)
+}
+
+func saveDraftAndRedirect(w http.ResponseWriter, r *http.Request, db storage.Storage, d creation.Draft) {
+	if err := db.Drafts().Update(d.ID, d); err != nil {
+		logger.WithError(r.Context(), err).Error("Failed to save draft to storage")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to save draft to storage")
+
+		return
+	}
+
+	http.Redirect(w, r, "/creation/"+d.ID, http.StatusSeeOther)
+}
+
+func creationTemplate() *template.Template {
+	html := string(assets.MustLoad("creation_step.gohtml"))
+
+	return template.Must(template.New("creation").Funcs(template.FuncMap{
+		"join": strings.Join,
+	}).Parse(html))
+}
+
+func renderCreationStep(w http.ResponseWriter, r *http.Request, tmpl *template.Template, status int, d creation.Draft, errMsg string) {
+	w.Header().Set("Content-Type", "text/html")
+
+	w.WriteHeader(status)
+
+	if err := tmpl.Execute(w, newCreationView(d, errMsg)); err != nil {
+		logger.WithError(r.Context(), err).Error("Failed to render creation step")
+	}
+}
+
+// draftFromPath loads creation draft by {id} path value.
+// It writes error response and returns false when draft could not be loaded.
+func draftFromPath(w http.ResponseWriter, r *http.Request, db storage.Storage) (creation.Draft, bool) {
+	id := r.PathValue("id")
+	if !isValidID(id) {
+		operationResponse(w, r, http.StatusBadRequest, "Wrong draft ID format")
+
+		return creation.Draft{}, false
+	}
+
+	d, err := db.Drafts().Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			operationResponse(w, r, http.StatusNotFound, "Draft not found")
+
+			return creation.Draft{}, false
+		}
+
+		logger.WithError(r.Context(), err).Error("Failed to get draft")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to get draft")
+
+		return creation.Draft{}, false
+	}
+
+	return d, true
+}
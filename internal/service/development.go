@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/service/assets"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+)
+
+func developmentRoutes(db storage.Storage) map[string]http.HandlerFunc {
+	tmpl := developmentTemplate()
+
+	return map[string]http.HandlerFunc{
+		makePathPattern(http.MethodPost, "/characters/{id}/ticks"):       characterTickHandler(db),
+		makePathPattern(http.MethodPost, "/characters/{id}/development"): characterDevelopmentHandler(db, tmpl),
+		makePathPattern(http.MethodPost, "/development"):                 partyDevelopmentHandler(db, tmpl),
+	}
+}
+
+type developmentResult struct {
+	Character   storage.Character
+	Development character.Development
+}
+
+func developmentTemplate() *template.Template {
+	html := string(assets.MustLoad("character_development.gohtml"))
+
+	return template.Must(template.New("development").Parse(html))
+}
+
+func characterTickHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := characterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		skill := r.FormValue("skill")
+
+		if _, err := ch.Investigator.Tick(skill); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, fmt.Sprintf("Could not tick skill %q: %v", skill, err))
+
+			return
+		}
+
+		if _, err := db.Update(ch); err != nil {
+			updateErrorResponse(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/characters/"+ch.ID, http.StatusSeeOther)
+	}
+}
+
+func characterDevelopmentHandler(db storage.Storage, tmpl *template.Template) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := characterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		res, err := developCharacter(db, ch)
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to run development phase")
+
+			updateErrorResponse(w, r, err)
+
+			return
+		}
+
+		renderDevelopment(w, r, tmpl, []developmentResult{res})
+	}
+}
+
+// partyDevelopmentHandler runs development phase for all characters passed in id form values.
+func partyDevelopmentHandler(db storage.Storage, tmpl *template.Template) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Failed to parse form")
+
+			return
+		}
+
+		ids := r.Form["id"]
+		if len(ids) == 0 {
+			operationResponse(w, r, http.StatusBadRequest, "No characters selected")
+
+			return
+		}
+
+		results := make([]developmentResult, 0, len(ids))
+
+		for _, id := range ids {
+			if !isValidID(id) {
+				operationResponse(w, r, http.StatusBadRequest, "Wrong character ID format")
+
+				return
+			}
+
+			ch, err := getCharacter(r, db, id)
+			if err != nil {
+				logger.WithError(r.Context(), err).Error("Failed to get character")
+
+				updateErrorResponse(w, r, err)
+
+				return
+			}
+
+			res, err := developCharacter(db, ch)
+			if err != nil {
+				logger.WithError(r.Context(), err).Error("Failed to run development phase")
+
+				updateErrorResponse(w, r, err)
+
+				return
+			}
+
+			results = append(results, res)
+		}
+
+		renderDevelopment(w, r, tmpl, results)
+	}
+}
+
+// developCharacter runs development phase for the character and stores the result with the log of changes.
+func developCharacter(db storage.Storage, ch storage.Character) (developmentResult, error) {
+	dev, err := ch.Investigator.Develop(roller)
+	if err != nil {
+		return developmentResult{}, err
+	}
+
+	now := time.Now().UTC()
+
+	for _, imp := range dev.Improvements {
+		ch.AddLog(now, storage.LogDevelopment, imp.String())
+	}
+
+	if len(dev.SanityRewards) != 0 {
+		ch.AddLog(now, storage.LogDevelopment, fmt.Sprintf("Sanity reward: %d → %d", dev.SanityFrom, dev.SanityTo))
+	}
+
+	updated, err := db.Update(ch)
+	if err != nil {
+		return developmentResult{}, err
+	}
+
+	return developmentResult{
+		Character:   updated,
+		Development: dev,
+	}, nil
+}
+
+// updateErrorResponse writes error response for failed character update.
+func updateErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		operationResponse(w, r, http.StatusNotFound, "Character not found")
+	case errors.Is(err, errForbidden):
+		operationResponse(w, r, http.StatusForbidden, "Access denied")
+	case errors.Is(err, storage.ErrConflict):
+		operationResponse(w, r, http.StatusConflict, "Character was modified by someone else, try again")
+	default:
+		logger.WithError(r.Context(), err).Error("Failed to update character")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to update character")
+	}
+}
+
+func renderDevelopment(w http.ResponseWriter, r *http.Request, tmpl *template.Template, results []developmentResult) {
+	w.Header().Set("Content-Type", "text/html")
+
+	if err := tmpl.Execute(w, results); err != nil {
+		logger.WithError(r.Context(), err).Error("Failed to render development results")
+	}
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var errNoVersion = errors.New("character version is not set")
+
+// characterETag returns strong entity tag for the character version.
+func characterETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// requestVersion returns character version the client has edited.
+// It is taken from If-Match header or falls back to version form value.
+func requestVersion(r *http.Request, formVersion string) (int, error) {
+	raw := formVersion
+
+	if im := r.Header.Get("If-Match"); im != "" {
+		raw = strings.Trim(strings.TrimPrefix(strings.TrimSpace(im), "W/"), `"`)
+	}
+
+	if raw == "" {
+		return 0, errNoVersion
+	}
+
+	return strconv.Atoi(raw)
+}
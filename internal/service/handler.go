@@ -6,20 +6,36 @@
 	"errors"
 	"fmt"
 	"html/template"
+	"maps"
+	"math/rand/v2"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/obalunenko/logger"
+	"github.com/obalunenko/version"
 
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/campaign"
 	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/metrics"
 	"github.com/obalunenko/cthulhu-mythos-tools/internal/service/assets"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/sheet"
 	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/validation"
 )
 
-func NewRouter() http.Handler {
+// NewRouter creates HTTP handler with all service routes backed by passed storage.
+// Access to the routes is controlled by authentication params.
+func NewRouter(db storage.Storage, authParams AuthParams) http.Handler {
 	mux := http.NewServeMux()
 
 	mw := []func(http.Handler) http.Handler{
+		authMiddleware(db, mux, authParams),
 		logRequestMiddleware,
 		requestIDMiddleware,
 		recoverMiddleware,
@@ -35,15 +51,39 @@ func NewRouter() http.Handler {
 	}
 
 	routes := map[string]http.HandlerFunc{
-		makePathPattern(http.MethodGet, "/"):                   indexHandler(),
-		makePathPattern(http.MethodGet, "/favicon.ico"):        faviconHandler(),
-		makePathPattern(http.MethodGet, "/characters/new"):     characterFormHandler(),
-		makePathPattern(http.MethodGet, "/characters/import"):  characterImportFormHandler(),
-		makePathPattern(http.MethodPost, "/characters/import"): characterImportHandler(),
-		makePathPattern(http.MethodPost, "/characters"):        characterCreateHandler(),
-		makePathPattern(http.MethodGet, "/characters"):         listCharactersHandler(),
-		makePathPattern(http.MethodGet, "/characters/{id}"):    characterDetailsHandler(),
-		makePathPattern(http.MethodDelete, "/characters/{id}"): characterDeleteHandler(),
+		makePathPattern(http.MethodGet, "/"):                           indexHandler(),
+		makePathPattern(http.MethodGet, "/favicon.ico"):                faviconHandler(),
+		makePathPattern(http.MethodGet, "/characters/new"):             characterFormHandler(),
+		makePathPattern(http.MethodGet, "/characters/import"):          characterImportFormHandler(),
+		makePathPattern(http.MethodPost, "/characters/import"):         characterImportHandler(db),
+		makePathPattern(http.MethodPost, "/characters"):                characterCreateHandler(db),
+		makePathPattern(http.MethodGet, "/characters"):                 listCharactersHandler(db),
+		makePathPattern(http.MethodGet, "/characters/{id}"):            characterDetailsHandler(db),
+		makePathPattern(http.MethodDelete, "/characters/{id}"):         characterDeleteHandler(db),
+		makePathPattern(http.MethodGet, "/characters/{id}/edit"):       characterEditFormHandler(db),
+		makePathPattern(http.MethodGet, "/characters/{id}/export"):     characterExportHandler(db),
+		makePathPattern(http.MethodGet, "/characters/{id}/sheet.pdf"):  characterSheetHandler(db),
+		makePathPattern(http.MethodGet, "/characters/{id}/validation"): characterValidationHandler(db),
+		makePathPattern(http.MethodPut, "/characters/{id}"):            characterUpdateHandler(db, false),
+		makePathPattern(http.MethodPatch, "/characters/{id}"):          characterUpdateHandler(db, true),
+		makePathPattern(http.MethodPost, "/characters/{id}/damage"):    characterDamageHandler(db),
+		makePathPattern(http.MethodPost, "/characters/{id}/rolls"):     characterRollHandler(db),
+	}
+
+	maps.Copy(routes, apiRoutes(db))
+	maps.Copy(routes, handoutRoutes(db))
+	maps.Copy(routes, creationRoutes(db))
+	maps.Copy(routes, developmentRoutes(db))
+	maps.Copy(routes, sanityRoutes(db))
+	maps.Copy(routes, luckRoutes(db))
+	maps.Copy(routes, combatRoutes(db))
+	maps.Copy(routes, chaseRoutes(db))
+	maps.Copy(routes, campaignRoutes(db))
+	maps.Copy(routes, healthRoutes(db))
+	maps.Copy(routes, metricsRoutes(db))
+
+	if authParams.Enabled {
+		maps.Copy(routes, authRoutes(db, authParams))
 	}
 
 	for pattern, handler := range routes {
@@ -54,7 +94,7 @@ func NewRouter() http.Handler {
 		mux.Handle(pattern, handler)
 	}
 
-	h := http.Handler(mux)
+	h := routeMiddleware(mux)
 
 	return mwApply(h)
 }
@@ -70,7 +110,7 @@ func indexHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html")
 
-		if err := homePageTmpl.Execute(w, nil); err != nil {
+		if err := homePageTmpl.Execute(w, viewerFromContext(r.Context()).User()); err != nil {
 			operationResponse(w, r, http.StatusInternalServerError, "Failed to render index page")
 
 			return
@@ -84,7 +124,8 @@ func faviconHandler() http.HandlerFunc {
 	}
 }
 
-var charactersDB = storage.NewInMemoryStorage()
+// roller counts rolled dice in metrics.
+var roller = dice.NewRoller(metrics.CountDice(rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())))) //nolint:gosec // dice do not need crypto.
 
 func characterFormHandler() http.HandlerFunc {
 	formHTML := string(assets.MustLoad("character_create.gohtml"))
@@ -116,10 +157,18 @@ func characterImportFormHandler() http.HandlerFunc {
 	}
 }
 
-func characterImportHandler() http.HandlerFunc {
+func characterImportHandler(db storage.Storage) http.HandlerFunc {
+	validationTmpl := characterValidationTemplate()
+
 	const maxFileSize = 10 << 20 // Максимальный размер файла 10MB
 
 	return func(w http.ResponseWriter, r *http.Request) {
+		var imported bool
+
+		defer func() {
+			metrics.CharacterImported(imported)
+		}()
+
 		if err := r.ParseMultipartForm(maxFileSize); err != nil {
 			operationResponse(w, r, http.StatusBadRequest, "Failed to parse form")
 
@@ -157,14 +206,25 @@ func characterImportHandler() http.HandlerFunc {
 			return
 		}
 
+		inv, report := importSheet(r.Context(), investigator.Investigator)
+
 		ch := storage.Character{
-			ID:         uuid.New().String(),
-			Name:       investigator.Investigator.PersonalDetails.Name,
-			Occupation: investigator.Investigator.PersonalDetails.Occupation,
-			Age:        investigator.Investigator.PersonalDetails.Age,
+			ID:           uuid.New().String(),
+			Version:      storage.InitialVersion,
+			Investigator: inv,
+			Owner:        viewerFromContext(r.Context()).ownerID(),
 		}
 
-		if err = charactersDB.Create(ch); err != nil {
+		for _, f := range report {
+			logger.WithFields(r.Context(), logger.Fields{
+				"id":       ch.ID,
+				"severity": f.Severity,
+				"path":     f.Path,
+				"message":  f.Message,
+			}).Warn("Imported sheet validation finding")
+		}
+
+		if err = db.Create(ch); err != nil {
 			operationResponse(w, r, http.StatusInternalServerError, "Failed to save character to storage")
 
 			logger.WithError(r.Context(), err).Error("Failed to save character to storage")
@@ -172,31 +232,41 @@ func characterImportHandler() http.HandlerFunc {
 			return
 		}
 
-		resp := fmt.Sprintf("Character %s created!", ch.ID)
+		imported = true
 
-		operationResponse(w, r, http.StatusCreated, resp)
+		renderValidation(w, r, validationTmpl, http.StatusCreated, validationView{
+			Character: ch,
+			Message:   fmt.Sprintf("Character %s created!", ch.ID),
+			Report:    report,
+		})
 	}
 }
 
-func characterCreateHandler() http.HandlerFunc {
+func characterCreateHandler(db storage.Storage) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Обработка данных формы
 		details := storage.Character{
-			ID:         uuid.New().String(),
-			Name:       r.FormValue("name"),
-			Occupation: r.FormValue("occupation"),
-			Age:        r.FormValue("age"),
+			ID:      uuid.New().String(),
+			Version: storage.InitialVersion,
+			Investigator: character.InvestigatorClass{
+				PersonalDetails: character.PersonalDetails{
+					Name:       r.FormValue("name"),
+					Occupation: r.FormValue("occupation"),
+					Age:        r.FormValue("age"),
+				},
+			},
+			Owner: viewerFromContext(r.Context()).ownerID(),
 		}
 
 		// Здесь можно добавить логику для сохранения данных персонажа
 		logger.WithFields(r.Context(), logger.Fields{
 			"id":         details.ID,
-			"name":       details.Name,
-			"occupation": details.Occupation,
-			"age":        details.Age,
+			"name":       details.Name(),
+			"occupation": details.Occupation(),
+			"age":        details.Age(),
 		}).Info("Create character")
 
-		if err := charactersDB.Create(details); err != nil {
+		if err := db.Create(details); err != nil {
 			logger.WithError(r.Context(), err).Error("Failed to save character to storage")
 
 			operationResponse(w, r, http.StatusInternalServerError, "Failed to save character to storage")
@@ -210,13 +280,13 @@ func characterCreateHandler() http.HandlerFunc {
 	}
 }
 
-func listCharactersHandler() http.HandlerFunc {
+func listCharactersHandler(db storage.Storage) http.HandlerFunc {
 	listHTML := string(assets.MustLoad("characters.gohtml"))
 	listTmpl := template.Must(template.New("list").Parse(listHTML))
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html")
 
-		list, err := charactersDB.List()
+		list, err := db.List()
 		if err != nil {
 			logger.WithError(r.Context(), err).Error("Failed to get characters list")
 
@@ -225,7 +295,45 @@ func listCharactersHandler() http.HandlerFunc {
 			return
 		}
 
-		if err = listTmpl.Execute(w, list); err != nil {
+		v := viewerFromContext(r.Context())
+
+		campaignID := r.URL.Query().Get("campaign")
+
+		list, err = charactersInCampaign(db, v.accessible(list), campaignID)
+		if err != nil {
+			switch {
+			case errors.Is(err, errCampaignID):
+				operationResponse(w, r, http.StatusBadRequest, "Wrong campaign ID format")
+			case errors.Is(err, storage.ErrNotFound):
+				operationResponse(w, r, http.StatusNotFound, "Campaign not found")
+			default:
+				logger.WithError(r.Context(), err).Error("Failed to get campaign")
+
+				operationResponse(w, r, http.StatusInternalServerError, "Failed to get campaign")
+			}
+
+			return
+		}
+
+		campaigns, err := listCampaigns(db, v)
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get campaigns list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get campaigns list")
+
+			return
+		}
+
+		err = listTmpl.Execute(w, struct {
+			Characters []storage.Character
+			Campaigns  []campaign.Campaign
+			Campaign   string
+		}{
+			Characters: list,
+			Campaigns:  campaigns,
+			Campaign:   campaignID,
+		})
+		if err != nil {
 			logger.WithError(r.Context(), err).Error("Failed to render characters list")
 
 			operationResponse(w, r, http.StatusInternalServerError, "Failed to render characters list")
@@ -235,7 +343,7 @@ func listCharactersHandler() http.HandlerFunc {
 	}
 }
 
-func characterDetailsHandler() http.HandlerFunc {
+func characterDetailsHandler(db storage.Storage) http.HandlerFunc {
 	detailsHTML := string(assets.MustLoad("character_details.gohtml"))
 	detailsTmpl := template.Must(template.New("details").Parse(detailsHTML))
 
@@ -252,7 +360,7 @@ func characterDetailsHandler() http.HandlerFunc {
 			return
 		}
 
-		ch, err := charactersDB.Get(id)
+		ch, err := db.Get(id)
 		if err != nil {
 			var (
 				status int
@@ -272,7 +380,21 @@ func characterDetailsHandler() http.HandlerFunc {
 			return
 		}
 
-		if err := detailsTmpl.Execute(w, ch); err != nil {
+		view := characterDetailsView{
+			Character: ch,
+		}
+
+		if ch.Investigator.Characteristics != (character.Characteristics{}) {
+			if stats, err := ch.Investigator.Characteristics.Stats(); err == nil {
+				view.Stats = &stats
+			}
+
+			view.Findings = validation.Validate(ch.Investigator)
+		}
+
+		w.Header().Set("ETag", characterETag(ch.Version))
+
+		if err := detailsTmpl.Execute(w, view); err != nil {
 			logger.WithError(r.Context(), err).Error("Failed to render character details")
 
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -280,7 +402,13 @@ func characterDetailsHandler() http.HandlerFunc {
 	}
 }
 
-func characterDeleteHandler() http.HandlerFunc {
+type characterDetailsView struct {
+	storage.Character
+	Stats    *character.Stats
+	Findings validation.Report
+}
+
+func characterDeleteHandler(db storage.Storage) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
 			status int
@@ -300,7 +428,7 @@ func characterDeleteHandler() http.HandlerFunc {
 			return
 		}
 
-		if err := charactersDB.Delete(id); err != nil {
+		if err := deleteCharacter(db, id); err != nil {
 			if errors.Is(err, storage.ErrNotFound) {
 				status = http.StatusNotFound
 				resp = "Character not found"
@@ -308,6 +436,8 @@ func characterDeleteHandler() http.HandlerFunc {
 				return
 			}
 
+			logger.WithError(r.Context(), err).Error("Failed to delete character")
+
 			status = http.StatusInternalServerError
 			resp = "Failed to delete character"
 
@@ -319,6 +449,387 @@ func characterDeleteHandler() http.HandlerFunc {
 	}
 }
 
+func characterEditFormHandler(db storage.Storage) http.HandlerFunc {
+	formHTML := string(assets.MustLoad("character_edit.gohtml"))
+	formTmpl := template.Must(template.New("edit").Parse(formHTML))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := characterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("ETag", characterETag(ch.Version))
+
+		if err := formTmpl.Execute(w, ch); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render form")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to render form")
+		}
+	}
+}
+
+// characterUpdateHandler handles PUT (replace all editable fields) and PATCH (only passed fields) requests.
+func characterUpdateHandler(db storage.Storage, partial bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Failed to parse form")
+
+			return
+		}
+
+		version, err := requestVersion(r, r.PostFormValue("version"))
+		if err != nil {
+			if errors.Is(err, errNoVersion) {
+				operationResponse(w, r, http.StatusPreconditionRequired, "Character version is required")
+
+				return
+			}
+
+			operationResponse(w, r, http.StatusBadRequest, "Wrong character version")
+
+			return
+		}
+
+		ch, ok := characterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		if ch.Version != version {
+			operationResponse(w, r, http.StatusPreconditionFailed, "Character was modified by someone else")
+
+			return
+		}
+
+		if err = applyPersonalDetailsForm(r.PostForm, &ch.Investigator.PersonalDetails, partial); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		updated, err := db.Update(ch)
+		if err != nil {
+			if errors.Is(err, storage.ErrConflict) {
+				operationResponse(w, r, http.StatusPreconditionFailed, "Character was modified by someone else")
+
+				return
+			}
+
+			if errors.Is(err, storage.ErrNotFound) {
+				operationResponse(w, r, http.StatusNotFound, "Character not found")
+
+				return
+			}
+
+			logger.WithError(r.Context(), err).Error("Failed to update character")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to update character")
+
+			return
+		}
+
+		logger.WithFields(r.Context(), logger.Fields{
+			"id":      updated.ID,
+			"version": updated.Version,
+		}).Info("Character updated")
+
+		w.Header().Set("ETag", characterETag(updated.Version))
+
+		operationResponse(w, r, http.StatusOK, fmt.Sprintf("Character %s updated!", updated.ID))
+	}
+}
+
+// applyPersonalDetailsForm sets personal details from form values.
+// When partial is false, name, occupation and age are required and missing optional fields are cleared.
+func applyPersonalDetailsForm(form url.Values, d *character.PersonalDetails, partial bool) error {
+	fields := []struct {
+		key      string
+		dst      *string
+		required bool
+	}{
+		{key: "name", dst: &d.Name, required: true},
+		{key: "occupation", dst: &d.Occupation, required: true},
+		{key: "age", dst: &d.Age, required: true},
+		{key: "gender", dst: &d.Gender},
+		{key: "birthplace", dst: &d.Birthplace},
+		{key: "residence", dst: &d.Residence},
+	}
+
+	for _, f := range fields {
+		_, ok := form[f.key]
+		if partial && !ok {
+			continue
+		}
+
+		v := strings.TrimSpace(form.Get(f.key))
+		if f.required && v == "" {
+			return fmt.Errorf("field %q is required", f.key)
+		}
+
+		*f.dst = v
+	}
+
+	return nil
+}
+
+const exportFormatDholesHouse = "dholeshouse"
+
+func characterExportHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = exportFormatDholesHouse
+		}
+
+		if format != exportFormatDholesHouse {
+			operationResponse(w, r, http.StatusBadRequest, fmt.Sprintf("Unsupported export format %q", format))
+
+			return
+		}
+
+		ch, ok := characterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		creator := fmt.Sprintf("%s %s", version.GetAppName(), version.GetVersion())
+
+		exported := ch.Investigator.Export(creator, time.Now())
+
+		data, err := exported.Marshal()
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to marshal investigator")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to export character")
+
+			return
+		}
+
+		filename := ch.Name()
+		if filename == "" {
+			filename = ch.ID
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": filename + ".json",
+		}))
+
+		if _, err = w.Write(data); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to write exported character")
+		}
+	}
+}
+
+func characterSheetHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := characterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		var buf bytes.Buffer
+
+		if err := sheet.Render(&buf, ch.Investigator); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render character sheet")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to render character sheet")
+
+			return
+		}
+
+		filename := ch.Name()
+		if filename == "" {
+			filename = ch.ID
+		}
+
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{
+			"filename": filename + ".pdf",
+		}))
+
+		if _, err := buf.WriteTo(w); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to write character sheet")
+		}
+	}
+}
+
+func characterDamageHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := characterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		idx, err := strconv.Atoi(r.FormValue("weapon"))
+		if err != nil || idx < 0 || idx >= len(ch.Investigator.Weapons.Weapon) {
+			operationResponse(w, r, http.StatusBadRequest, "Wrong weapon")
+
+			return
+		}
+
+		weapon := ch.Investigator.Weapons.Weapon[idx]
+
+		res, err := weapon.RollDamage(roller, ch.Investigator.DamageBonus())
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to roll weapon damage")
+
+			operationResponse(w, r, http.StatusBadRequest, fmt.Sprintf("Failed to roll damage %q", weapon.Damage))
+
+			return
+		}
+
+		operationResponse(w, r, http.StatusOK, fmt.Sprintf("%s: %s", weapon.Name, res.String()))
+	}
+}
+
+func characterRollHandler(db storage.Storage) http.HandlerFunc {
+	rollHTML := string(assets.MustLoad("character_roll.gohtml"))
+	rollTmpl := template.Must(template.New("roll").Parse(rollHTML))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := characterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		check := r.FormValue("skill")
+
+		target, err := ch.Investigator.CheckTarget(check)
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get check target")
+
+			operationResponse(w, r, http.StatusBadRequest, fmt.Sprintf("Unknown skill %q", check))
+
+			return
+		}
+
+		bonus, err := formInt(r, "bonus")
+		if err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Wrong bonus dice number")
+
+			return
+		}
+
+		penalty, err := formInt(r, "penalty")
+		if err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Wrong penalty dice number")
+
+			return
+		}
+
+		res, err := roller.Check(target, bonus, penalty)
+		if err != nil {
+			operationResponse(w, r, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		logger.WithFields(r.Context(), logger.Fields{
+			"id":     ch.ID,
+			"check":  check,
+			"target": res.Target,
+			"roll":   res.Roll,
+			"level":  res.Level.String(),
+		}).Info("Skill check rolled")
+
+		ch.LastCheck = &storage.Check{
+			ID:     uuid.New().String(),
+			Name:   check,
+			Target: res.Target,
+			Roll:   res.Roll,
+		}
+
+		ticked := tickOnSuccess(&ch, check, res)
+		luck := luckOptions(ch.Investigator, check, res)
+
+		// Failing to store the check is logged and does not fail the roll, Luck could not be spent on it then.
+		if updated, err := db.Update(ch); err != nil {
+			logger.WithError(r.Context(), err).Warn("Failed to store skill check")
+
+			ticked = false
+			luck = nil
+		} else {
+			ch = updated
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+
+		err = rollTmpl.Execute(w, struct {
+			Character storage.Character
+			Check     string
+			Hard      int
+			Extreme   int
+			Result    dice.CheckResult
+			Ticked    bool
+			Luck      []luckOption
+		}{
+			Character: ch,
+			Check:     check,
+			Hard:      target / 2,
+			Extreme:   target / 5,
+			Result:    res,
+			Ticked:    ticked,
+			Luck:      luck,
+		})
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render roll result")
+		}
+	}
+}
+
+// tickOnSuccess marks successfully rolled skill for the development phase.
+func tickOnSuccess(ch *storage.Character, check string, res dice.CheckResult) bool {
+	if !res.Level.IsSuccess() {
+		return false
+	}
+
+	// Characteristics and not improvable skills could not be ticked, that's fine.
+	ticked, err := ch.Investigator.Tick(check)
+
+	return err == nil && ticked
+}
+
+// formInt parses optional integer form value, empty value is zero.
+func formInt(r *http.Request, key string) (int, error) {
+	v := r.FormValue(key)
+	if v == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(v)
+}
+
+// characterFromPath loads character by {id} path value.
+// It writes error response and returns false when character could not be loaded.
+func characterFromPath(w http.ResponseWriter, r *http.Request, db storage.Storage) (storage.Character, bool) {
+	id := r.PathValue("id")
+	if !isValidID(id) {
+		operationResponse(w, r, http.StatusBadRequest, "Wrong character ID format")
+
+		return storage.Character{}, false
+	}
+
+	ch, err := db.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			operationResponse(w, r, http.StatusNotFound, "Character not found")
+
+			return storage.Character{}, false
+		}
+
+		logger.WithError(r.Context(), err).Error("Failed to get character")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to get character details")
+
+		return storage.Character{}, false
+	}
+
+	return ch, true
+}
+
 func isValidID(id string) bool {
 	_, err := uuid.Parse(id)
 	if err != nil {
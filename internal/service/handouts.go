@@ -0,0 +1,234 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/handouts"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/service/assets"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+)
+
+func handoutRoutes(db storage.Storage) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		makePathPattern(http.MethodGet, "/handouts"):                  listHandoutsHandler(db),
+		makePathPattern(http.MethodGet, "/handouts/new"):              handoutFormHandler(),
+		makePathPattern(http.MethodPost, "/handouts"):                 handoutCreateHandler(db),
+		makePathPattern(http.MethodGet, "/handouts/{id}"):             handoutPreviewHandler(db),
+		makePathPattern(http.MethodGet, "/handouts/{id}/handout.pdf"): handoutFileHandler(db, "application/pdf", "pdf", handouts.RenderPDF),
+		makePathPattern(http.MethodGet, "/handouts/{id}/handout.png"): handoutFileHandler(db, "image/png", "png", handouts.RenderPNG),
+	}
+}
+
+func listHandoutsHandler(db storage.Storage) http.HandlerFunc {
+	listHTML := string(assets.MustLoad("handouts.gohtml"))
+	listTmpl := template.Must(template.New("handouts").Parse(listHTML))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+
+		list, err := db.Handouts().List()
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to get handouts list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to get handouts list")
+
+			return
+		}
+
+		v := viewerFromContext(r.Context())
+
+		list = slices.DeleteFunc(list, func(h handouts.Handout) bool {
+			return !v.canKeep(h.KeeperID)
+		})
+
+		slices.SortFunc(list, func(a, b handouts.Handout) int {
+			return b.CreatedAt.Compare(a.CreatedAt)
+		})
+
+		if err = listTmpl.Execute(w, list); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render handouts list")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to render handouts list")
+		}
+	}
+}
+
+func handoutFormHandler() http.HandlerFunc {
+	formHTML := string(assets.MustLoad("handout_create.gohtml"))
+	formTmpl := template.Must(template.New("form").Parse(formHTML))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+
+		if err := formTmpl.Execute(w, nil); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render form")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to render form")
+		}
+	}
+}
+
+// maxHandoutFormSize fits the longest handout body URL encoded.
+const maxHandoutFormSize = 128 << 10
+
+func handoutCreateHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxHandoutFormSize)
+
+		if err := r.ParseForm(); err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				operationResponse(w, r, http.StatusRequestEntityTooLarge, "Handout is too large")
+
+				return
+			}
+
+			operationResponse(w, r, http.StatusBadRequest, "Failed to parse form")
+
+			return
+		}
+
+		kind, err := handouts.ParseKind(r.FormValue("kind"))
+		if err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Wrong handout kind")
+
+			return
+		}
+
+		h := handouts.Handout{
+			ID:        uuid.New().String(),
+			Kind:      kind,
+			Title:     strings.TrimSpace(r.FormValue("title")),
+			Date:      strings.TrimSpace(r.FormValue("date")),
+			Sender:    strings.TrimSpace(r.FormValue("sender")),
+			Recipient: strings.TrimSpace(r.FormValue("recipient")),
+			Place:     strings.TrimSpace(r.FormValue("place")),
+			Headline:  strings.TrimSpace(r.FormValue("headline")),
+			Body:      r.FormValue("body"),
+			CreatedAt: time.Now().UTC(),
+			KeeperID:  viewerFromContext(r.Context()).ownerID(),
+		}
+
+		if err = h.Validate(); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		if err = db.Handouts().Create(h.ID, h); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to save handout to storage")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to save handout to storage")
+
+			return
+		}
+
+		logger.WithFields(r.Context(), logger.Fields{
+			"id":   h.ID,
+			"kind": h.Kind,
+		}).Info("Create handout")
+
+		http.Redirect(w, r, "/handouts/"+h.ID, http.StatusSeeOther)
+	}
+}
+
+func handoutPreviewHandler(db storage.Storage) http.HandlerFunc {
+	tmpls := make(map[handouts.Kind]*template.Template, len(handouts.Kinds()))
+
+	for _, k := range handouts.Kinds() {
+		name := fmt.Sprintf("handout_%s.gohtml", k)
+
+		tmpls[k] = template.Must(template.New(name).Parse(string(assets.MustLoad(name))))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		h, ok := handoutFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+
+		if err := tmpls[h.Kind].Execute(w, h); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render handout")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to render handout")
+		}
+	}
+}
+
+type handoutRenderer func(w io.Writer, h handouts.Handout) error
+
+func handoutFileHandler(db storage.Storage, contentType, ext string, render handoutRenderer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h, ok := handoutFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		var buf bytes.Buffer
+
+		if err := render(&buf, h); err != nil {
+			if errors.Is(err, handouts.ErrTooLarge) {
+				operationResponse(w, r, http.StatusUnprocessableEntity, "Handout is too large to render")
+
+				return
+			}
+
+			logger.WithError(r.Context(), err).Error("Failed to render handout")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to render handout")
+
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{
+			"filename": fmt.Sprintf("%s-%s.%s", h.Kind, h.ID, ext),
+		}))
+
+		if _, err := buf.WriteTo(w); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to write handout")
+		}
+	}
+}
+
+// handoutFromPath loads handout by {id} path value.
+// It writes error response and returns false when handout could not be loaded.
+func handoutFromPath(w http.ResponseWriter, r *http.Request, db storage.Storage) (handouts.Handout, bool) {
+	id := r.PathValue("id")
+	if !isValidID(id) {
+		operationResponse(w, r, http.StatusBadRequest, "Wrong handout ID format")
+
+		return handouts.Handout{}, false
+	}
+
+	h, err := db.Handouts().Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			operationResponse(w, r, http.StatusNotFound, "Handout not found")
+
+			return handouts.Handout{}, false
+		}
+
+		logger.WithError(r.Context(), err).Error("Failed to get handout")
+
+		operationResponse(w, r, http.StatusInternalServerError, "Failed to get handout")
+
+		return handouts.Handout{}, false
+	}
+
+	return h, true
+}
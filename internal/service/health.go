@@ -0,0 +1,46 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+)
+
+func healthRoutes(db storage.Storage) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		makePathPattern(http.MethodGet, "/healthz"): healthHandler(),
+		makePathPattern(http.MethodGet, "/readyz"):  readyHandler(db),
+	}
+}
+
+type healthStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// healthHandler reports that the process is alive and serves requests.
+func healthHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		apiResponse(w, r, http.StatusOK, healthStatus{Status: "ok"})
+	}
+}
+
+// readyHandler reports whether the service can handle requests, it checks the storage backend.
+func readyHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := db.Ping(); err != nil {
+			logger.WithError(r.Context(), err).Error("Storage is not available")
+
+			apiResponse(w, r, http.StatusServiceUnavailable, healthStatus{
+				Status: "unavailable",
+				Error:  "storage is not available",
+			})
+
+			return
+		}
+
+		apiResponse(w, r, http.StatusOK, healthStatus{Status: "ok"})
+	}
+}
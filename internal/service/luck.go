@@ -0,0 +1,192 @@
+package service
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/dice"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/service/assets"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+)
+
+func luckRoutes(db storage.Storage) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		makePathPattern(http.MethodPost, "/characters/{id}/luck"): characterSpendLuckHandler(db),
+		makePathPattern(http.MethodPost, "/luck"):                 partyLuckRecoveryHandler(db),
+	}
+}
+
+// luckOption is a success level that could be bought with Luck after the roll.
+type luckOption struct {
+	Level dice.SuccessLevel
+	Cost  int
+}
+
+// luckOptions returns success levels affordable for the investigator after the check.
+func luckOptions(inv character.InvestigatorClass, check string, res dice.CheckResult) []luckOption {
+	var opts []luckOption
+
+	for _, level := range []dice.SuccessLevel{dice.RegularSuccess, dice.HardSuccess, dice.ExtremeSuccess} {
+		// Spend is made on a copy of the sheet, only its cost is needed here.
+		probe := inv
+
+		spend, err := probe.SpendLuck(check, res, level)
+		if err != nil {
+			continue
+		}
+
+		opts = append(opts, luckOption{
+			Level: level,
+			Cost:  spend.Cost,
+		})
+	}
+
+	return opts
+}
+
+// characterSpendLuckHandler spends Luck to adjust the roll of the last check of the character.
+// Check is taken from the storage and used once, so the player could not send any roll.
+// Skill is not ticked for success bought with Luck.
+func characterSpendLuckHandler(db storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := characterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		last := ch.LastCheck
+		if last == nil || last.ID != r.FormValue("check") {
+			operationResponse(w, r, http.StatusBadRequest, "Luck could be spent only once and only on the last check")
+
+			return
+		}
+
+		level, err := strconv.Atoi(r.FormValue("level"))
+		if err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Wrong success level")
+
+			return
+		}
+
+		check := last.Name
+
+		res := dice.CheckResult{
+			Target: last.Target,
+			Roll:   last.Roll,
+			Level:  dice.Resolve(last.Roll, last.Target),
+		}
+
+		spend, err := ch.Investigator.SpendLuck(check, res, dice.SuccessLevel(level))
+		if err != nil {
+			if errors.Is(err, character.ErrLuckNotAllowed) || errors.Is(err, character.ErrNotEnoughLuck) {
+				operationResponse(w, r, http.StatusBadRequest, err.Error())
+
+				return
+			}
+
+			logger.WithError(r.Context(), err).Error("Failed to spend luck")
+
+			operationResponse(w, r, http.StatusInternalServerError, "Failed to spend luck")
+
+			return
+		}
+
+		ch.LastCheck = nil
+		ch.AddLog(time.Now().UTC(), storage.LogLuck, spend.String())
+
+		if _, err = db.Update(ch); err != nil {
+			updateErrorResponse(w, r, err)
+
+			return
+		}
+
+		logger.WithFields(r.Context(), logger.Fields{
+			"id":    ch.ID,
+			"check": check,
+			"cost":  spend.Cost,
+			"level": spend.Result.Level.String(),
+		}).Info("Luck spent")
+
+		operationResponse(w, r, http.StatusOK, spend.String())
+	}
+}
+
+type luckRecoveryResult struct {
+	Character storage.Character
+	Recovery  character.LuckRecovery
+}
+
+// partyLuckRecoveryHandler makes the end of session Luck recovery roll for all characters passed in id form values.
+func partyLuckRecoveryHandler(db storage.Storage) http.HandlerFunc {
+	html := string(assets.MustLoad("character_luck.gohtml"))
+	tmpl := template.Must(template.New("luck").Parse(html))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, "Failed to parse form")
+
+			return
+		}
+
+		ids := r.Form["id"]
+		if len(ids) == 0 {
+			operationResponse(w, r, http.StatusBadRequest, "No characters selected")
+
+			return
+		}
+
+		results := make([]luckRecoveryResult, 0, len(ids))
+
+		for _, id := range ids {
+			if !isValidID(id) {
+				operationResponse(w, r, http.StatusBadRequest, "Wrong character ID format")
+
+				return
+			}
+
+			ch, err := getCharacter(r, db, id)
+			if err != nil {
+				logger.WithError(r.Context(), err).Error("Failed to get character")
+
+				updateErrorResponse(w, r, err)
+
+				return
+			}
+
+			rec, err := ch.Investigator.RecoverLuck(roller)
+			if err != nil {
+				logger.WithError(r.Context(), err).Error("Failed to recover luck")
+
+				updateErrorResponse(w, r, err)
+
+				return
+			}
+
+			ch.AddLog(time.Now().UTC(), storage.LogLuck, rec.String())
+
+			updated, err := db.Update(ch)
+			if err != nil {
+				updateErrorResponse(w, r, err)
+
+				return
+			}
+
+			results = append(results, luckRecoveryResult{
+				Character: updated,
+				Recovery:  rec,
+			})
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+
+		if err := tmpl.Execute(w, results); err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render luck recovery results")
+		}
+	}
+}
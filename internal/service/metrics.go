@@ -0,0 +1,16 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/metrics"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+)
+
+func metricsRoutes(db storage.Storage) map[string]http.HandlerFunc {
+	h := metrics.Handler(db.Count)
+
+	return map[string]http.HandlerFunc{
+		makePathPattern(http.MethodGet, "/metrics"): h.ServeHTTP,
+	}
+}
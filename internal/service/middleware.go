@@ -3,10 +3,13 @@
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	log "github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/metrics"
 )
 
 func loggerMiddleware(next http.Handler) http.Handler {
@@ -31,10 +34,15 @@ func logRequestMiddleware(next http.Handler) http.Handler {
 
 		next.ServeHTTP(rw, r)
 
+		latency := time.Since(now)
+
+		metrics.ObserveRequest(r.Method, rw.route, rw.status, latency)
+
 		l := log.WithFields(ctx, log.Fields{
 			"method":  r.Method,
 			"url":     r.URL.String(),
-			"latency": time.Since(now).String(),
+			"route":   rw.route,
+			"latency": latency.String(),
 			"status":  rw.status,
 		})
 
@@ -90,6 +98,8 @@ func newRequestID() string {
 type responseWriter struct {
 	http.ResponseWriter
 	status int
+	// route is a path pattern of the matched route, set by routeMiddleware.
+	route string
 }
 
 func newResponseWriter(w http.ResponseWriter) *responseWriter {
@@ -105,6 +115,23 @@ func (rw *responseWriter) WriteHeader(status int) {
 	rw.ResponseWriter.WriteHeader(status)
 }
 
+// routeMiddleware wraps mux to report path pattern of the matched route to logRequestMiddleware.
+// Mux sets the pattern on the request it serves, which is not visible to outer middlewares.
+func routeMiddleware(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r)
+
+		setRoute(w, r.Pattern)
+	})
+}
+
+// setRoute reports path of the route pattern to logRequestMiddleware.
+func setRoute(w http.ResponseWriter, pattern string) {
+	if rw, ok := w.(*responseWriter); ok {
+		_, rw.route, _ = strings.Cut(pattern, " ")
+	}
+}
+
 func recoverMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
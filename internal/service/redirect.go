@@ -0,0 +1,26 @@
+package service
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+const defaultHTTPSPort = "443"
+
+// NewHTTPSRedirect creates HTTP handler redirecting all requests to the same host and path over HTTPS on the port.
+func NewHTTPSRedirect(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			// Host header without port, IPv6 address could be in brackets.
+			host = strings.Trim(r.Host, "[]")
+		}
+
+		if httpsPort != defaultHTTPSPort {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
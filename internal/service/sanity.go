@@ -0,0 +1,159 @@
+package service
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/sanity"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/service/assets"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+)
+
+func sanityRoutes(db storage.Storage) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		makePathPattern(http.MethodPost, "/characters/{id}/sanity"):         characterSanityHandler(db),
+		makePathPattern(http.MethodPost, "/characters/{id}/sanity/day"):     characterSanityStateHandler(db, startDay),
+		makePathPattern(http.MethodPost, "/characters/{id}/sanity/recover"): characterSanityStateHandler(db, recoverSanity),
+		makePathPattern(http.MethodPost, "/characters/{id}/mythos"):         characterSanityStateHandler(db, gainMythos),
+	}
+}
+
+func characterSanityHandler(db storage.Storage) http.HandlerFunc {
+	html := string(assets.MustLoad("character_sanity.gohtml"))
+	tmpl := template.Must(template.New("sanity").Parse(html))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := characterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		loss, err := sanity.ParseLoss(r.FormValue("loss"))
+		if err != nil {
+			operationResponse(w, r, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		mode, err := sanity.ParseBoutMode(r.FormValue("mode"))
+		if err != nil {
+			operationResponse(w, r, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		out, err := sanity.Roll(roller, &ch.Investigator, &ch.Sanity, loss, mode)
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to roll sanity")
+
+			operationResponse(w, r, http.StatusBadRequest, fmt.Sprintf("Failed to roll sanity: %v", err))
+
+			return
+		}
+
+		now := time.Now().UTC()
+
+		ch.AddLog(now, storage.LogSanity, out.String())
+
+		if out.Bout != nil {
+			ch.AddLog(now, storage.LogSanity, "Bout of madness: "+out.Bout.String())
+		}
+
+		updated, err := db.Update(ch)
+		if err != nil {
+			updateErrorResponse(w, r, err)
+
+			return
+		}
+
+		logger.WithFields(r.Context(), logger.Fields{
+			"id":   ch.ID,
+			"loss": loss.String(),
+			"from": out.From,
+			"to":   out.To,
+		}).Info("Sanity rolled")
+
+		w.Header().Set("Content-Type", "text/html")
+
+		err = tmpl.Execute(w, struct {
+			Character storage.Character
+			Outcome   sanity.Outcome
+		}{
+			Character: updated,
+			Outcome:   out,
+		})
+		if err != nil {
+			logger.WithError(r.Context(), err).Error("Failed to render sanity roll")
+		}
+	}
+}
+
+// startDay starts a new game day for the character Sanity tracking.
+func startDay(_ *http.Request, ch *storage.Character) error {
+	v, err := character.ParseNumber(ch.Investigator.Characteristics.Sanity)
+	if err != nil {
+		return err
+	}
+
+	ch.Sanity.StartDay(v)
+
+	ch.AddLog(time.Now().UTC(), storage.LogSanity, fmt.Sprintf("New day started with Sanity %d", v))
+
+	return nil
+}
+
+// recoverSanity ends temporary and indefinite insanity of the character.
+func recoverSanity(_ *http.Request, ch *storage.Character) error {
+	ch.Sanity.Recover()
+
+	ch.AddLog(time.Now().UTC(), storage.LogSanity, "Recovered from insanity")
+
+	return nil
+}
+
+// characterSanityStateHandler applies change to the character Sanity state and redirects back to the character.
+func characterSanityStateHandler(db storage.Storage, change func(r *http.Request, ch *storage.Character) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := characterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		if err := change(r, &ch); err != nil {
+			operationResponse(w, r, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		if _, err := db.Update(ch); err != nil {
+			updateErrorResponse(w, r, err)
+
+			return
+		}
+
+		http.Redirect(w, r, "/characters/"+ch.ID, http.StatusSeeOther)
+	}
+}
+
+// gainMythos raises the character Cthulhu Mythos by points form value, lowering maximum Sanity.
+func gainMythos(r *http.Request, ch *storage.Character) error {
+	points, err := strconv.Atoi(r.FormValue("points"))
+	if err != nil {
+		return fmt.Errorf("%w: points should be a number", sanity.ErrInvalidMythos)
+	}
+
+	change, err := sanity.GainMythos(&ch.Investigator, points)
+	if err != nil {
+		return err
+	}
+
+	ch.AddLog(time.Now().UTC(), storage.LogSanity, change.String())
+
+	return nil
+}
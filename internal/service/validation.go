@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/obalunenko/logger"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/service/assets"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/skills"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/storage"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/validation"
+)
+
+type validationView struct {
+	storage.Character
+	Message string
+	Report  validation.Report
+}
+
+func (v validationView) Errors() int {
+	return v.Report.Count(validation.SeverityError)
+}
+
+func (v validationView) Warnings() int {
+	return v.Report.Count(validation.SeverityWarning)
+}
+
+func characterValidationTemplate() *template.Template {
+	html := string(assets.MustLoad("character_validation.gohtml"))
+
+	return template.Must(template.New("character_validation").Parse(html))
+}
+
+func characterValidationHandler(db storage.Storage) http.HandlerFunc {
+	tmpl := characterValidationTemplate()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := characterFromPath(w, r, db)
+		if !ok {
+			return
+		}
+
+		renderValidation(w, r, tmpl, http.StatusOK, validationView{
+			Character: ch,
+			Report:    validation.Validate(ch.Investigator),
+		})
+	}
+}
+
+func renderValidation(w http.ResponseWriter, r *http.Request, tmpl *template.Template, status int, view validationView) {
+	w.Header().Set("Content-Type", "text/html")
+
+	w.WriteHeader(status)
+
+	if err := tmpl.Execute(w, view); err != nil {
+		logger.WithError(r.Context(), err).Error("Failed to render validation report")
+	}
+}
+
+// importSheet normalises skills of the imported sheet and validates it.
+// Sheet which could not be normalised, e.g. because of broken characteristics, is kept as is:
+// the report tells the user what to fix instead of rejecting the file.
+func importSheet(ctx context.Context, inv character.InvestigatorClass) (character.InvestigatorClass, validation.Report) {
+	normalised, changes, err := skills.Normalise(inv)
+	if err != nil {
+		logger.WithError(ctx, err).Warn("Imported sheet is stored without normalisation")
+
+		normalised = inv
+	}
+
+	report := validation.Validate(normalised)
+
+	// Normalisation fixes are reported too, so the user knows the stored sheet differs from the file.
+	for _, c := range changes {
+		report = append(report, validation.Finding{
+			Severity: validation.SeverityWarning,
+			Path:     c.Field,
+			Message:  fmt.Sprintf("normalised from %q to %q", c.Stored, c.Expected),
+		})
+	}
+
+	return normalised, report
+}
@@ -0,0 +1,612 @@
+// Package sheet renders printable Call of Cthulhu 7e investigator sheets.
+package sheet
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/gofont/gosmallcaps"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+)
+
+// Layout of the sheet.
+type Layout int
+
+const (
+	// Classic is a 1920's styled sheet.
+	Classic Layout = iota
+	// Modern is a modern day styled sheet.
+	Modern
+)
+
+func (l Layout) String() string {
+	switch l {
+	case Classic:
+		return "Classic"
+	case Modern:
+		return "Modern"
+	default:
+		return "Unknown"
+	}
+}
+
+// LayoutFor chooses sheet layout by Header.GameType.
+func LayoutFor(gameType string) Layout {
+	if strings.Contains(strings.ToLower(gameType), "modern") {
+		return Modern
+	}
+
+	return Classic
+}
+
+type rgb struct {
+	r, g, b int
+}
+
+type style struct {
+	title     string
+	subtitle  string
+	heading   string // font family for headings.
+	ink       rgb
+	accent    rgb
+	fill      rgb
+	cashLabel string
+}
+
+func styleFor(l Layout) style {
+	if l == Modern {
+		return style{
+			title:     "CALL OF CTHULHU",
+			subtitle:  "Modern Investigator",
+			heading:   fontBold,
+			ink:       rgb{r: 33, g: 37, b: 41},
+			accent:    rgb{r: 29, g: 78, b: 137},
+			fill:      rgb{r: 230, g: 236, b: 244},
+			cashLabel: "Cash & Assets",
+		}
+	}
+
+	return style{
+		title:     "Call of Cthulhu",
+		subtitle:  "1920s Investigator",
+		heading:   fontSmallCaps,
+		ink:       rgb{r: 59, g: 42, b: 26},
+		accent:    rgb{r: 110, g: 62, b: 24},
+		fill:      rgb{r: 243, g: 233, b: 210},
+		cashLabel: "Cash & Assets (1920s)",
+	}
+}
+
+const (
+	fontRegular   = "goregular"
+	fontBold      = "gobold"
+	fontSmallCaps = "gosmallcaps"
+
+	pageWidth   = 210.0
+	margin      = 10.0
+	contentW    = pageWidth - 2*margin
+	pageBottom  = 287.0
+	skillCols   = 3
+	maxSkillRow = 6.0
+)
+
+// Render writes two pages PDF sheet of the investigator.
+func Render(w io.Writer, inv character.InvestigatorClass) error {
+	layout := LayoutFor(inv.Header.GameType)
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.SetTitle(fmt.Sprintf("%s - investigator sheet", inv.PersonalDetails.Name), true)
+	pdf.SetCreator("cthulhu-mythos-tools", true)
+
+	pdf.AddUTF8FontFromBytes(fontRegular, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(fontBold, "", gobold.TTF)
+	pdf.AddUTF8FontFromBytes(fontSmallCaps, "", gosmallcaps.TTF)
+
+	r := renderer{
+		pdf:   pdf,
+		style: styleFor(layout),
+		inv:   inv,
+	}
+
+	r.firstPage()
+	r.secondPage()
+
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("render sheet: %w", err)
+	}
+
+	return pdf.Output(w)
+}
+
+type renderer struct {
+	pdf   *fpdf.Fpdf
+	style style
+	inv   character.InvestigatorClass
+}
+
+func (r *renderer) setColor(c rgb) {
+	r.pdf.SetTextColor(c.r, c.g, c.b)
+	r.pdf.SetDrawColor(c.r, c.g, c.b)
+}
+
+func (r *renderer) titleBar() {
+	p := r.pdf
+	s := r.style
+
+	p.SetFillColor(s.accent.r, s.accent.g, s.accent.b)
+	p.Rect(margin, margin, contentW, 12, "F")
+
+	p.SetTextColor(255, 255, 255)
+	p.SetFont(s.heading, "", 18)
+	p.SetXY(margin+2, margin)
+	p.CellFormat(contentW/2, 12, s.title, "", 0, "L", false, 0, "")
+
+	p.SetFont(fontRegular, "", 10)
+	p.CellFormat(contentW/2-4, 12, s.subtitle, "", 1, "R", false, 0, "")
+
+	r.setColor(s.ink)
+	p.Ln(2)
+}
+
+func (r *renderer) heading(text string) {
+	p := r.pdf
+	s := r.style
+
+	p.SetFont(s.heading, "", 12)
+	p.SetTextColor(s.accent.r, s.accent.g, s.accent.b)
+	p.CellFormat(contentW, 7, text, "B", 1, "L", false, 0, "")
+	r.setColor(s.ink)
+	p.Ln(1)
+}
+
+func (r *renderer) firstPage() {
+	r.pdf.AddPage()
+	r.titleBar()
+
+	r.personalDetails()
+	r.characteristics()
+	r.derived()
+	r.skills()
+}
+
+func (r *renderer) personalDetails() {
+	p := r.pdf
+	d := r.inv.PersonalDetails
+
+	const (
+		portraitSize = 32.0
+		labelW       = 28.0
+		rowH         = 5.5
+	)
+
+	top := p.GetY()
+
+	r.heading("Investigator")
+
+	rows := [][2]string{
+		{"Name", d.Name},
+		{"Occupation", d.Occupation},
+		{"Age", d.Age},
+		{"Sex", d.Gender},
+		{"Residence", d.Residence},
+		{"Birthplace", d.Birthplace},
+	}
+
+	valueW := contentW - labelW - portraitSize - 4
+
+	for _, row := range rows {
+		p.SetFont(fontBold, "", 9)
+		p.CellFormat(labelW, rowH, row[0]+":", "", 0, "L", false, 0, "")
+		p.SetFont(fontRegular, "", 9)
+		p.CellFormat(valueW, rowH, row[1], "B", 1, "L", false, 0, "")
+	}
+
+	r.portrait(pageWidth-margin-portraitSize, top+8, portraitSize)
+
+	p.Ln(3)
+}
+
+func (r *renderer) portrait(x, y, size float64) {
+	p := r.pdf
+
+	p.Rect(x, y, size, size, "D")
+
+	raw := r.inv.PersonalDetails.Portrait
+	if raw == "" {
+		return
+	}
+
+	data, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return
+	}
+
+	imgType := imageType(data)
+	if imgType == "" {
+		return
+	}
+
+	opts := fpdf.ImageOptions{ImageType: imgType, ReadDpi: false, AllowNegativePosition: false}
+
+	info := p.RegisterImageOptionsReader("portrait", opts, bytes.NewReader(data))
+	if p.Err() {
+		// Broken portrait should not break the whole sheet.
+		p.ClearError()
+
+		return
+	}
+
+	if info == nil {
+		return
+	}
+
+	p.ImageOptions("portrait", x+0.5, y+0.5, size-1, size-1, false, opts, 0, "")
+}
+
+func imageType(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG")):
+		return "PNG"
+	case bytes.HasPrefix(data, []byte("\xff\xd8")):
+		return "JPG"
+	case bytes.HasPrefix(data, []byte("GIF8")):
+		return "GIF"
+	default:
+		return ""
+	}
+}
+
+func (r *renderer) characteristics() {
+	p := r.pdf
+	c := r.inv.Characteristics
+
+	r.heading("Characteristics")
+
+	items := [][2]string{
+		{"STR", c.Str}, {"DEX", c.Dex}, {"INT", c.Int},
+		{"CON", c.Con}, {"APP", c.App}, {"POW", c.Pow},
+		{"SIZ", c.Siz}, {"EDU", c.Edu}, {"Luck", c.Luck},
+	}
+
+	const (
+		cols = 3
+		boxH = 11.0
+		gap  = 2.0
+	)
+
+	boxW := (contentW - gap*(cols-1)) / cols
+	top := p.GetY()
+
+	for i, it := range items {
+		x := margin + float64(i%cols)*(boxW+gap)
+		y := top + float64(i/cols)*(boxH+gap)
+
+		r.valueBox(x, y, boxW, boxH, it[0], it[1])
+	}
+
+	p.SetY(top + 3*(boxH+gap) + 1)
+}
+
+// valueBox draws labeled box with full, half and fifth values.
+func (r *renderer) valueBox(x, y, w, h float64, label, raw string) {
+	p := r.pdf
+	s := r.style
+
+	p.SetFillColor(s.fill.r, s.fill.g, s.fill.b)
+	p.Rect(x, y, w, h, "FD")
+
+	p.SetXY(x+1, y)
+	p.SetFont(s.heading, "", 11)
+	p.CellFormat(w/3, h, label, "", 0, "L", false, 0, "")
+
+	v, err := character.ParseNumber(raw)
+	if err != nil {
+		p.SetFont(fontBold, "", 12)
+		p.CellFormat(w/3, h, raw, "", 0, "C", false, 0, "")
+
+		return
+	}
+
+	val := character.NewValue(v)
+
+	p.SetFont(fontBold, "", 14)
+	p.CellFormat(w/3, h, fmt.Sprint(val.Full), "", 0, "C", false, 0, "")
+
+	p.SetFont(fontRegular, "", 8)
+	p.SetXY(x+2*w/3, y+1)
+	p.CellFormat(w/3-1, h/2-1, fmt.Sprintf("1/2: %d", val.Half), "", 2, "R", false, 0, "")
+	p.CellFormat(w/3-1, h/2-1, fmt.Sprintf("1/5: %d", val.Fifth), "", 0, "R", false, 0, "")
+}
+
+func (r *renderer) derived() {
+	p := r.pdf
+	c := r.inv.Characteristics
+
+	r.heading("Derived attributes")
+
+	items := [][2]string{
+		{"Hit Points", pair(c.HitPts, c.HitPtsMax)},
+		{"Magic Points", pair(c.MagicPts, c.MagicPtsMax)},
+		{"Sanity", fmt.Sprintf("%s (start %s, max %s)", c.Sanity, c.SanityStart, c.SanityMax)},
+		{"Luck", pair(c.Luck, c.LuckMax)},
+		{"Move", c.Move},
+		{"Build", firstNonEmpty(r.inv.Combat.Build, c.Build)},
+		{"Damage Bonus", r.inv.DamageBonus()},
+		{"Dodge", r.inv.Combat.Dodge.Value},
+	}
+
+	const (
+		cols = 4
+		rowH = 6.0
+	)
+
+	colW := contentW / cols
+
+	for i, it := range items {
+		p.SetFont(fontBold, "", 8)
+		p.CellFormat(colW*0.45, rowH, it[0]+":", "", 0, "L", false, 0, "")
+		p.SetFont(fontRegular, "", 8)
+
+		ln := 0
+		if i%cols == cols-1 {
+			ln = 1
+		}
+
+		p.CellFormat(colW*0.55, rowH, it[1], "B", ln, "L", false, 0, "")
+	}
+
+	p.Ln(3)
+}
+
+func (r *renderer) skills() {
+	p := r.pdf
+	s := r.style
+
+	r.heading("Skills")
+
+	list := r.inv.Skills.Skill
+	if len(list) == 0 {
+		p.SetFont(fontRegular, "", 9)
+		p.CellFormat(contentW, 6, "No skills", "", 1, "L", false, 0, "")
+
+		return
+	}
+
+	rows := (len(list) + skillCols - 1) / skillCols
+	top := p.GetY()
+	rowH := min((pageBottom-top)/float64(rows), maxSkillRow)
+	colW := contentW / skillCols
+
+	const boxSize = 2.6
+
+	for i, sk := range list {
+		x := margin + float64(i/rows)*colW
+		y := top + float64(i%rows)*rowH
+
+		// Occupation skill checkbox.
+		bx, by := x+0.5, y+(rowH-boxSize)/2
+		p.Rect(bx, by, boxSize, boxSize, "D")
+
+		if sk.IsOccupation() {
+			p.SetDrawColor(s.accent.r, s.accent.g, s.accent.b)
+			p.Line(bx, by, bx+boxSize, by+boxSize)
+			p.Line(bx, by+boxSize, bx+boxSize, by)
+			r.setColor(s.ink)
+		}
+
+		valW := 18.0
+		nameW := colW - boxSize - 2 - valW
+
+		p.SetXY(x+boxSize+1.5, y)
+		p.SetFont(fontRegular, "", 7.5)
+		p.CellFormat(nameW, rowH, fitText(p, sk.FullName(), nameW), "B", 0, "L", false, 0, "")
+
+		p.SetFont(fontBold, "", 7.5)
+		p.CellFormat(valW*0.45, rowH, sk.Value, "B", 0, "R", false, 0, "")
+		p.SetFont(fontRegular, "", 6)
+		p.CellFormat(valW*0.55, rowH, sk.Half+"/"+sk.Fifth, "B", 0, "R", false, 0, "")
+	}
+
+	p.SetY(top + float64(rows)*rowH)
+}
+
+func (r *renderer) secondPage() {
+	r.pdf.AddPage()
+	r.titleBar()
+
+	r.weapons()
+	r.backstory()
+	r.possessions()
+}
+
+func (r *renderer) weapons() {
+	p := r.pdf
+	s := r.style
+
+	r.heading("Weapons")
+
+	cols := []struct {
+		title string
+		w     float64
+	}{
+		{title: "Weapon", w: 50},
+		{title: "Regular", w: 16},
+		{title: "Hard", w: 14},
+		{title: "Extreme", w: 16},
+		{title: "Damage", w: 26},
+		{title: "Range", w: 22},
+		{title: "Attacks", w: 16},
+		{title: "Ammo", w: 14},
+		{title: "Malf", w: 16},
+	}
+
+	p.SetFont(fontBold, "", 8)
+	p.SetFillColor(s.fill.r, s.fill.g, s.fill.b)
+
+	for i, c := range cols {
+		ln := 0
+		if i == len(cols)-1 {
+			ln = 1
+		}
+
+		p.CellFormat(c.w, 6, c.title, "1", ln, "C", true, 0, "")
+	}
+
+	p.SetFont(fontRegular, "", 8)
+
+	for _, wpn := range r.inv.Weapons.Weapon {
+		v, _ := character.ParseNumber(wpn.Regular)
+		val := character.NewValue(v)
+
+		hard := fmt.Sprint(val.Half)
+		if wpn.Hard != nil && *wpn.Hard != "" {
+			hard = *wpn.Hard
+		}
+
+		extreme := fmt.Sprint(val.Fifth)
+		if wpn.Extreme != nil && *wpn.Extreme != "" {
+			extreme = *wpn.Extreme
+		}
+
+		values := []string{
+			fitText(p, wpn.Name, cols[0].w), wpn.Regular, hard, extreme,
+			wpn.Damage, fitText(p, wpn.Range, cols[5].w), wpn.Attacks, wpn.Ammo, wpn.Malf,
+		}
+
+		for i, v := range values {
+			ln := 0
+			if i == len(values)-1 {
+				ln = 1
+			}
+
+			align := "C"
+			if i == 0 {
+				align = "L"
+			}
+
+			p.CellFormat(cols[i].w, 6, v, "1", ln, align, false, 0, "")
+		}
+	}
+
+	p.Ln(4)
+}
+
+func (r *renderer) backstory() {
+	b := r.inv.Backstory
+
+	r.heading("Backstory")
+
+	sections := [][2]string{
+		{"Personal Description", b.Description},
+		{"Ideology/Beliefs", b.Ideology},
+		{"Significant People", b.People},
+		{"Meaningful Locations", b.Locations},
+		{"Treasured Possessions", b.Possessions},
+		{"Traits", b.Traits},
+		{"Injuries & Scars", anyText(b.Injurues)},
+		{"Phobias & Manias", b.Phobias},
+		{"Arcane Tomes, Spells & Artifacts", b.Tomes},
+		{"Encounters with Strange Entities", b.Encounters},
+	}
+
+	for _, sec := range sections {
+		r.textBlock(sec[0], sec[1])
+	}
+}
+
+func (r *renderer) possessions() {
+	p := r.pdf
+	c := r.inv.Cash
+
+	r.heading("Gear & Possessions")
+
+	p.SetFont(fontRegular, "", 9)
+	p.MultiCell(contentW, 5, orDash(r.inv.Possessions.Item.Description), "", "L", false)
+	p.Ln(2)
+
+	r.heading(r.style.cashLabel)
+
+	rows := [][2]string{
+		{"Spending Level", c.Spending},
+		{"Cash", c.Cash},
+		{"Assets", c.Assets},
+	}
+
+	for _, row := range rows {
+		p.SetFont(fontBold, "", 9)
+		p.CellFormat(35, 6, row[0]+":", "", 0, "L", false, 0, "")
+		p.SetFont(fontRegular, "", 9)
+		p.CellFormat(contentW-35, 6, orDash(row[1]), "B", 1, "L", false, 0, "")
+	}
+}
+
+func (r *renderer) textBlock(title, text string) {
+	p := r.pdf
+
+	p.SetFont(fontBold, "", 9)
+	p.CellFormat(contentW, 5, title, "", 1, "L", false, 0, "")
+
+	p.SetFont(fontRegular, "", 9)
+	p.MultiCell(contentW, 4.5, orDash(text), "", "L", false)
+	p.Ln(1.5)
+}
+
+func fitText(p *fpdf.Fpdf, s string, w float64) string {
+	const ellipsis = "…"
+
+	if p.GetStringWidth(s) <= w-1 {
+		return s
+	}
+
+	rs := []rune(s)
+
+	for len(rs) > 0 && p.GetStringWidth(string(rs)+ellipsis) > w-1 {
+		rs = rs[:len(rs)-1]
+	}
+
+	return string(rs) + ellipsis
+}
+
+func pair(current, maxValue string) string {
+	if maxValue == "" || current == maxValue {
+		return current
+	}
+
+	return current + " / " + maxValue
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func orDash(s string) string {
+	s = strings.TrimSpace(strings.ReplaceAll(s, "\r", ""))
+	if s == "" {
+		return "—"
+	}
+
+	return s
+}
+
+func anyText(v any) string {
+	if v == nil {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,50 @@
+package sheet
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+)
+
+func TestLayoutFor(t *testing.T) {
+	assert.Equal(t, Classic, LayoutFor("Classic (1920's)"))
+	assert.Equal(t, Modern, LayoutFor("Modern"))
+	assert.Equal(t, Classic, LayoutFor(""))
+}
+
+func TestRender(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "character", "testdata", "character.json"))
+	require.NoError(t, err)
+
+	inv, err := character.UnmarshalInvestigator(data)
+	require.NoError(t, err)
+
+	modern := inv.Investigator
+	modern.Header.GameType = character.GameTypeModern
+
+	tests := []struct {
+		name string
+		inv  character.InvestigatorClass
+	}{
+		{name: "classic", inv: inv.Investigator},
+		{name: "modern", inv: modern},
+		{name: "empty", inv: character.InvestigatorClass{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			require.NoError(t, Render(&buf, tt.inv))
+
+			assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+			assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("/Type /Page\n")))
+		})
+	}
+}
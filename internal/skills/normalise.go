@@ -0,0 +1,139 @@
+package skills
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+)
+
+// Normalise brings sheet skills to the canonical names and fills values:
+// aliases are renamed, "Family (Specialisation)" names are split into name and subskill,
+// empty values are set to the base ones and half and fifth values are recalculated.
+// It returns normalised investigator and the list of changes made.
+func Normalise(inv character.InvestigatorClass) (character.InvestigatorClass, []character.Mismatch, error) {
+	stats, err := inv.Characteristics.Stats()
+	if err != nil {
+		return inv, nil, err
+	}
+
+	var changes []character.Mismatch
+
+	list := make([]character.Skill, len(inv.Skills.Skill))
+
+	for i, s := range inv.Skills.Skill {
+		field := "Skills." + s.FullName()
+
+		d, spec, ok := Resolve(s)
+		if ok {
+			canonical := s
+			canonical.Name = d.Name
+
+			if spec != "" {
+				canonical.Subskill = &spec
+			} else if d.Family && s.Subskill == nil {
+				none := SubskillNone
+				canonical.Subskill = &none
+			}
+
+			if canonical.FullName() != s.FullName() {
+				changes = append(changes, character.Mismatch{
+					Field:    field + ".name",
+					Stored:   s.FullName(),
+					Expected: canonical.FullName(),
+				})
+			}
+
+			if strings.TrimSpace(s.Value) == "" {
+				canonical.Value = strconv.Itoa(d.BaseValue(spec, stats))
+
+				changes = append(changes, character.Mismatch{
+					Field:    field + ".value",
+					Stored:   s.Value,
+					Expected: canonical.Value,
+				})
+			}
+
+			s = canonical
+		}
+
+		v, err := s.Values()
+		if err != nil {
+			return inv, nil, fmt.Errorf("%s: %w", field, err)
+		}
+
+		half, fifth := strconv.Itoa(v.Half), strconv.Itoa(v.Fifth)
+
+		if s.Half != half || s.Fifth != fifth {
+			changes = append(changes, character.Mismatch{
+				Field:    field + ".half/fifth",
+				Stored:   s.Half + "/" + s.Fifth,
+				Expected: half + "/" + fifth,
+			})
+
+			s.Half, s.Fifth = half, fifth
+		}
+
+		list[i] = s
+	}
+
+	inv.Skills.Skill = list
+
+	return inv, changes, nil
+}
+
+// Check compares sheet skills with the registry: unknown skills, skills not available
+// in the sheet era and values below the base ones are reported.
+func Check(inv character.InvestigatorClass) ([]character.Mismatch, error) {
+	stats, err := inv.Characteristics.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	era := character.EraFor(inv.Header.GameType)
+
+	var res []character.Mismatch
+
+	for _, s := range inv.Skills.Skill {
+		if strings.EqualFold(s.Name, misc) {
+			continue
+		}
+
+		field := "Skills." + s.FullName()
+
+		d, spec, ok := Resolve(s)
+		if !ok {
+			res = append(res, character.Mismatch{
+				Field:    field,
+				Stored:   s.FullName(),
+				Expected: "known skill",
+			})
+
+			continue
+		}
+
+		if !d.AvailableIn(era) {
+			res = append(res, character.Mismatch{
+				Field:    field,
+				Stored:   s.FullName(),
+				Expected: fmt.Sprintf("skill of %s era", era),
+			})
+		}
+
+		v, err := character.ParseNumber(s.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+
+		if base := d.BaseValue(spec, stats); v < base {
+			res = append(res, character.Mismatch{
+				Field:    field + ".value",
+				Stored:   s.Value,
+				Expected: fmt.Sprintf(">= %d", base),
+			})
+		}
+	}
+
+	return res, nil
+}
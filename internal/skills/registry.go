@@ -0,0 +1,292 @@
+// Package skills provides the canonical Call of Cthulhu 7e skill list with base values.
+package skills
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+)
+
+const (
+	CreditRating  = "Credit Rating"
+	CthulhuMythos = "Cthulhu Mythos"
+	Dodge         = "Dodge"
+	Fighting      = "Fighting"
+	Firearms      = "Firearms"
+	LanguageOwn   = "Language (Own)"
+	LanguageOther = "Language (Other)"
+
+	// misc is a Dhole's House placeholder for custom skills.
+	misc = "Misc"
+	// SubskillNone is a Dhole's House placeholder for not chosen specialisation.
+	SubskillNone = "None"
+)
+
+var allEras = []character.Era{character.EraClassic, character.EraModern}
+
+// Specialisation is a known specialisation of a skill family with its own base value.
+type Specialisation struct {
+	Name string
+	Base int
+}
+
+// Definition describes a skill.
+type Definition struct {
+	Name string
+	// Base is a base value, for families it is used for specialisations without own base value.
+	Base int
+	// baseFrom calculates base value from characteristics, when set Base is ignored.
+	baseFrom func(s character.Stats) int
+	// Family skills take specialisation, e.g. Science (Biology).
+	Family          bool
+	Specialisations []Specialisation
+	Eras            []character.Era
+	// Uncommon skills are not listed on a new sheet.
+	Uncommon bool
+	// Starting lists specialisations put on a new sheet, empty string is a slot without specialisation.
+	Starting []string
+}
+
+// AvailableIn reports whether skill exists in the era.
+func (d Definition) AvailableIn(era character.Era) bool {
+	return slices.Contains(d.Eras, era)
+}
+
+// BaseValue returns base value of the skill with specialisation for the characteristics.
+func (d Definition) BaseValue(spec string, s character.Stats) int {
+	for _, sp := range d.Specialisations {
+		if strings.EqualFold(sp.Name, spec) {
+			return sp.Base
+		}
+	}
+
+	if d.baseFrom != nil {
+		return d.baseFrom(s)
+	}
+
+	return d.Base
+}
+
+// Specialisation returns canonical spelling of known specialisation or spec itself.
+func (d Definition) Specialisation(spec string) string {
+	for _, sp := range d.Specialisations {
+		if strings.EqualFold(sp.Name, spec) {
+			return sp.Name
+		}
+	}
+
+	return spec
+}
+
+func specs(base int, names ...string) []Specialisation {
+	res := make([]Specialisation, 0, len(names))
+
+	for _, n := range names {
+		res = append(res, Specialisation{Name: n, Base: base})
+	}
+
+	return res
+}
+
+//nolint:mnd // base values are defined by the rulebook.
+var registry = []Definition{
+	{Name: "Accounting", Base: 5},
+	{Name: "Animal Handling", Base: 5, Uncommon: true},
+	{Name: "Anthropology", Base: 1},
+	{Name: "Appraise", Base: 5},
+	{Name: "Archaeology", Base: 1},
+	{Name: "Art/Craft", Base: 5, Family: true, Specialisations: specs(5,
+		"Acting", "Fine Art", "Forgery", "Literature", "Photography", "Farming", "Technical Drawing", "Instrument", "Singing",
+	)},
+	{Name: "Artillery", Base: 1, Uncommon: true},
+	{Name: "Charm", Base: 15},
+	{Name: "Climb", Base: 20},
+	{Name: "Computer Use", Base: 5, Eras: []character.Era{character.EraModern}},
+	{Name: CreditRating, Base: 0},
+	{Name: CthulhuMythos, Base: 0},
+	{Name: "Demolitions", Base: 1, Uncommon: true},
+	{Name: "Disguise", Base: 5},
+	{Name: "Diving", Base: 1, Uncommon: true},
+	{Name: Dodge, baseFrom: func(s character.Stats) int { return s.DEX.Half }},
+	{Name: "Drive Auto", Base: 20},
+	{Name: "Electrical Repair", Base: 10},
+	{Name: "Electronics", Base: 1, Eras: []character.Era{character.EraModern}},
+	{Name: "Fast Talk", Base: 5},
+	{Name: Fighting, Base: 1, Family: true, Starting: []string{"Brawl"}, Specialisations: []Specialisation{
+		{Name: "Brawl", Base: 25},
+		{Name: "Axe", Base: 15},
+		{Name: "Chainsaw", Base: 10},
+		{Name: "Flail", Base: 10},
+		{Name: "Garrote", Base: 15},
+		{Name: "Spear", Base: 20},
+		{Name: "Sword", Base: 20},
+		{Name: "Whip", Base: 5},
+	}},
+	{Name: Firearms, Base: 1, Family: true, Starting: []string{"Handgun", "Rifle/Shotgun"}, Specialisations: []Specialisation{
+		{Name: "Handgun", Base: 20},
+		{Name: "Rifle/Shotgun", Base: 25},
+		{Name: "Bow", Base: 15},
+		{Name: "Flamethrower", Base: 10},
+		{Name: "Heavy Weapons", Base: 10},
+		{Name: "Machine Gun", Base: 10},
+		{Name: "Submachine Gun", Base: 15},
+	}},
+	{Name: "First Aid", Base: 30},
+	{Name: "History", Base: 5},
+	{Name: "Hypnosis", Base: 1, Uncommon: true},
+	{Name: "Intimidate", Base: 15},
+	{Name: "Jump", Base: 20},
+	{Name: LanguageOther, Base: 1, Family: true},
+	{Name: LanguageOwn, baseFrom: func(s character.Stats) int { return s.EDU.Full }, Family: true},
+	{Name: "Law", Base: 5},
+	{Name: "Library Use", Base: 20},
+	{Name: "Listen", Base: 20},
+	{Name: "Locksmith", Base: 1},
+	{Name: "Lore", Base: 1, Family: true, Uncommon: true},
+	{Name: "Mechanical Repair", Base: 10},
+	{Name: "Medicine", Base: 1},
+	{Name: "Natural World", Base: 10},
+	{Name: "Navigate", Base: 10},
+	{Name: "Occult", Base: 5},
+	{Name: "Operate Heavy Machine", Base: 1},
+	{Name: "Persuade", Base: 10},
+	{Name: "Pilot", Base: 1, Family: true, Specialisations: specs(1, "Aircraft", "Boat", "Airship")},
+	{Name: "Psychology", Base: 10},
+	{Name: "Psychoanalysis", Base: 1},
+	{Name: "Read Lips", Base: 1, Uncommon: true},
+	{Name: "Ride", Base: 5},
+	{Name: "Science", Base: 1, Family: true, Specialisations: specs(1,
+		"Astronomy", "Biology", "Botany", "Chemistry", "Cryptography", "Engineering", "Forensics",
+		"Geology", "Mathematics", "Meteorology", "Pharmacy", "Physics", "Zoology",
+	)},
+	{Name: "Sleight of Hand", Base: 10},
+	{Name: "Spot Hidden", Base: 25},
+	{Name: "Stealth", Base: 20},
+	{Name: "Survival", Base: 10, Family: true, Specialisations: specs(10, "Arctic", "Desert", "Sea", "Jungle")},
+	{Name: "Swim", Base: 20},
+	{Name: "Throw", Base: 20},
+	{Name: "Track", Base: 10},
+}
+
+// aliases maps alternative spellings met in sheets to the canonical full names.
+var aliases = map[string]string{
+	"art":                     "Art/Craft",
+	"craft":                   "Art/Craft",
+	"brawl":                   "Fighting (Brawl)",
+	"computer":                "Computer Use",
+	"drive automobile":        "Drive Auto",
+	"drive car":               "Drive Auto",
+	"elec. repair":            "Electrical Repair",
+	"electric repair":         "Electrical Repair",
+	"handgun":                 "Firearms (Handgun)",
+	"mech. repair":            "Mechanical Repair",
+	"operate heavy machinery": "Operate Heavy Machine",
+	"other language":          LanguageOther,
+	"own language":            LanguageOwn,
+	"rifle":                   "Firearms (Rifle/Shotgun)",
+	"rifle/shotgun":           "Firearms (Rifle/Shotgun)",
+	"shotgun":                 "Firearms (Rifle/Shotgun)",
+}
+
+func init() {
+	for i := range registry {
+		if registry[i].Eras == nil {
+			registry[i].Eras = allEras
+		}
+	}
+}
+
+// All returns all known skills.
+func All() []Definition {
+	return slices.Clone(registry)
+}
+
+// Lookup resolves skill name, alias or "Family (Specialisation)" full name into definition and specialisation.
+func Lookup(name string) (Definition, string, bool) {
+	name = strings.TrimSpace(name)
+
+	if canonical, ok := aliases[strings.ToLower(name)]; ok {
+		name = canonical
+	}
+
+	for _, d := range registry {
+		if strings.EqualFold(d.Name, name) {
+			return d, "", true
+		}
+	}
+
+	idx := strings.LastIndex(name, " (")
+	if idx < 0 || !strings.HasSuffix(name, ")") {
+		return Definition{}, "", false
+	}
+
+	family, spec := name[:idx], name[idx+2:len(name)-1]
+
+	for _, d := range registry {
+		if d.Family && strings.EqualFold(d.Name, family) {
+			return d, d.Specialisation(spec), true
+		}
+	}
+
+	return Definition{}, "", false
+}
+
+// Resolve looks up definition of the sheet skill. Specialisation is taken from the subskill when it is set.
+func Resolve(s character.Skill) (Definition, string, bool) {
+	d, spec, ok := Lookup(s.Name)
+	if !ok {
+		return Definition{}, "", false
+	}
+
+	if sp := s.Specialisation(); sp != "" {
+		spec = d.Specialisation(sp)
+	}
+
+	return d, spec, true
+}
+
+// Slot is a skill of a new investigator.
+type Slot struct {
+	Name           string
+	Specialisation string
+	Base           int
+	Family         bool
+}
+
+// FullName returns skill name with its specialisation, e.g. "Firearms (Handgun)".
+func (s Slot) FullName() string {
+	if s.Specialisation != "" {
+		return fmt.Sprintf("%s (%s)", s.Name, s.Specialisation)
+	}
+
+	return s.Name
+}
+
+// Starting returns skills of a new investigator in the era with base values for the characteristics.
+func Starting(era character.Era, s character.Stats) []Slot {
+	var res []Slot
+
+	for _, d := range registry {
+		if d.Uncommon || !d.AvailableIn(era) {
+			continue
+		}
+
+		starting := d.Starting
+		if len(starting) == 0 {
+			starting = []string{""}
+		}
+
+		for _, spec := range starting {
+			res = append(res, Slot{
+				Name:           d.Name,
+				Specialisation: spec,
+				Base:           d.BaseValue(spec, s),
+				Family:         d.Family,
+			})
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,145 @@
+package skills
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character/charactertest"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantName string
+		wantSpec string
+		wantOK   bool
+	}{
+		{name: "Dodge", wantName: "Dodge", wantOK: true},
+		{name: "spot hidden", wantName: "Spot Hidden", wantOK: true},
+		{name: "firearms (handgun)", wantName: "Firearms", wantSpec: "Handgun", wantOK: true},
+		{name: "Handgun", wantName: "Firearms", wantSpec: "Handgun", wantOK: true},
+		{name: "Operate Heavy Machinery", wantName: "Operate Heavy Machine", wantOK: true},
+		{name: "Language (Other)", wantName: "Language (Other)", wantOK: true},
+		{name: "Language (Other) (Latin)", wantName: "Language (Other)", wantSpec: "Latin", wantOK: true},
+		{name: "Science (Xenobiology)", wantName: "Science", wantSpec: "Xenobiology", wantOK: true},
+		{name: "Swim (Butterfly)", wantOK: false},
+		{name: "Basket Weaving", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, spec, ok := Lookup(tt.name)
+			require.Equal(t, tt.wantOK, ok)
+
+			if !ok {
+				return
+			}
+
+			assert.Equal(t, tt.wantName, d.Name)
+			assert.Equal(t, tt.wantSpec, spec)
+		})
+	}
+}
+
+func TestDefinition_BaseValue(t *testing.T) {
+	stats := character.Stats{
+		DEX: character.NewValue(55),
+		EDU: character.NewValue(70),
+	}
+
+	tests := []struct {
+		name string
+		want int
+	}{
+		{name: "Dodge", want: 27},
+		{name: "Language (Own)", want: 70},
+		{name: "Cthulhu Mythos", want: 0},
+		{name: "Fighting (Brawl)", want: 25},
+		{name: "Fighting (Katana)", want: 1},
+		{name: "Firearms (Rifle/Shotgun)", want: 25},
+		{name: "Art/Craft (Photography)", want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, spec, ok := Lookup(tt.name)
+			require.True(t, ok)
+
+			assert.Equal(t, tt.want, d.BaseValue(spec, stats))
+		})
+	}
+}
+
+func TestStarting(t *testing.T) {
+	names := func(era character.Era) []string {
+		var res []string
+		for _, s := range Starting(era, character.Stats{}) {
+			res = append(res, s.FullName())
+		}
+
+		return res
+	}
+
+	classic := names(character.EraClassic)
+	assert.Contains(t, classic, "Fighting (Brawl)")
+	assert.Contains(t, classic, "Firearms (Rifle/Shotgun)")
+	assert.NotContains(t, classic, "Computer Use")
+	assert.NotContains(t, classic, "Hypnosis")
+
+	assert.Contains(t, names(character.EraModern), "Computer Use")
+}
+
+func TestNormalise(t *testing.T) {
+	inv := charactertest.LoadInvestigator(t)
+
+	got, changes, err := Normalise(inv)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, inv, got)
+
+	inv.Skills.Skill = []character.Skill{
+		{Name: "Operate Heavy Machinery", SkillValues: character.SkillValues{Value: "21", Half: "10", Fifth: "4"}},
+		{Name: "Firearms (Handgun)", SkillValues: character.SkillValues{Value: "40", Half: "20", Fifth: "8"}},
+		{Name: "Dodge"},
+		{Name: "Basket Weaving", SkillValues: character.SkillValues{Value: "50"}},
+	}
+
+	got, changes, err = Normalise(inv)
+	require.NoError(t, err)
+
+	handgun := "Handgun"
+
+	assert.Equal(t, []character.Skill{
+		{Name: "Operate Heavy Machine", SkillValues: character.SkillValues{Value: "21", Half: "10", Fifth: "4"}},
+		{Name: "Firearms", Subskill: &handgun, SkillValues: character.SkillValues{Value: "40", Half: "20", Fifth: "8"}},
+		{Name: "Dodge", SkillValues: character.SkillValues{Value: "27", Half: "13", Fifth: "5"}},
+		{Name: "Basket Weaving", SkillValues: character.SkillValues{Value: "50", Half: "25", Fifth: "10"}},
+	}, got.Skills.Skill)
+	assert.Len(t, changes, 4)
+}
+
+func TestCheck(t *testing.T) {
+	inv := charactertest.LoadInvestigator(t)
+
+	got, err := Check(inv)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	inv.Skills.Skill = []character.Skill{
+		{Name: "Computer Use", SkillValues: character.SkillValues{Value: "50"}},
+		{Name: "Dodge", SkillValues: character.SkillValues{Value: "10"}},
+		{Name: "Basket Weaving", SkillValues: character.SkillValues{Value: "50"}},
+		{Name: "Misc", SkillValues: character.SkillValues{Value: "1"}},
+	}
+
+	got, err = Check(inv)
+	require.NoError(t, err)
+	assert.Equal(t, []character.Mismatch{
+		{Field: "Skills.Computer Use", Stored: "Computer Use", Expected: "skill of classic era"},
+		{Field: "Skills.Dodge.value", Stored: "10", Expected: ">= 27"},
+		{Field: "Skills.Basket Weaving", Stored: "Basket Weaving", Expected: "known skill"},
+	}, got)
+}
@@ -0,0 +1,395 @@
+package storage
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/auth"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/campaign"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/chase"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/combat"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/creation"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/handouts"
+)
+
+const (
+	boltFileMode    = 0o600
+	boltDirMode     = 0o750
+	boltOpenTimeout = 5 * time.Second
+)
+
+var (
+	metaBucket       = []byte("meta")
+	schemaVersionKey = []byte("schema_version")
+	charactersBucket = []byte("characters")
+	handoutsBucket   = []byte("handouts")
+	draftsBucket     = []byte("drafts")
+	encountersBucket = []byte("encounters")
+	chasesBucket     = []byte("chases")
+	campaignsBucket  = []byte("campaigns")
+	usersBucket      = []byte("users")
+	sessionsBucket   = []byte("sessions")
+)
+
+// migration upgrades database schema by one version.
+type migration func(tx *bolt.Tx) error
+
+// migrations are applied in order, index+1 is the schema version after migration is applied.
+// Never change or remove applied migrations - add new ones to the end.
+var migrations = []migration{
+	// 1: characters bucket.
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(charactersBucket)
+
+		return err
+	},
+	// 2: handouts bucket.
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(handoutsBucket)
+
+		return err
+	},
+	// 3: creation wizard drafts bucket.
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(draftsBucket)
+
+		return err
+	},
+	// 4: combat encounters bucket.
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(encountersBucket)
+
+		return err
+	},
+	// 5: chases bucket.
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(chasesBucket)
+
+		return err
+	},
+	// 6: campaigns bucket.
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(campaignsBucket)
+
+		return err
+	},
+	// 7: user accounts and login sessions buckets.
+	func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(usersBucket); err != nil {
+			return err
+		}
+
+		_, err := tx.CreateBucketIfNotExists(sessionsBucket)
+
+		return err
+	},
+	// 8: initial version of characters stored before optimistic locking.
+	versionCharacters,
+}
+
+// versionCharacters sets InitialVersion on characters stored without version.
+// Records are patched as raw JSON, so fields unknown to Character are kept.
+func versionCharacters(tx *bolt.Tx) error {
+	bkt := tx.Bucket(charactersBucket)
+
+	patched := make(map[string][]byte)
+
+	err := bkt.ForEach(func(k, v []byte) error {
+		var rec map[string]json.RawMessage
+
+		if err := json.Unmarshal(v, &rec); err != nil {
+			return fmt.Errorf("unmarshal character %s: %w", k, err)
+		}
+
+		var version int
+
+		if raw, ok := rec["version"]; ok {
+			if err := json.Unmarshal(raw, &version); err != nil {
+				return fmt.Errorf("unmarshal version of character %s: %w", k, err)
+			}
+		}
+
+		if version != 0 {
+			return nil
+		}
+
+		rec["version"] = json.RawMessage(strconv.Itoa(InitialVersion))
+
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf("marshal character %s: %w", k, err)
+		}
+
+		patched[string(k)] = data
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Bucket must not be modified inside ForEach.
+	for k, data := range patched {
+		if err = bkt.Put([]byte(k), data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type boltStorage struct {
+	db         *bolt.DB
+	handouts   *boltRepository[handouts.Handout]
+	drafts     *boltRepository[creation.Draft]
+	encounters *boltRepository[combat.Encounter]
+	chases     *boltRepository[chase.Chase]
+	campaigns  *boltRepository[campaign.Campaign]
+	users      *boltRepository[auth.User]
+	sessions   *boltRepository[auth.Session]
+}
+
+// NewBoltStorage opens (or creates) file based storage at the path and migrates its schema to the latest version.
+func NewBoltStorage(path string) (Storage, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, boltDirMode); err != nil {
+			return nil, fmt.Errorf("create storage dir: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, boltFileMode, &bolt.Options{
+		Timeout: boltOpenTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("open storage file: %w", err)
+	}
+
+	if err = migrate(db); err != nil {
+		return nil, errors.Join(fmt.Errorf("migrate storage: %w", err), db.Close())
+	}
+
+	return &boltStorage{
+		db:         db,
+		handouts:   newBoltRepository[handouts.Handout](db, handoutsBucket),
+		drafts:     newBoltRepository[creation.Draft](db, draftsBucket),
+		encounters: newBoltRepository[combat.Encounter](db, encountersBucket),
+		chases:     newBoltRepository[chase.Chase](db, chasesBucket),
+		campaigns:  newBoltRepository[campaign.Campaign](db, campaignsBucket),
+		users:      newBoltRepository[auth.User](db, usersBucket),
+		sessions:   newBoltRepository[auth.Session](db, sessionsBucket),
+	}, nil
+}
+
+// SchemaVersion returns the latest schema version known to the application.
+func SchemaVersion() int {
+	return len(migrations)
+}
+
+func migrate(db *bolt.DB) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		var current int
+
+		if v := meta.Get(schemaVersionKey); v != nil {
+			current = int(binary.BigEndian.Uint64(v))
+		}
+
+		if current > len(migrations) {
+			return fmt.Errorf("storage schema version %d is newer than supported %d", current, len(migrations))
+		}
+
+		for i := current; i < len(migrations); i++ {
+			if err = migrations[i](tx); err != nil {
+				return fmt.Errorf("migration %d: %w", i+1, err)
+			}
+		}
+
+		v := make([]byte, 8)
+		binary.BigEndian.PutUint64(v, uint64(len(migrations)))
+
+		return meta.Put(schemaVersionKey, v)
+	})
+}
+
+func (b *boltStorage) Create(character Character) error {
+	if character.Version == 0 {
+		character.Version = InitialVersion
+	}
+
+	data, err := json.Marshal(character)
+	if err != nil {
+		return fmt.Errorf("marshal character: %w", err)
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(charactersBucket)
+
+		if bkt.Get([]byte(character.ID)) != nil {
+			return ErrAlreadyExists
+		}
+
+		return bkt.Put([]byte(character.ID), data)
+	})
+}
+
+func (b *boltStorage) Update(character Character) (Character, error) {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(charactersBucket)
+
+		v := bkt.Get([]byte(character.ID))
+		if v == nil {
+			return ErrNotFound
+		}
+
+		var stored Character
+
+		if err := json.Unmarshal(v, &stored); err != nil {
+			return fmt.Errorf("unmarshal character: %w", err)
+		}
+
+		if stored.Version != character.Version {
+			return ErrConflict
+		}
+
+		character.Version++
+
+		data, err := json.Marshal(character)
+		if err != nil {
+			return fmt.Errorf("marshal character: %w", err)
+		}
+
+		return bkt.Put([]byte(character.ID), data)
+	})
+	if err != nil {
+		return Character{}, err
+	}
+
+	return character, nil
+}
+
+func (b *boltStorage) List() ([]Character, error) {
+	resp := make([]Character, 0)
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(charactersBucket).ForEach(func(_, v []byte) error {
+			var c Character
+
+			if err := json.Unmarshal(v, &c); err != nil {
+				return fmt.Errorf("unmarshal character: %w", err)
+			}
+
+			resp = append(resp, c)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (b *boltStorage) Count() (int, error) {
+	var n int
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		n = tx.Bucket(charactersBucket).Stats().KeyN
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func (b *boltStorage) Get(id string) (Character, error) {
+	var c Character
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(charactersBucket).Get([]byte(id))
+		if v == nil {
+			return ErrNotFound
+		}
+
+		if err := json.Unmarshal(v, &c); err != nil {
+			return fmt.Errorf("unmarshal character: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Character{}, err
+	}
+
+	return c, nil
+}
+
+func (b *boltStorage) Delete(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(charactersBucket)
+
+		if bkt.Get([]byte(id)) == nil {
+			return ErrNotFound
+		}
+
+		return bkt.Delete([]byte(id))
+	})
+}
+
+func (b *boltStorage) Handouts() Repository[handouts.Handout] {
+	return b.handouts
+}
+
+func (b *boltStorage) Drafts() Repository[creation.Draft] {
+	return b.drafts
+}
+
+func (b *boltStorage) Encounters() Repository[combat.Encounter] {
+	return b.encounters
+}
+
+func (b *boltStorage) Chases() Repository[chase.Chase] {
+	return b.chases
+}
+
+func (b *boltStorage) Campaigns() Repository[campaign.Campaign] {
+	return b.campaigns
+}
+
+func (b *boltStorage) Users() Repository[auth.User] {
+	return b.users
+}
+
+func (b *boltStorage) Sessions() Repository[auth.Session] {
+	return b.sessions
+}
+
+// Ping checks that database file is open and its schema is readable.
+func (b *boltStorage) Ping() error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(metaBucket) == nil {
+			return errors.New("meta bucket not found")
+		}
+
+		return nil
+	})
+}
+
+func (b *boltStorage) Close() error {
+	return b.db.Close()
+}
@@ -1,9 +1,114 @@
 package storage
 
+import (
+	"slices"
+	"time"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/sanity"
+)
+
+// InitialVersion is a version of just created character.
+const InitialVersion = 1
+
+// LogKind is a kind of character log entry.
+type LogKind string
+
+const (
+	// LogDevelopment marks entries of the development phase.
+	LogDevelopment LogKind = "development"
+	// LogSanity marks Sanity losses, insanity and Cthulhu Mythos gains.
+	LogSanity LogKind = "sanity"
+	// LogCombat marks hit points changes after the combat.
+	LogCombat LogKind = "combat"
+	// LogLuck marks Luck spends and end of session Luck recovery.
+	LogLuck LogKind = "luck"
+)
+
+// LogEntry records a change of the character made by the game mechanics.
+type LogEntry struct {
+	Time    time.Time `json:"time"`
+	Kind    LogKind   `json:"kind"`
+	Message string    `json:"message"`
+	// Source is ID of the encounter or other record the change comes from, empty when there is none.
+	Source string `json:"source,omitempty"`
+}
+
+// Check is a skill check rolled for the character. Luck could be spent only on the last check and only once,
+// ID is a one-time token the player sends back to spend Luck on it.
+type Check struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Target int    `json:"target"`
+	Roll   int    `json:"roll"`
+}
+
+// Character is an investigator sheet kept in the storage.
 type Character struct {
-	ID         string
-	Name       string
-	Occupation string
-	Age        string
-	// TODO: Finalize the structure of the character according to Call of Cthulhu 7e rules.
+	ID string `json:"id"`
+	// Version is incremented on each update and used to detect concurrent modifications.
+	Version      int                         `json:"version"`
+	Investigator character.InvestigatorClass `json:"investigator"`
+	// Owner is ID of the user who created the character, empty for characters created without authentication.
+	Owner string `json:"owner,omitempty"`
+	// Log is a history of changes made by the game mechanics, oldest first.
+	Log []LogEntry `json:"log,omitempty"`
+	// Sanity tracks Sanity losses within a game day and insanity of the investigator.
+	Sanity sanity.State `json:"sanity"`
+	// LastCheck is the last skill check rolled on the site, nil when there is nothing to spend Luck on.
+	LastCheck *Check `json:"last_check,omitempty"`
+}
+
+// AddLog appends entry to the character log.
+func (c *Character) AddLog(now time.Time, kind LogKind, message string) {
+	c.Log = append(c.Log, LogEntry{
+		Time:    now,
+		Kind:    kind,
+		Message: message,
+	})
+}
+
+// AddSourceLog appends entry made for the source record to the character log.
+func (c *Character) AddSourceLog(now time.Time, kind LogKind, source, message string) {
+	c.Log = append(c.Log, LogEntry{
+		Time:    now,
+		Kind:    kind,
+		Message: message,
+		Source:  source,
+	})
+}
+
+// HasSourceLog reports whether the log has entry of the kind made for the source record.
+func (c Character) HasSourceLog(kind LogKind, source string) bool {
+	return slices.ContainsFunc(c.Log, func(e LogEntry) bool {
+		return e.Kind == kind && e.Source == source
+	})
+}
+
+// LogOf returns log entries of the kind, oldest first.
+func (c Character) LogOf(kind LogKind) []LogEntry {
+	var res []LogEntry
+
+	for _, e := range c.Log {
+		if e.Kind == kind {
+			res = append(res, e)
+		}
+	}
+
+	return res
+}
+
+// Name returns investigator name.
+func (c Character) Name() string {
+	return c.Investigator.PersonalDetails.Name
+}
+
+// Occupation returns investigator occupation.
+func (c Character) Occupation() string {
+	return c.Investigator.PersonalDetails.Occupation
+}
+
+// Age returns investigator age.
+func (c Character) Age() string {
+	return c.Investigator.PersonalDetails.Age
 }
@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Repository is a storage of records of type T identified by string ID.
+type Repository[T any] interface {
+	Create(id string, v T) error
+	List() ([]T, error)
+	Get(id string) (T, error)
+	Update(id string, v T) error
+	Delete(id string) error
+}
+
+// clone returns deep copy of v, so callers of in-memory storage never share
+// slices and maps with stored records, the same way bolt storage decodes a fresh copy.
+func clone[T any](v T) (T, error) {
+	var c T
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return c, fmt.Errorf("marshal: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return c, nil
+}
+
+type memRepository[T any] struct {
+	mu sync.RWMutex
+	db map[string]T
+}
+
+func newMemRepository[T any]() *memRepository[T] {
+	return &memRepository[T]{
+		mu: sync.RWMutex{},
+		db: make(map[string]T),
+	}
+}
+
+func (m *memRepository[T]) Create(id string, v T) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.db[id]; ok {
+		return ErrAlreadyExists
+	}
+
+	c, err := clone(v)
+	if err != nil {
+		return err
+	}
+
+	m.db[id] = c
+
+	return nil
+}
+
+func (m *memRepository[T]) List() ([]T, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	resp := make([]T, 0, len(m.db))
+
+	for _, v := range m.db {
+		c, err := clone(v)
+		if err != nil {
+			return nil, err
+		}
+
+		resp = append(resp, c)
+	}
+
+	return resp, nil
+}
+
+func (m *memRepository[T]) Get(id string) (T, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.db[id]
+	if !ok {
+		var zero T
+
+		return zero, ErrNotFound
+	}
+
+	return clone(v)
+}
+
+func (m *memRepository[T]) Update(id string, v T) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.db[id]; !ok {
+		return ErrNotFound
+	}
+
+	c, err := clone(v)
+	if err != nil {
+		return err
+	}
+
+	m.db[id] = c
+
+	return nil
+}
+
+func (m *memRepository[T]) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.db[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(m.db, id)
+
+	return nil
+}
+
+// boltRepository keeps JSON encoded records in a bucket.
+type boltRepository[T any] struct {
+	db     *bolt.DB
+	bucket []byte
+}
+
+func newBoltRepository[T any](db *bolt.DB, bucket []byte) *boltRepository[T] {
+	return &boltRepository[T]{
+		db:     db,
+		bucket: bucket,
+	}
+}
+
+func (b *boltRepository[T]) put(id string, v T, exists bool) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", b.bucket, err)
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(b.bucket)
+
+		found := bkt.Get([]byte(id)) != nil
+
+		switch {
+		case exists && !found:
+			return ErrNotFound
+		case !exists && found:
+			return ErrAlreadyExists
+		}
+
+		return bkt.Put([]byte(id), data)
+	})
+}
+
+func (b *boltRepository[T]) Create(id string, v T) error {
+	return b.put(id, v, false)
+}
+
+func (b *boltRepository[T]) Update(id string, v T) error {
+	return b.put(id, v, true)
+}
+
+func (b *boltRepository[T]) List() ([]T, error) {
+	resp := make([]T, 0)
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(b.bucket).ForEach(func(_, data []byte) error {
+			var v T
+
+			if err := json.Unmarshal(data, &v); err != nil {
+				return fmt.Errorf("unmarshal %s: %w", b.bucket, err)
+			}
+
+			resp = append(resp, v)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (b *boltRepository[T]) Get(id string) (T, error) {
+	var v T
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(b.bucket).Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("unmarshal %s: %w", b.bucket, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return v, nil
+}
+
+func (b *boltRepository[T]) Delete(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(b.bucket)
+
+		if bkt.Get([]byte(id)) == nil {
+			return ErrNotFound
+		}
+
+		return bkt.Delete([]byte(id))
+	})
+}
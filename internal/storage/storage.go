@@ -3,31 +3,110 @@
 import (
 	"errors"
 	"sync"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/auth"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/campaign"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/chase"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/combat"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/creation"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/handouts"
 )
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrConflict is returned by Update when stored version differs from the updated one.
+	ErrConflict = errors.New("version conflict")
+)
 
 type Storage interface {
+	// Create stores new character. Zero version is set to InitialVersion.
 	Create(character Character) error
 	List() ([]Character, error)
+	// Count returns number of stored characters without reading them.
+	Count() (int, error)
 	Get(id string) (Character, error)
+	// Update replaces stored character if its version matches character.Version
+	// and returns stored character with incremented version.
+	Update(character Character) (Character, error)
 	Delete(id string) error
+	Handouts() Repository[handouts.Handout]
+	// Drafts stores characters under construction in the creation wizard.
+	Drafts() Repository[creation.Draft]
+	// Encounters stores combats tracked by the Keeper.
+	Encounters() Repository[combat.Encounter]
+	// Chases stores chases tracked by the Keeper.
+	Chases() Repository[chase.Chase]
+	// Campaigns stores campaigns grouping investigators and NPCs.
+	Campaigns() Repository[campaign.Campaign]
+	// Users stores local user accounts.
+	Users() Repository[auth.User]
+	// Sessions stores login sessions by hash of the session token.
+	Sessions() Repository[auth.Session]
+	// Ping checks that storage is available.
+	Ping() error
+	Close() error
 }
 
 type inMemoryStorage struct {
 	sync.RWMutex
-	db map[string]Character
+	db         map[string]Character
+	handouts   *memRepository[handouts.Handout]
+	drafts     *memRepository[creation.Draft]
+	encounters *memRepository[combat.Encounter]
+	chases     *memRepository[chase.Chase]
+	campaigns  *memRepository[campaign.Campaign]
+	users      *memRepository[auth.User]
+	sessions   *memRepository[auth.Session]
 }
 
 func (i *inMemoryStorage) Create(character Character) error {
 	i.Lock()
 	defer i.Unlock()
 
-	i.db[character.ID] = character
+	if _, ok := i.db[character.ID]; ok {
+		return ErrAlreadyExists
+	}
+
+	if character.Version == 0 {
+		character.Version = InitialVersion
+	}
+
+	c, err := clone(character)
+	if err != nil {
+		return err
+	}
+
+	i.db[character.ID] = c
 
 	return nil
 }
 
+func (i *inMemoryStorage) Update(character Character) (Character, error) {
+	i.Lock()
+	defer i.Unlock()
+
+	stored, ok := i.db[character.ID]
+	if !ok {
+		return Character{}, ErrNotFound
+	}
+
+	if stored.Version != character.Version {
+		return Character{}, ErrConflict
+	}
+
+	character.Version++
+
+	c, err := clone(character)
+	if err != nil {
+		return Character{}, err
+	}
+
+	i.db[character.ID] = c
+
+	return character, nil
+}
+
 func (i *inMemoryStorage) List() ([]Character, error) {
 	i.RLock()
 	defer i.RUnlock()
@@ -35,12 +114,24 @@ func (i *inMemoryStorage) List() ([]Character, error) {
 	resp := make([]Character, 0, len(i.db))
 
 	for _, v := range i.db {
-		resp = append(resp, v)
+		c, err := clone(v)
+		if err != nil {
+			return nil, err
+		}
+
+		resp = append(resp, c)
 	}
 
 	return resp, nil
 }
 
+func (i *inMemoryStorage) Count() (int, error) {
+	i.RLock()
+	defer i.RUnlock()
+
+	return len(i.db), nil
+}
+
 func (i *inMemoryStorage) Get(id string) (Character, error) {
 	i.RLock()
 	defer i.RUnlock()
@@ -50,7 +141,7 @@ func (i *inMemoryStorage) Get(id string) (Character, error) {
 		return Character{}, ErrNotFound
 	}
 
-	return c, nil
+	return clone(c)
 }
 
 func (i *inMemoryStorage) Delete(id string) error {
@@ -66,9 +157,52 @@ func (i *inMemoryStorage) Delete(id string) error {
 	return nil
 }
 
+func (i *inMemoryStorage) Handouts() Repository[handouts.Handout] {
+	return i.handouts
+}
+
+func (i *inMemoryStorage) Drafts() Repository[creation.Draft] {
+	return i.drafts
+}
+
+func (i *inMemoryStorage) Encounters() Repository[combat.Encounter] {
+	return i.encounters
+}
+
+func (i *inMemoryStorage) Chases() Repository[chase.Chase] {
+	return i.chases
+}
+
+func (i *inMemoryStorage) Campaigns() Repository[campaign.Campaign] {
+	return i.campaigns
+}
+
+func (i *inMemoryStorage) Users() Repository[auth.User] {
+	return i.users
+}
+
+func (i *inMemoryStorage) Sessions() Repository[auth.Session] {
+	return i.sessions
+}
+
+func (i *inMemoryStorage) Ping() error {
+	return nil
+}
+
+func (i *inMemoryStorage) Close() error {
+	return nil
+}
+
 func NewInMemoryStorage() Storage {
 	return &inMemoryStorage{
-		RWMutex: sync.RWMutex{},
-		db:      make(map[string]Character),
+		RWMutex:    sync.RWMutex{},
+		db:         make(map[string]Character),
+		handouts:   newMemRepository[handouts.Handout](),
+		drafts:     newMemRepository[creation.Draft](),
+		encounters: newMemRepository[combat.Encounter](),
+		chases:     newMemRepository[chase.Chase](),
+		campaigns:  newMemRepository[campaign.Campaign](),
+		users:      newMemRepository[auth.User](),
+		sessions:   newMemRepository[auth.Session](),
 	}
 }
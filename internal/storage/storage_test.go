@@ -0,0 +1,326 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/handouts"
+)
+
+const testID = "b2a3c2e4-6a4e-4e0e-9b5a-7f2b1c0d9e8f"
+
+type storageFactory func(tb testing.TB) Storage
+
+func backends() map[string]storageFactory {
+	return map[string]storageFactory{
+		"memory": func(tb testing.TB) Storage {
+			tb.Helper()
+
+			return NewInMemoryStorage()
+		},
+		"bolt": func(tb testing.TB) Storage {
+			tb.Helper()
+
+			db, err := NewBoltStorage(filepath.Join(tb.TempDir(), "test.db"))
+			require.NoError(tb, err)
+
+			tb.Cleanup(func() {
+				require.NoError(tb, db.Close())
+			})
+
+			return db
+		},
+	}
+}
+
+func testInvestigator(tb testing.TB) character.Investigator {
+	tb.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "character", "testdata", "character.json"))
+	require.NoError(tb, err)
+
+	investigator, err := character.UnmarshalInvestigator(data)
+	require.NoError(tb, err)
+
+	return investigator
+}
+
+func TestStorage_RoundTrip(t *testing.T) {
+	for name, newStorage := range backends() {
+		t.Run(name, func(t *testing.T) {
+			investigator := testInvestigator(t)
+
+			db := newStorage(t)
+
+			ch := Character{
+				ID:           testID,
+				Version:      InitialVersion,
+				Investigator: investigator.Investigator,
+			}
+
+			require.NoError(t, db.Create(ch))
+			require.ErrorIs(t, db.Create(ch), ErrAlreadyExists)
+
+			got, err := db.Get(ch.ID)
+			require.NoError(t, err)
+
+			assert.Equal(t, ch, got)
+			assert.Equal(t, "Ричард Смит", got.Name())
+			assert.Equal(t, "Private Investigator", got.Occupation())
+			assert.Equal(t, "34", got.Age())
+
+			exported := character.Investigator{Investigator: got.Investigator}
+
+			out, err := exported.Marshal()
+			require.NoError(t, err)
+
+			roundTrip, err := character.UnmarshalInvestigator(out)
+			require.NoError(t, err)
+
+			assert.Equal(t, investigator, roundTrip)
+
+			list, err := db.List()
+			require.NoError(t, err)
+			assert.Equal(t, []Character{ch}, list)
+
+			count, err := db.Count()
+			require.NoError(t, err)
+			assert.Equal(t, 1, count)
+
+			require.NoError(t, db.Delete(ch.ID))
+
+			_, err = db.Get(ch.ID)
+			require.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestStorage_NotFound(t *testing.T) {
+	for name, newStorage := range backends() {
+		t.Run(name, func(t *testing.T) {
+			db := newStorage(t)
+
+			_, err := db.Get("missing")
+			require.ErrorIs(t, err, ErrNotFound)
+
+			require.ErrorIs(t, db.Delete("missing"), ErrNotFound)
+		})
+	}
+}
+
+func TestStorage_Update(t *testing.T) {
+	for name, newStorage := range backends() {
+		t.Run(name, func(t *testing.T) {
+			db := newStorage(t)
+
+			ch := Character{
+				ID:           testID,
+				Investigator: testInvestigator(t).Investigator,
+			}
+
+			_, err := db.Update(ch)
+			require.ErrorIs(t, err, ErrNotFound)
+
+			require.NoError(t, db.Create(ch))
+
+			stored, err := db.Get(testID)
+			require.NoError(t, err)
+			require.Equal(t, InitialVersion, stored.Version)
+
+			first := stored
+			first.Investigator.PersonalDetails.Name = "Richard Smith"
+
+			second := stored
+			second.Investigator.PersonalDetails.Name = "Dick Smith"
+
+			updated, err := db.Update(first)
+			require.NoError(t, err)
+			assert.Equal(t, InitialVersion+1, updated.Version)
+			assert.Equal(t, "Richard Smith", updated.Name())
+
+			_, err = db.Update(second)
+			require.ErrorIs(t, err, ErrConflict)
+
+			got, err := db.Get(testID)
+			require.NoError(t, err)
+			assert.Equal(t, updated, got)
+		})
+	}
+}
+
+func TestStorage_UpdateConflictKeepsStored(t *testing.T) {
+	for name, newStorage := range backends() {
+		t.Run(name, func(t *testing.T) {
+			db := newStorage(t)
+
+			ch := Character{
+				ID:           testID,
+				Investigator: testInvestigator(t).Investigator,
+			}
+
+			require.NoError(t, db.Create(ch))
+
+			stale, err := db.Get(testID)
+			require.NoError(t, err)
+
+			fresh, err := db.Get(testID)
+			require.NoError(t, err)
+
+			_, err = db.Update(fresh)
+			require.NoError(t, err)
+
+			require.NotEmpty(t, stale.Investigator.Skills.Skill)
+			want := stale.Investigator.Skills.Skill[0].Value
+			stale.Investigator.Skills.Skill[0].Value = "99"
+
+			_, err = db.Update(stale)
+			require.ErrorIs(t, err, ErrConflict)
+
+			got, err := db.Get(testID)
+			require.NoError(t, err)
+			assert.Equal(t, InitialVersion+1, got.Version)
+			assert.Equal(t, want, got.Investigator.Skills.Skill[0].Value, "rejected update leaked into storage")
+		})
+	}
+}
+
+func TestStorage_Handouts(t *testing.T) {
+	for name, newStorage := range backends() {
+		t.Run(name, func(t *testing.T) {
+			repo := newStorage(t).Handouts()
+
+			h := handouts.Handout{
+				ID:        testID,
+				Kind:      handouts.KindTelegram,
+				Body:      "Come at once.",
+				CreatedAt: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+			}
+
+			require.NoError(t, repo.Create(h.ID, h))
+			require.ErrorIs(t, repo.Create(h.ID, h), ErrAlreadyExists)
+
+			got, err := repo.Get(h.ID)
+			require.NoError(t, err)
+			assert.Equal(t, h, got)
+
+			h.Body = "Do not come."
+			require.NoError(t, repo.Update(h.ID, h))
+
+			list, err := repo.List()
+			require.NoError(t, err)
+			assert.Equal(t, []handouts.Handout{h}, list)
+
+			require.NoError(t, repo.Delete(h.ID))
+			require.ErrorIs(t, repo.Delete(h.ID), ErrNotFound)
+			require.ErrorIs(t, repo.Update(h.ID, h), ErrNotFound)
+
+			_, err = repo.Get(h.ID)
+			require.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestBoltStorage_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "test.db")
+
+	db, err := NewBoltStorage(path)
+	require.NoError(t, err)
+
+	ch := Character{
+		ID:           testID,
+		Version:      InitialVersion,
+		Investigator: testInvestigator(t).Investigator,
+	}
+
+	require.NoError(t, db.Create(ch))
+	require.NoError(t, db.Close())
+
+	db, err = NewBoltStorage(path)
+	require.NoError(t, err)
+
+	got, err := db.Get(testID)
+	require.NoError(t, err)
+	assert.Equal(t, ch, got)
+
+	require.NoError(t, db.Close())
+}
+
+func TestBoltStorage_Ping(t *testing.T) {
+	db, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+
+	require.NoError(t, db.Ping())
+	require.NoError(t, db.Close())
+	require.Error(t, db.Ping())
+}
+
+func TestBoltStorage_NewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	raw, err := bolt.Open(path, boltFileMode, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, raw.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucket(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		return b.Put(schemaVersionKey, []byte{0, 0, 0, 0, 0, 0, 0, 100})
+	}))
+	require.NoError(t, raw.Close())
+
+	_, err = NewBoltStorage(path)
+	require.Error(t, err)
+}
+
+func TestBoltStorage_VersionMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	raw, err := bolt.Open(path, boltFileMode, nil)
+	require.NoError(t, err)
+
+	// Schema 7 kept characters without version.
+	require.NoError(t, raw.Update(func(tx *bolt.Tx) error {
+		for _, m := range migrations[:7] {
+			if err := m(tx); err != nil {
+				return err
+			}
+		}
+
+		meta, err := tx.CreateBucket(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		if err = meta.Put(schemaVersionKey, []byte{0, 0, 0, 0, 0, 0, 0, 7}); err != nil {
+			return err
+		}
+
+		return tx.Bucket(charactersBucket).Put([]byte(testID), []byte(`{"id":"`+testID+`","owner":"keeper"}`))
+	}))
+	require.NoError(t, raw.Close())
+
+	db, err := NewBoltStorage(path)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	got, err := db.Get(testID)
+	require.NoError(t, err)
+	assert.Equal(t, InitialVersion, got.Version)
+	assert.Equal(t, "keeper", got.Owner)
+
+	updated, err := db.Update(got)
+	require.NoError(t, err)
+	assert.Equal(t, InitialVersion+1, updated.Version)
+}
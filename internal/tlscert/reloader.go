@@ -0,0 +1,56 @@
+// Package tlscert keeps TLS certificate loaded from files and reloads it on demand without restarting the server.
+package tlscert
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync"
+)
+
+// Reloader holds certificate and key pair loaded from files.
+type Reloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewReloader loads certificate and key pair from files.
+func NewReloader(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload loads certificate and key pair from files again.
+// Current certificate is kept when files could not be loaded.
+func (r *Reloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate %q with key %q: %w", r.certFile, r.keyFile, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cert = &cert
+
+	return nil
+}
+
+// GetCertificate returns current certificate, it is used as tls.Config.GetCertificate,
+// so new connections get reloaded certificate while existing ones keep working.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
@@ -0,0 +1,86 @@
+package tlscert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCert writes self-signed certificate for the host name and its key to the dir.
+func writeCert(tb testing.TB, dir, host string) (string, string) {
+	tb.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(tb, err)
+
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	require.NoError(tb, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(tb, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	require.NoError(tb, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(tb, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+func commonName(tb testing.TB, r *Reloader) string {
+	tb.Helper()
+
+	cert, err := r.GetCertificate(nil)
+	require.NoError(tb, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(tb, err)
+
+	return leaf.Subject.CommonName
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+
+	certFile, keyFile := writeCert(t, dir, "old.example.com")
+
+	r, err := NewReloader(certFile, keyFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, "old.example.com", commonName(t, r))
+
+	writeCert(t, dir, "new.example.com")
+
+	require.NoError(t, r.Reload())
+	assert.Equal(t, "new.example.com", commonName(t, r))
+
+	require.NoError(t, os.WriteFile(certFile, []byte("broken"), 0o600))
+
+	require.Error(t, r.Reload())
+	assert.Equal(t, "new.example.com", commonName(t, r), "failed reload keeps current certificate")
+}
+
+func TestNewReloader_MissingFiles(t *testing.T) {
+	_, err := NewReloader(filepath.Join(t.TempDir(), "cert.pem"), "key.pem")
+	require.Error(t, err)
+}
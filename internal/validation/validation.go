@@ -0,0 +1,264 @@
+// Package validation checks investigator sheets against Call of Cthulhu 7e rules.
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/occupation"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/skills"
+)
+
+// Severity of a finding.
+type Severity string
+
+const (
+	// SeverityError marks values that break the rules or the sheet consistency.
+	SeverityError Severity = "error"
+	// SeverityWarning marks unusual values that are still possible, e.g. after Keeper decisions.
+	SeverityWarning Severity = "warning"
+)
+
+const (
+	minCharacteristic = 15
+	maxCharacteristic = 90
+	maxValue          = 99
+)
+
+// Finding is a single validation result.
+type Finding struct {
+	Severity Severity `json:"severity"`
+	// Path is a path of the field in the sheet, e.g. "Skills.Spot Hidden.half".
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Report is a list of findings.
+type Report []Finding
+
+// HasErrors reports whether report contains findings with SeverityError.
+func (r Report) HasErrors() bool {
+	for _, f := range r {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Count returns number of findings with the severity.
+func (r Report) Count(s Severity) int {
+	var n int
+
+	for _, f := range r {
+		if f.Severity == s {
+			n++
+		}
+	}
+
+	return n
+}
+
+type validator struct {
+	inv    character.InvestigatorClass
+	report Report
+}
+
+func (v *validator) add(s Severity, path, format string, args ...any) {
+	v.report = append(v.report, Finding{
+		Severity: s,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) addMismatches(s Severity, mismatches []character.Mismatch) {
+	for _, m := range mismatches {
+		v.add(s, m.Field, "stored %q, expected %q", m.Stored, m.Expected)
+	}
+}
+
+// Validate checks investigator and returns findings. Empty report means the sheet follows the rules.
+func Validate(inv character.InvestigatorClass) Report {
+	v := validator{inv: inv}
+
+	stats, err := inv.Characteristics.Stats()
+	if err != nil {
+		for _, e := range unwrapJoined(err) {
+			v.add(SeverityError, "Characteristics", "%v", e)
+		}
+
+		// Rest of the checks depend on characteristics.
+		return v.report
+	}
+
+	v.characteristics(stats)
+	v.derived()
+	v.current()
+	v.skills()
+	v.sanity()
+	v.occupation()
+
+	return v.report
+}
+
+func (v *validator) characteristics(s character.Stats) {
+	values := []struct {
+		name  string
+		value int
+	}{
+		{name: "STR", value: s.STR.Full},
+		{name: "CON", value: s.CON.Full},
+		{name: "SIZ", value: s.SIZ.Full},
+		{name: "DEX", value: s.DEX.Full},
+		{name: "APP", value: s.APP.Full},
+		{name: "INT", value: s.INT.Full},
+		{name: "POW", value: s.POW.Full},
+		{name: "EDU", value: s.EDU.Full},
+	}
+
+	for _, c := range values {
+		if c.value < minCharacteristic || c.value > maxCharacteristic {
+			v.add(SeverityWarning, "Characteristics."+c.name,
+				"%d is outside of %d-%d range of a starting investigator", c.value, minCharacteristic, maxCharacteristic)
+		}
+	}
+
+	if s.Luck.Full > maxValue {
+		v.add(SeverityError, "Characteristics.Luck", "%d is above %d", s.Luck.Full, maxValue)
+	}
+}
+
+func (v *validator) derived() {
+	mismatches, err := v.inv.DerivedMismatches()
+	if err != nil {
+		v.add(SeverityError, "PersonalDetails.Age", "%v", err)
+
+		return
+	}
+
+	v.addMismatches(SeverityError, mismatches)
+}
+
+// current checks that current values do not exceed their maximums.
+func (v *validator) current() {
+	c := v.inv.Characteristics
+
+	pairs := []struct {
+		path     string
+		current  string
+		maxPath  string
+		maxValue string
+	}{
+		{path: "Characteristics.HitPts", current: c.HitPts, maxPath: "HitPtsMax", maxValue: c.HitPtsMax},
+		{path: "Characteristics.MagicPts", current: c.MagicPts, maxPath: "MagicPtsMax", maxValue: c.MagicPtsMax},
+		{path: "Characteristics.Sanity", current: c.Sanity, maxPath: "SanityMax", maxValue: c.SanityMax},
+		{path: "Characteristics.Luck", current: c.Luck, maxPath: "LuckMax", maxValue: c.LuckMax},
+	}
+
+	for _, p := range pairs {
+		cur, err := character.ParseNumber(p.current)
+		if err != nil {
+			v.add(SeverityError, p.path, "%v", err)
+
+			continue
+		}
+
+		limit, err := character.ParseNumber(p.maxValue)
+		if err != nil {
+			v.add(SeverityError, "Characteristics."+p.maxPath, "%v", err)
+
+			continue
+		}
+
+		// Zero maximum means it is not filled in the sheet.
+		if limit != 0 && cur > limit {
+			v.add(SeverityError, p.path, "%d is above %s %d", cur, p.maxPath, limit)
+		}
+	}
+}
+
+func (v *validator) skills() {
+	for _, s := range v.inv.Skills.Skill {
+		path := "Skills." + s.FullName()
+
+		value, err := s.Values()
+		if err != nil {
+			v.add(SeverityError, path+".value", "%v", err)
+
+			continue
+		}
+
+		if value.Full > maxValue {
+			v.add(SeverityError, path+".value", "%d is above %d", value.Full, maxValue)
+		}
+
+		if half, err := character.ParseNumber(s.Half); err != nil || half != value.Half {
+			v.add(SeverityError, path+".half", "stored %q, expected %d", s.Half, value.Half)
+		}
+
+		if fifth, err := character.ParseNumber(s.Fifth); err != nil || fifth != value.Fifth {
+			v.add(SeverityError, path+".fifth", "stored %q, expected %d", s.Fifth, value.Fifth)
+		}
+	}
+
+	mismatches, err := skills.Check(v.inv)
+	if err != nil {
+		// Values errors are already reported above.
+		return
+	}
+
+	v.addMismatches(SeverityWarning, mismatches)
+}
+
+// sanity checks that Cthulhu Mythos knowledge leaves room for the current Sanity: Sanity <= 99 - Cthulhu Mythos.
+func (v *validator) sanity() {
+	sanity, err := character.ParseNumber(v.inv.Characteristics.Sanity)
+	if err != nil {
+		return
+	}
+
+	var mythos int
+
+	for _, s := range v.inv.Skills.Skill {
+		if !strings.EqualFold(s.Name, skills.CthulhuMythos) {
+			continue
+		}
+
+		value, err := s.Values()
+		if err != nil {
+			return
+		}
+
+		mythos = value.Full
+	}
+
+	if mythos > maxValue-sanity {
+		v.add(SeverityError, "Skills."+skills.CthulhuMythos+".value",
+			"%d is above 99 - Sanity (%d)", mythos, maxValue-sanity)
+	}
+}
+
+func (v *validator) occupation() {
+	o, err := occupation.Find(v.inv.PersonalDetails.Occupation)
+	if err != nil {
+		return
+	}
+
+	mismatches, err := o.Mismatches(v.inv)
+	if err != nil {
+		return
+	}
+
+	v.addMismatches(SeverityWarning, mismatches)
+}
+
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+
+	return []error{err}
+}
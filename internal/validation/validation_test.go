@@ -0,0 +1,120 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character"
+	"github.com/obalunenko/cthulhu-mythos-tools/internal/character/charactertest"
+)
+
+func paths(r Report) map[string]Severity {
+	res := make(map[string]Severity, len(r))
+
+	for _, f := range r {
+		res[f.Path] = f.Severity
+	}
+
+	return res
+}
+
+func TestValidate_Valid(t *testing.T) {
+	r := Validate(charactertest.LoadInvestigator(t))
+
+	assert.Empty(t, r)
+	assert.False(t, r.HasErrors())
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(inv *character.InvestigatorClass)
+		want   map[string]Severity
+	}{
+		{
+			name: "characteristic out of range",
+			modify: func(inv *character.InvestigatorClass) {
+				inv.Characteristics.App = "95"
+			},
+			want: map[string]Severity{"Characteristics.APP": SeverityWarning},
+		},
+		{
+			name: "unparsable characteristic",
+			modify: func(inv *character.InvestigatorClass) {
+				inv.Characteristics.Str = "strong"
+			},
+			want: map[string]Severity{"Characteristics": SeverityError},
+		},
+		{
+			name: "wrong hit points",
+			modify: func(inv *character.InvestigatorClass) {
+				inv.Characteristics.HitPtsMax = "12"
+				inv.Characteristics.MagicPtsMax = "9"
+			},
+			want: map[string]Severity{
+				"Characteristics.HitPtsMax":   SeverityError,
+				"Characteristics.MagicPtsMax": SeverityError,
+			},
+		},
+		{
+			name: "current above maximum",
+			modify: func(inv *character.InvestigatorClass) {
+				inv.Characteristics.HitPts = "11"
+			},
+			want: map[string]Severity{"Characteristics.HitPts": SeverityError},
+		},
+		{
+			name: "skill values",
+			modify: func(inv *character.InvestigatorClass) {
+				for i, s := range inv.Skills.Skill {
+					switch s.Name {
+					case "Spot Hidden":
+						inv.Skills.Skill[i].Half = "31"
+					case "Listen":
+						inv.Skills.Skill[i].SkillValues = character.SkillValues{Value: "100", Half: "50", Fifth: "20"}
+					}
+				}
+			},
+			want: map[string]Severity{
+				"Skills.Spot Hidden.half": SeverityError,
+				"Skills.Listen.value":     SeverityError,
+			},
+		},
+		{
+			name: "cthulhu mythos above sanity limit",
+			modify: func(inv *character.InvestigatorClass) {
+				for i, s := range inv.Skills.Skill {
+					if s.Name == "Cthulhu Mythos" {
+						inv.Skills.Skill[i].SkillValues = character.SkillValues{Value: "60", Half: "30", Fifth: "12"}
+					}
+				}
+			},
+			want: map[string]Severity{"Skills.Cthulhu Mythos.value": SeverityError},
+		},
+		{
+			name: "credit rating outside occupation range",
+			modify: func(inv *character.InvestigatorClass) {
+				for i, s := range inv.Skills.Skill {
+					if s.Name == "Credit Rating" {
+						inv.Skills.Skill[i].SkillValues = character.SkillValues{Value: "50", Half: "25", Fifth: "10"}
+					}
+				}
+			},
+			want: map[string]Severity{"Skills.Credit Rating": SeverityWarning},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := charactertest.LoadInvestigator(t)
+
+			tt.modify(&inv)
+
+			r := Validate(inv)
+
+			assert.Equal(t, tt.want, paths(r))
+			assert.Equal(t, len(tt.want), len(r))
+		})
+	}
+}